@@ -0,0 +1,175 @@
+package alert
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// Alert is raised when a battery record breaks a Rule.
+type Alert struct {
+	Rule     string
+	TenantID string
+	Serial   string
+	Battery  uint8
+	State    drone.State
+	Message  string
+	At       time.Time
+}
+
+// Rule evaluates the battery records of a drone.
+type Rule interface {
+	// Name identifies the Rule (used to de-duplicate its alerts).
+	Name() string
+	// Evaluate returns the alert message if curr breaks the Rule.
+	// NOTE: prev is the previous record of the same drone (nil on the first tick).
+	Evaluate(prev *drone.BatteryRecord, curr drone.BatteryRecord) (string, bool)
+}
+
+// Threshold fires when the battery drops under Below.
+type Threshold struct {
+	Below uint8
+}
+
+func (r Threshold) Name() string { return fmt.Sprintf("battery_below_%d", r.Below) }
+
+func (r Threshold) Evaluate(_ *drone.BatteryRecord, curr drone.BatteryRecord) (string, bool) {
+	if curr.Battery >= r.Below {
+		return "", false
+	}
+
+	return fmt.Sprintf("battery level %d%% is under %d%%", curr.Battery, r.Below), true
+}
+
+// DrainRate fires when the battery drains faster than MaxPerMinute (percentage points per minute).
+type DrainRate struct {
+	MaxPerMinute float64
+}
+
+func (r DrainRate) Name() string { return fmt.Sprintf("drain_rate_over_%g", r.MaxPerMinute) }
+
+func (r DrainRate) Evaluate(prev *drone.BatteryRecord, curr drone.BatteryRecord) (string, bool) {
+	if prev == nil || curr.Battery >= prev.Battery {
+		return "", false
+	}
+
+	minutes := curr.At.Sub(prev.At).Minutes()
+	if minutes <= 0 {
+		return "", false
+	}
+
+	rate := float64(prev.Battery-curr.Battery) / minutes
+	if rate <= r.MaxPerMinute {
+		return "", false
+	}
+
+	return fmt.Sprintf("battery drains %.2f%%/min (max %g%%/min)", rate, r.MaxPerMinute), true
+}
+
+// DeliveringBelow fires when a drone in the Delivering state has less battery than Below.
+type DeliveringBelow struct {
+	Below uint8
+}
+
+func (r DeliveringBelow) Name() string { return fmt.Sprintf("delivering_below_%d", r.Below) }
+
+func (r DeliveringBelow) Evaluate(_ *drone.BatteryRecord, curr drone.BatteryRecord) (string, bool) {
+	if curr.State != drone.Delivering || curr.Battery >= r.Below {
+		return "", false
+	}
+
+	return fmt.Sprintf("delivering with battery level %d%% (under %d%%)", curr.Battery, r.Below), true
+}
+
+// alertKey identifies the alerts of a Rule for a drone.
+type alertKey struct {
+	rule     string
+	tenantID string
+	serial   string
+}
+
+// droneKey identifies a drone inside its tenant.
+type droneKey struct {
+	tenantID string
+	serial   string
+}
+
+// Evaluator evaluates the Rules on each tick, de-duplicating the alerts: a
+// Rule alerts when it starts firing for a drone and, while it keeps firing,
+// again after every Cooldown (zero never repeats it).
+type Evaluator struct {
+	rules    []Rule
+	cooldown time.Duration
+
+	mu         sync.Mutex
+	last       map[droneKey]drone.BatteryRecord
+	notifiedAt map[alertKey]time.Time
+}
+
+// NewEvaluator builds an Evaluator of the given rules.
+func NewEvaluator(cooldown time.Duration, rules ...Rule) *Evaluator {
+	return &Evaluator{
+		rules:      rules,
+		cooldown:   cooldown,
+		last:       make(map[droneKey]drone.BatteryRecord),
+		notifiedAt: make(map[alertKey]time.Time),
+	}
+}
+
+// Evaluate returns the new alerts raised by the records of a tick. They are
+// raised again on the next tick until marked as Notified.
+func (e *Evaluator) Evaluate(records []drone.BatteryRecord) []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var alerts []Alert
+	for _, curr := range records {
+		dk := droneKey{tenantID: curr.TenantID, serial: curr.Serial}
+		var prev *drone.BatteryRecord
+		if p, ok := e.last[dk]; ok {
+			prev = &p
+		}
+
+		e.last[dk] = curr
+		for _, r := range e.rules {
+			ak := alertKey{rule: r.Name(), tenantID: curr.TenantID, serial: curr.Serial}
+			msg, firing := r.Evaluate(prev, curr)
+			if !firing {
+				delete(e.notifiedAt, ak)
+				continue
+			}
+
+			if at, ok := e.notifiedAt[ak]; ok && (e.cooldown <= 0 || curr.At.Sub(at) < e.cooldown) {
+				continue
+			}
+
+			alerts = append(alerts, Alert{
+				Rule:     r.Name(),
+				TenantID: curr.TenantID,
+				Serial:   curr.Serial,
+				Battery:  curr.Battery,
+				State:    curr.State,
+				Message:  msg,
+				At:       curr.At,
+			})
+		}
+	}
+
+	return alerts
+}
+
+// Notified starts the Cooldown of the alerts, call it once they are delivered.
+// NOTE: the alerts of a tick older than the last one are ignored, their rule may have stopped firing since.
+func (e *Evaluator) Notified(alerts []Alert) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, a := range alerts {
+		dk := droneKey{tenantID: a.TenantID, serial: a.Serial}
+		if last, ok := e.last[dk]; ok && last.At.After(a.At) {
+			continue
+		}
+
+		e.notifiedAt[alertKey{rule: a.Rule, tenantID: a.TenantID, serial: a.Serial}] = a.At
+	}
+}
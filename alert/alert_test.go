@@ -0,0 +1,97 @@
+package alert_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hsequeda/drone/alert"
+	"github.com/hsequeda/drone/drone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRules(t *testing.T) {
+	at := time.Date(2023, time.January, 10, 12, 0, 0, 0, time.UTC)
+	prev := drone.BatteryRecord{At: at, Battery: 50, State: drone.Delivering}
+	testCases := []struct {
+		name     string
+		rule     alert.Rule
+		prev     *drone.BatteryRecord
+		curr     drone.BatteryRecord
+		expected bool
+	}{
+		{
+			name:     "Threshold: firing",
+			rule:     alert.Threshold{Below: 20},
+			curr:     drone.BatteryRecord{At: at, Battery: 19},
+			expected: true,
+		},
+		{
+			name: "Threshold: not firing",
+			rule: alert.Threshold{Below: 20},
+			curr: drone.BatteryRecord{At: at, Battery: 20},
+		},
+		{
+			name:     "DrainRate: firing",
+			rule:     alert.DrainRate{MaxPerMinute: 2},
+			prev:     &prev,
+			curr:     drone.BatteryRecord{At: at.Add(time.Minute), Battery: 47},
+			expected: true,
+		},
+		{
+			name: "DrainRate: not firing",
+			rule: alert.DrainRate{MaxPerMinute: 2},
+			prev: &prev,
+			curr: drone.BatteryRecord{At: at.Add(2 * time.Minute), Battery: 47},
+		},
+		{
+			name: "DrainRate: first record",
+			rule: alert.DrainRate{MaxPerMinute: 2},
+			curr: drone.BatteryRecord{At: at, Battery: 10},
+		},
+		{
+			name:     "DeliveringBelow: firing",
+			rule:     alert.DeliveringBelow{Below: 30},
+			curr:     drone.BatteryRecord{At: at, Battery: 29, State: drone.Delivering},
+			expected: true,
+		},
+		{
+			name: "DeliveringBelow: not delivering",
+			rule: alert.DeliveringBelow{Below: 30},
+			curr: drone.BatteryRecord{At: at, Battery: 29, State: drone.Idle},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			msg, firing := tc.rule.Evaluate(tc.prev, tc.curr)
+			assert.Equal(t, tc.expected, firing)
+			if tc.expected {
+				assert.NotEmpty(t, msg)
+			}
+		})
+	}
+}
+
+func TestEvaluatorDeduplication(t *testing.T) {
+	at := time.Date(2023, time.January, 10, 12, 0, 0, 0, time.UTC)
+	e := alert.NewEvaluator(time.Hour, alert.Threshold{Below: 20})
+	tick := func(minutes int, battery uint8) []alert.Alert {
+		alerts := e.Evaluate([]drone.BatteryRecord{
+			{At: at.Add(time.Duration(minutes) * time.Minute), TenantID: "hospital-a", Serial: "1", Battery: battery},
+			{At: at.Add(time.Duration(minutes) * time.Minute), TenantID: "hospital-a", Serial: "2", Battery: 90},
+		})
+		e.Notified(alerts)
+		return alerts
+	}
+
+	alerts := tick(0, 15)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "1", alerts[0].Serial)
+	assert.Equal(t, "hospital-a", alerts[0].TenantID)
+
+	assert.Empty(t, tick(10, 14))  // still firing, inside the cooldown
+	assert.Len(t, tick(60, 13), 1) // still firing, after the cooldown
+	assert.Empty(t, tick(70, 80))  // recovered
+	assert.Len(t, tick(80, 10), 1) // firing again
+}
@@ -0,0 +1,57 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// BatteryAudit records the battery of the fleet in the history and notifies
+// the alerts raised by the records.
+type BatteryAudit struct {
+	storage   drone.Storage
+	history   drone.BatteryHistory
+	evaluator *Evaluator
+	notifier  Notifier
+}
+
+// NewBatteryAudit builds a BatteryAudit, schedule its Run periodically.
+func NewBatteryAudit(storage drone.Storage, history drone.BatteryHistory, evaluator *Evaluator, notifier Notifier) *BatteryAudit {
+	return &BatteryAudit{
+		storage:   storage,
+		history:   history,
+		evaluator: evaluator,
+		notifier:  notifier,
+	}
+}
+
+// Run audits the battery of every drone once.
+func (a *BatteryAudit) Run(ctx context.Context) error {
+	drones, err := a.storage.AllDrones(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch drones: %w", err)
+	}
+
+	now := time.Now().UTC()
+	records := make([]drone.BatteryRecord, len(drones))
+	for i, d := range drones {
+		records[i] = drone.NewBatteryRecord(d, now)
+	}
+
+	if err := a.history.AppendBatteryRecords(ctx, records...); err != nil {
+		return fmt.Errorf("register battery levels: %w", err)
+	}
+
+	if alerts := a.evaluator.Evaluate(records); len(alerts) > 0 {
+		// NOTE: the alerts not delivered are raised again on the next run.
+		if err := a.notifier.Notify(ctx, alerts); err != nil {
+			return fmt.Errorf("notify alerts: %w", err)
+		}
+
+		a.evaluator.Notified(alerts)
+	}
+
+	return nil
+}
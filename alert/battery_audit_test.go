@@ -0,0 +1,85 @@
+package alert_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hsequeda/drone/alert"
+	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// notifierFunc is a Notifier calling the function.
+type notifierFunc func(ctx context.Context, alerts []alert.Alert) error
+
+func (f notifierFunc) Notify(ctx context.Context, alerts []alert.Alert) error {
+	return f(ctx, alerts)
+}
+
+func TestBatteryAudit(t *testing.T) {
+	ctx := context.Background()
+	st := storage.NewInMemory()
+	for _, d := range []drone.Drone{
+		{TenantID: "hospital-a", Serial: "1", Model: drone.Lightweight, WeightLimit: 300, BatteryCapacity: 15, State: drone.Delivering},
+		{TenantID: "hospital-b", Serial: "2", Model: drone.Heavyweight, WeightLimit: 500, BatteryCapacity: 90, State: drone.Idle},
+	} {
+		require.NoError(t, st.SaveDrone(ctx, d))
+	}
+
+	history := storage.NewBatteryHistoryFile(t.TempDir(), storage.BatteryHistoryOptions{})
+	var notified []alert.Alert
+	audit := alert.NewBatteryAudit(st, history, alert.NewEvaluator(0, alert.Threshold{Below: 20}), notifierFunc(func(_ context.Context, alerts []alert.Alert) error {
+		notified = append(notified, alerts...)
+		return nil
+	}))
+
+	require.NoError(t, audit.Run(ctx))
+	require.NoError(t, audit.Run(ctx))
+
+	records, err := history.BatteryRecords(ctx, "hospital-a", "1", time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, uint8(15), records[0].Battery)
+
+	records, err = history.BatteryRecords(ctx, "hospital-b", "2", time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	require.Len(t, notified, 1)
+	assert.Equal(t, "hospital-a", notified[0].TenantID)
+	assert.Equal(t, "1", notified[0].Serial)
+}
+
+func TestBatteryAuditRetriesFailedNotifications(t *testing.T) {
+	ctx := context.Background()
+	st := storage.NewInMemory()
+	require.NoError(t, st.SaveDrone(ctx, drone.Drone{
+		TenantID: "hospital-a", Serial: "1", Model: drone.Lightweight, WeightLimit: 300, BatteryCapacity: 15, State: drone.Delivering,
+	}))
+
+	var (
+		fail     = true
+		notified []alert.Alert
+	)
+	audit := alert.NewBatteryAudit(st, storage.NewBatteryHistoryFile(t.TempDir(), storage.BatteryHistoryOptions{}), alert.NewEvaluator(time.Hour, alert.Threshold{Below: 20}), notifierFunc(func(_ context.Context, alerts []alert.Alert) error {
+		if fail {
+			return errors.New("smtp down")
+		}
+
+		notified = append(notified, alerts...)
+		return nil
+	}))
+
+	require.Error(t, audit.Run(ctx))
+	fail = false
+	require.NoError(t, audit.Run(ctx))
+	require.NoError(t, audit.Run(ctx))
+
+	// the failed alert isn't in the cooldown, and the delivered one is
+	require.Len(t, notified, 1)
+	assert.Equal(t, "1", notified[0].Serial)
+}
@@ -0,0 +1,128 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Notifier delivers the Alerts.
+type Notifier interface {
+	Notify(ctx context.Context, alerts []Alert) error
+}
+
+// Notifiers is a Notifier delivering the alerts through all of its Notifiers.
+type Notifiers []Notifier
+
+func (n Notifiers) Notify(ctx context.Context, alerts []Alert) error {
+	var errs []error
+	for _, notifier := range n {
+		if err := notifier.Notify(ctx, alerts); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// LogNotifier writes the alerts to a logger.
+type LogNotifier struct {
+	Logger *log.Logger
+}
+
+func (n LogNotifier) Notify(_ context.Context, alerts []Alert) error {
+	for _, a := range alerts {
+		n.Logger.Printf("ALERT %s tenant(%s)-serial(%s): %s", a.Rule, a.TenantID, a.Serial, a.Message)
+	}
+
+	return nil
+}
+
+// alertDTO is the JSON representation of an Alert sent by the WebhookNotifier.
+type alertDTO struct {
+	Rule     string    `json:"rule"`
+	TenantID string    `json:"tenant_id"`
+	Serial   string    `json:"serial"`
+	Battery  uint8     `json:"battery_level"`
+	State    int8      `json:"state"`
+	Message  string    `json:"message"`
+	At       time.Time `json:"at"`
+}
+
+// WebhookNotifier POSTs the alerts as a JSON array to URL.
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func (n WebhookNotifier) Notify(ctx context.Context, alerts []Alert) error {
+	dtos := make([]alertDTO, len(alerts))
+	for i, a := range alerts {
+		dtos[i] = alertDTO{
+			Rule:     a.Rule,
+			TenantID: a.TenantID,
+			Serial:   a.Serial,
+			Battery:  a.Battery,
+			State:    int8(a.State),
+			Message:  a.Message,
+			At:       a.At,
+		}
+	}
+
+	b, err := json.Marshal(dtos)
+	if err != nil {
+		return fmt.Errorf("encode alerts: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("build alert request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send alerts: %w", err)
+	}
+
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("send alerts: unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// SMTPNotifier sends the alerts by email.
+type SMTPNotifier struct {
+	// Addr is the host:port of the SMTP server.
+	Addr string
+	// Auth is optional (nil sends the mail without authentication).
+	Auth smtp.Auth
+	From string
+	To   []string
+}
+
+func (n SMTPNotifier) Notify(_ context.Context, alerts []Alert) error {
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\r\n", n.From)
+	fmt.Fprintf(&body, "To: %s\r\n", strings.Join(n.To, ", "))
+	fmt.Fprintf(&body, "Subject: [drone] %d battery alert(s)\r\n", len(alerts))
+	body.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	for _, a := range alerts {
+		fmt.Fprintf(&body, "%s %s tenant(%s)-serial(%s): %s\r\n", a.At.Format(time.RFC3339), a.Rule, a.TenantID, a.Serial, a.Message)
+	}
+
+	if err := smtp.SendMail(n.Addr, n.Auth, n.From, n.To, []byte(body.String())); err != nil {
+		return fmt.Errorf("send alerts mail: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package alert_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hsequeda/drone/alert"
+	"github.com/hsequeda/drone/drone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAlerts() []alert.Alert {
+	return []alert.Alert{{
+		Rule:     "battery_below_20",
+		TenantID: "hospital-a",
+		Serial:   "1",
+		Battery:  15,
+		State:    drone.Delivering,
+		Message:  "battery level 15% is under 20%",
+		At:       time.Date(2023, time.January, 10, 12, 0, 0, 0, time.UTC),
+	}}
+}
+
+func TestLogNotifier(t *testing.T) {
+	var buf strings.Builder
+	n := alert.LogNotifier{Logger: log.New(&buf, "", 0)}
+	require.NoError(t, n.Notify(context.Background(), testAlerts()))
+	assert.Contains(t, buf.String(), "tenant(hospital-a)-serial(1): battery level 15% is under 20%")
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	received := make(chan []map[string]any, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body []map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		received <- body
+	}))
+	t.Cleanup(receiver.Close)
+
+	n := alert.WebhookNotifier{URL: receiver.URL, Client: receiver.Client()}
+	require.NoError(t, n.Notify(context.Background(), testAlerts()))
+	body := <-received
+	require.Len(t, body, 1)
+	assert.Equal(t, "battery_below_20", body[0]["rule"])
+	assert.Equal(t, "1", body[0]["serial"])
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	addr, mails := startSMTPServer(t)
+	n := alert.SMTPNotifier{Addr: addr, From: "drones@hospital.local", To: []string{"ops@hospital.local"}}
+	require.NoError(t, n.Notify(context.Background(), testAlerts()))
+
+	select {
+	case mail := <-mails:
+		assert.Contains(t, mail, "Subject: [drone] 1 battery alert(s)")
+		assert.Contains(t, mail, "tenant(hospital-a)-serial(1): battery level 15% is under 20%")
+	case <-time.After(5 * time.Second):
+		t.Fatal("mail not received")
+	}
+}
+
+func TestNotifiers(t *testing.T) {
+	var buf strings.Builder
+	failing := alert.WebhookNotifier{URL: "http://127.0.0.1:0", Client: http.DefaultClient}
+	n := alert.Notifiers{failing, alert.LogNotifier{Logger: log.New(&buf, "", 0)}}
+	require.Error(t, n.Notify(context.Background(), testAlerts()))
+	assert.NotEmpty(t, buf.String()) // a failing notifier doesn't stop the rest
+}
+
+// startSMTPServer starts a minimal SMTP server that accepts every mail and
+// sends its DATA to the returned channel.
+func startSMTPServer(t *testing.T) (string, <-chan string) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	mails := make(chan string, 1)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			go serveSMTP(conn, mails)
+		}
+	}()
+
+	return l.Addr().String(), mails
+}
+
+func serveSMTP(conn net.Conn, mails chan<- string) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(s string) { _, _ = conn.Write([]byte(s + "\r\n")) }
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "DATA"):
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+
+				if l == ".\r\n" {
+					break
+				}
+
+				data.WriteString(l)
+			}
+
+			mails <- data.String()
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "QUIT"):
+			reply("221 bye")
+			return
+		default: // MAIL, RCPT, NOOP...
+			reply("250 OK")
+		}
+	}
+}
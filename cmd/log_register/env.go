@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hsequeda/drone/alert"
+	"github.com/hsequeda/drone/storage"
+)
+
+// alertsFromEnv builds the Evaluator and Notifiers from the (optional) ALERT_*
+// environment variables. Alerts are always written to the standard logger.
+func alertsFromEnv() (*alert.Evaluator, alert.Notifiers, error) {
+	var rules []alert.Rule
+	if v, ok := os.LookupEnv("ALERT_BATTERY_BELOW"); ok {
+		below, err := strconv.ParseUint(v, 10, 8)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ALERT_BATTERY_BELOW need to be a percentage")
+		}
+
+		rules = append(rules, alert.Threshold{Below: uint8(below)})
+	}
+
+	if v, ok := os.LookupEnv("ALERT_DRAIN_RATE_PER_MINUTE"); ok {
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ALERT_DRAIN_RATE_PER_MINUTE need to be a number")
+		}
+
+		rules = append(rules, alert.DrainRate{MaxPerMinute: rate})
+	}
+
+	if v, ok := os.LookupEnv("ALERT_DELIVERING_BELOW"); ok {
+		below, err := strconv.ParseUint(v, 10, 8)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ALERT_DELIVERING_BELOW need to be a percentage")
+		}
+
+		rules = append(rules, alert.DeliveringBelow{Below: uint8(below)})
+	}
+
+	cooldown := 30 * time.Minute
+	if v, ok := os.LookupEnv("ALERT_COOLDOWN_MINUTES"); ok {
+		minutes, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ALERT_COOLDOWN_MINUTES need to be integer")
+		}
+
+		cooldown = time.Duration(minutes) * time.Minute
+	}
+
+	notifiers := alert.Notifiers{alert.LogNotifier{Logger: log.Default()}}
+	if url, ok := os.LookupEnv("ALERT_WEBHOOK_URL"); ok {
+		notifiers = append(notifiers, alert.WebhookNotifier{URL: url, Client: &http.Client{Timeout: 10 * time.Second}})
+	}
+
+	if addr, ok := os.LookupEnv("ALERT_SMTP_ADDR"); ok {
+		n := alert.SMTPNotifier{
+			Addr: addr,
+			From: os.Getenv("ALERT_SMTP_FROM"),
+			To:   strings.Split(os.Getenv("ALERT_SMTP_TO"), ","),
+		}
+		if n.From == "" || os.Getenv("ALERT_SMTP_TO") == "" {
+			return nil, nil, fmt.Errorf("ALERT_SMTP_FROM and ALERT_SMTP_TO are required with ALERT_SMTP_ADDR")
+		}
+
+		if user, ok := os.LookupEnv("ALERT_SMTP_USERNAME"); ok {
+			host, _, _ := net.SplitHostPort(addr)
+			n.Auth = smtp.PlainAuth("", user, os.Getenv("ALERT_SMTP_PASSWORD"), host)
+		}
+
+		notifiers = append(notifiers, n)
+	}
+
+	return alert.NewEvaluator(cooldown, rules...), notifiers, nil
+}
+
+// batteryHistoryOptionsFromEnv reads the rotation and retention of the battery
+// history from the (optional) BATTERY_HISTORY_* environment variables.
+func batteryHistoryOptionsFromEnv() (storage.BatteryHistoryOptions, error) {
+	opts := storage.BatteryHistoryOptions{
+		MaxFileSize: 10 * (1024 * 1024),
+		MaxFileAge:  24 * time.Hour,
+		Retention:   30 * 24 * time.Hour,
+	}
+
+	for _, v := range []struct {
+		env  string
+		unit int64
+		dst  func(int64)
+	}{
+		{env: "BATTERY_HISTORY_MAX_FILE_SIZE", unit: 1024 * 1024, dst: func(n int64) { opts.MaxFileSize = n }},
+		{env: "BATTERY_HISTORY_ROTATION_HOURS", unit: int64(time.Hour), dst: func(n int64) { opts.MaxFileAge = time.Duration(n) }},
+		{env: "BATTERY_HISTORY_RETENTION_DAYS", unit: int64(24 * time.Hour), dst: func(n int64) { opts.Retention = time.Duration(n) }},
+	} {
+		s, ok := os.LookupEnv(v.env)
+		if !ok {
+			continue
+		}
+
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || n < 0 {
+			return storage.BatteryHistoryOptions{}, fmt.Errorf("%s need to be a positive integer", v.env)
+		}
+
+		v.dst(n * v.unit)
+	}
+
+	return opts, nil
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"errors"
 	"log"
 	"os"
 	"os/signal"
@@ -10,7 +9,7 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/alert"
 	"github.com/hsequeda/drone/storage"
 	"github.com/sdomino/scribble"
 )
@@ -28,13 +27,17 @@ func main() {
 		return
 	}
 
-	file, err := createFile()
+	historyOpts, err := batteryHistoryOptionsFromEnv()
 	if err != nil {
 		log.Fatal(err.Error())
 		return
 	}
 
-	defer file.Close()
+	evaluator, notifiers, err := alertsFromEnv()
+	if err != nil {
+		log.Fatal(err.Error())
+		return
+	}
 
 	pwd, _ := os.Getwd()
 	db, err := scribble.New(filepath.Join(pwd, "/data"), nil)
@@ -44,12 +47,13 @@ func main() {
 	}
 
 	st := storage.NewJSON(db)
+	history := storage.NewBatteryHistoryFile(filepath.Join(pwd, "/logs/battery"), historyOpts)
+	audit := alert.NewBatteryAudit(st, history, evaluator, notifiers)
 	log.SetFlags(log.Ldate | log.Ltime | log.Lmicroseconds)
-	log.SetOutput(file)
 	println("Registering Drones battery level")
 
 	ctx, _ := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
-	if err := execute(ctx, st); err != nil {
+	if err := audit.Run(ctx); err != nil {
 		println(err.Error())
 	}
 	exit := make(chan struct{})
@@ -58,7 +62,7 @@ func main() {
 		for {
 			select {
 			case <-time.Tick(time.Duration(interval) * time.Second):
-				if err := execute(ctx, st); err != nil {
+				if err := audit.Run(ctx); err != nil {
 					println(err.Error())
 				}
 			case <-ctx.Done():
@@ -71,33 +75,3 @@ func main() {
 	<-exit
 	println("server exited properly")
 }
-
-func execute(ctx context.Context, st drone.Storage) error {
-	drones, err := st.Drones(ctx)
-	if err != nil {
-		return errors.New("fetch error")
-	}
-
-	for _, d := range drones {
-		log.Printf("serial(%s)-battery_level: %d%%", d.Serial, d.BatteryCapacity)
-	}
-
-	return nil
-}
-
-func createFile() (*os.File, error) {
-	pwd, _ := os.Getwd()
-	dir := filepath.Join(pwd, "/logs")
-	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
-		return nil, errors.New("create /logs dir")
-	}
-
-	randomName := strconv.FormatInt(time.Now().UnixNano(), 10)
-	filename := filepath.Join(dir, randomName)
-	file, err := os.Create(filename)
-	if err != nil {
-		return nil, errors.New("create log file")
-	}
-
-	return file, nil
-}
@@ -0,0 +1,722 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hsequeda/drone/alert"
+	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/health"
+	dronehttp "github.com/hsequeda/drone/http"
+	"github.com/hsequeda/drone/logging"
+	"github.com/hsequeda/drone/scheduler"
+	"github.com/hsequeda/drone/storage"
+	"github.com/hsequeda/drone/tracing"
+	"github.com/hsequeda/drone/webhook"
+	"gopkg.in/yaml.v3"
+)
+
+// APIKeyConfiguration is an API key of the FileConfiguration.
+type APIKeyConfiguration struct {
+	Subject string `yaml:"subject"`
+	Tenant  string `yaml:"tenant"`
+	Key     string `yaml:"key"`
+	// Admin keys can reach the /admin endpoints.
+	Admin bool `yaml:"admin"`
+}
+
+// PolicyOverrideConfiguration is the drone.Policy of a tenant in the
+// FileConfiguration, its unset fields keep the values of the default policy.
+type PolicyOverrideConfiguration struct {
+	MaxWeight          map[string]uint32 `yaml:"max_weight"`
+	MinLoadBattery     *uint8            `yaml:"min_load_battery"`
+	MinDispatchBattery *uint8            `yaml:"min_dispatch_battery"`
+	Reserve            *uint8            `yaml:"reserve"`
+	// ModelCapabilities replace the capabilities of each model by its name.
+	ModelCapabilities map[string][]drone.Capability `yaml:"model_capabilities"`
+}
+
+// FileConfiguration is the configuration of the server as written in the YAML
+// file. Each field with an `env` tag is overridden by that environment variable.
+type FileConfiguration struct {
+	HTTP struct {
+		Addr              string        `yaml:"addr" env:"HTTP_SERVER_ADDR"`
+		ReadTimeout       time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+		ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+		WriteTimeout      time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+		IdleTimeout       time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+		ShutdownTimeout   time.Duration `yaml:"shutdown_timeout" env:"HTTP_SHUTDOWN_TIMEOUT"`
+		MaxJSONBodyKb     int64         `yaml:"max_json_body_kb" env:"HTTP_MAX_JSON_BODY_KB"`
+		RateLimit         struct {
+			PerIP struct {
+				RPS   float64 `yaml:"rps" env:"HTTP_RATE_LIMIT_IP_RPS"`
+				Burst int     `yaml:"burst" env:"HTTP_RATE_LIMIT_IP_BURST"`
+			} `yaml:"per_ip"`
+			PerAPIKey struct {
+				RPS   float64 `yaml:"rps" env:"HTTP_RATE_LIMIT_KEY_RPS"`
+				Burst int     `yaml:"burst" env:"HTTP_RATE_LIMIT_KEY_BURST"`
+			} `yaml:"per_api_key"`
+		} `yaml:"rate_limit"`
+		TLS struct {
+			CertFile string `yaml:"cert_file" env:"HTTP_TLS_CERT_FILE"`
+			KeyFile  string `yaml:"key_file" env:"HTTP_TLS_KEY_FILE"`
+		} `yaml:"tls"`
+	} `yaml:"http"`
+	Storage struct {
+		Backend string `yaml:"backend" env:"STORAGE_BACKEND"`
+		DataDir string `yaml:"data_dir" env:"STORAGE_DATA_DIR"`
+		// LegacyTenant is the tenant the drones saved before the tenants existed are migrated to.
+		LegacyTenant string `yaml:"legacy_tenant" env:"STORAGE_LEGACY_TENANT"`
+	} `yaml:"storage"`
+	Uploads struct {
+		Dir       string `yaml:"dir" env:"UPLOAD_DIR"`
+		MaxSizeMb int64  `yaml:"max_size_mb" env:"UPLOAD_SIZE"`
+	} `yaml:"uploads"`
+	Audit struct {
+		FilePath string `yaml:"file_path" env:"AUDIT_FILE_PATH"`
+	} `yaml:"audit"`
+	BatteryHistory struct {
+		Dir           string `yaml:"dir" env:"BATTERY_HISTORY_DIR"`
+		MaxFileSizeMb int64  `yaml:"max_file_size_mb" env:"BATTERY_HISTORY_MAX_FILE_SIZE"`
+		RotationHours int64  `yaml:"rotation_hours" env:"BATTERY_HISTORY_ROTATION_HOURS"`
+		RetentionDays int64  `yaml:"retention_days" env:"BATTERY_HISTORY_RETENTION_DAYS"`
+	} `yaml:"battery_history"`
+	Auth struct {
+		APIKeys []APIKeyConfiguration `yaml:"api_keys" env:"API_KEYS"`
+	} `yaml:"auth"`
+	Events struct {
+		DispatchInterval time.Duration `yaml:"dispatch_interval" env:"EVENTS_DISPATCH_INTERVAL"`
+	} `yaml:"events"`
+	Webhooks struct {
+		Timeout     time.Duration `yaml:"timeout" env:"WEBHOOKS_TIMEOUT"`
+		MaxAttempts int           `yaml:"max_attempts" env:"WEBHOOKS_MAX_ATTEMPTS"`
+		// InsecureHosts are the hosts the webhooks can use over plain http.
+		InsecureHosts []string `yaml:"insecure_hosts" env:"WEBHOOKS_INSECURE_HOSTS"`
+		// AllowPrivateNetworks allows the webhooks to reach loopback, link-local and private addresses.
+		AllowPrivateNetworks bool `yaml:"allow_private_networks" env:"WEBHOOKS_ALLOW_PRIVATE_NETWORKS"`
+	} `yaml:"webhooks"`
+	Orders struct {
+		Strategy      string `yaml:"strategy" env:"ORDERS_STRATEGY"`
+		BatteryMargin uint8  `yaml:"battery_margin" env:"ORDERS_BATTERY_MARGIN"`
+	} `yaml:"orders"`
+	Policy struct {
+		// MaxWeight is the max weight limit in grams of each model by its name (e.g. lightweight).
+		MaxWeight          map[string]uint32 `yaml:"max_weight"`
+		MinLoadBattery     uint8             `yaml:"min_load_battery" env:"POLICY_MIN_LOAD_BATTERY"`
+		MinDispatchBattery uint8             `yaml:"min_dispatch_battery" env:"POLICY_MIN_DISPATCH_BATTERY"`
+		Reserve            uint8             `yaml:"reserve" env:"POLICY_RESERVE"`
+		// ModelCapabilities replace the capabilities every drone of a model has by its name.
+		ModelCapabilities map[string][]drone.Capability          `yaml:"model_capabilities"`
+		Tenants           map[string]PolicyOverrideConfiguration `yaml:"tenants"`
+	} `yaml:"policy"`
+	Maintenance struct {
+		MaxCycles      int64 `yaml:"max_cycles" env:"MAINTENANCE_MAX_CYCLES"`
+		MaxFlightHours int64 `yaml:"max_flight_hours" env:"MAINTENANCE_MAX_FLIGHT_HOURS"`
+	} `yaml:"maintenance"`
+	Jobs struct {
+		BatteryAudit string `yaml:"battery_audit" env:"BATTERY_AUDIT_SCHEDULE"`
+	} `yaml:"jobs"`
+	Alerts struct {
+		BatteryBelow       uint8   `yaml:"battery_below" env:"ALERT_BATTERY_BELOW"`
+		DrainRatePerMinute float64 `yaml:"drain_rate_per_minute" env:"ALERT_DRAIN_RATE_PER_MINUTE"`
+		DeliveringBelow    uint8   `yaml:"delivering_below" env:"ALERT_DELIVERING_BELOW"`
+		CooldownMinutes    int64   `yaml:"cooldown_minutes" env:"ALERT_COOLDOWN_MINUTES"`
+		WebhookURL         string  `yaml:"webhook_url" env:"ALERT_WEBHOOK_URL"`
+		SMTP               struct {
+			Addr     string   `yaml:"addr" env:"ALERT_SMTP_ADDR"`
+			From     string   `yaml:"from" env:"ALERT_SMTP_FROM"`
+			To       []string `yaml:"to" env:"ALERT_SMTP_TO"`
+			Username string   `yaml:"username" env:"ALERT_SMTP_USERNAME"`
+			Password string   `yaml:"password" env:"ALERT_SMTP_PASSWORD"`
+		} `yaml:"smtp"`
+	} `yaml:"alerts"`
+	Health struct {
+		CheckTimeout  time.Duration `yaml:"check_timeout" env:"HEALTH_CHECK_TIMEOUT"`
+		MinFreeDiskMb int64         `yaml:"min_free_disk_mb" env:"HEALTH_MIN_FREE_DISK_MB"`
+	} `yaml:"health"`
+	Tracing struct {
+		Exporter string `yaml:"exporter" env:"TRACING_EXPORTER"`
+		Endpoint string `yaml:"endpoint" env:"TRACING_OTLP_ENDPOINT"`
+		Insecure bool   `yaml:"insecure" env:"TRACING_OTLP_INSECURE"`
+	} `yaml:"tracing"`
+	Log struct {
+		Level string `yaml:"level" env:"LOG_LEVEL"`
+	} `yaml:"log"`
+}
+
+// DefaultFileConfiguration returns the values used for the fields missing in
+// the file and the environment.
+func DefaultFileConfiguration() FileConfiguration {
+	var fc FileConfiguration
+	fc.HTTP.ReadTimeout = time.Minute
+	fc.HTTP.ReadHeaderTimeout = 10 * time.Second
+	fc.HTTP.WriteTimeout = time.Minute
+	fc.HTTP.IdleTimeout = 2 * time.Minute
+	fc.HTTP.ShutdownTimeout = 5 * time.Second
+	fc.HTTP.MaxJSONBodyKb = 64
+	fc.Storage.Backend = StorageBackendJSON
+	fc.Storage.DataDir = "data"
+	fc.Uploads.Dir = "uploads"
+	fc.Uploads.MaxSizeMb = 5
+	fc.Audit.FilePath = "data/audit/events.jsonl"
+	fc.BatteryHistory.Dir = "logs/battery"
+	fc.BatteryHistory.MaxFileSizeMb = 10
+	fc.BatteryHistory.RotationHours = 24
+	fc.BatteryHistory.RetentionDays = 30
+	fc.Events.DispatchInterval = time.Second
+	fc.Webhooks.Timeout = 10 * time.Second
+	fc.Alerts.CooldownMinutes = 30
+	fc.Orders.Strategy = AssignmentBestFit
+	fc.Orders.BatteryMargin = 10
+	defaultPolicy := drone.DefaultPolicy()
+	fc.Policy.MinLoadBattery = defaultPolicy.MinLoadBattery
+	fc.Policy.MinDispatchBattery = defaultPolicy.MinDispatchBattery
+	fc.Policy.Reserve = defaultPolicy.Reserve
+	fc.Health.CheckTimeout = 2 * time.Second
+	fc.Health.MinFreeDiskMb = 100
+	fc.Log.Level = "info"
+	return fc
+}
+
+// LoadFileConfiguration reads the YAML file at path (optional) over the
+// defaults, applies the environment overrides and validates the result. All
+// the problems found are reported in the returned error.
+func LoadFileConfiguration(path string, lookupEnv func(string) (string, bool)) (FileConfiguration, error) {
+	fc := DefaultFileConfiguration()
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return FileConfiguration{}, fmt.Errorf("read configuration file: %w", err)
+		}
+
+		dec := yaml.NewDecoder(bytes.NewReader(b))
+		dec.KnownFields(true)
+		if err := dec.Decode(&fc); err != nil && !errors.Is(err, io.EOF) {
+			return FileConfiguration{}, fmt.Errorf("parse configuration file %q: %w", path, err)
+		}
+	}
+
+	errs := applyEnv(reflect.ValueOf(&fc).Elem(), lookupEnv)
+	errs = append(errs, fc.Validate()...)
+	return fc, errors.Join(errs...)
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	apiKeysType  = reflect.TypeOf([]APIKeyConfiguration(nil))
+)
+
+// applyEnv overrides the fields of v (a struct) with their `env` variables.
+func applyEnv(v reflect.Value, lookupEnv func(string) (string, bool)) []error {
+	var errs []error
+	for i := 0; i < v.NumField(); i++ {
+		field, value := v.Type().Field(i), v.Field(i)
+		name, ok := field.Tag.Lookup("env")
+		if !ok {
+			if value.Kind() == reflect.Struct {
+				errs = append(errs, applyEnv(value, lookupEnv)...)
+			}
+
+			continue
+		}
+
+		s, ok := lookupEnv(name)
+		if !ok {
+			continue
+		}
+
+		if err := setFromEnv(value, s); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	return errs
+}
+
+func setFromEnv(v reflect.Value, s string) error {
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("need to be a duration (e.g. 10s)")
+		}
+
+		v.SetInt(int64(d))
+	case v.Type() == apiKeysType:
+		keys, err := parseAPIKeys(s)
+		if err != nil {
+			return err
+		}
+
+		v.Set(reflect.ValueOf(keys))
+	case v.Kind() == reflect.String:
+		v.SetString(s)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("need to be a boolean")
+		}
+
+		v.SetBool(b)
+	case v.Kind() == reflect.Int || v.Kind() == reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("need to be integer")
+		}
+
+		v.SetInt(n)
+	case v.Kind() == reflect.Uint8:
+		n, err := strconv.ParseUint(s, 10, 8)
+		if err != nil {
+			return fmt.Errorf("need to be a percentage")
+		}
+
+		v.SetUint(n)
+	case v.Kind() == reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("need to be a number")
+		}
+
+		v.SetFloat(f)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		var items []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
+
+// parseAPIKeys parses a comma separated list of `subject:tenant:key` entries,
+// the admin keys end with `:admin`.
+func parseAPIKeys(s string) ([]APIKeyConfiguration, error) {
+	var keys []APIKeyConfiguration
+	for _, entry := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if len(parts) < 3 || len(parts) > 4 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("invalid entry %q, expected subject:tenant:key[:admin]", entry)
+		}
+
+		k := APIKeyConfiguration{Subject: parts[0], Tenant: parts[1], Key: parts[2]}
+		if len(parts) == 4 {
+			if parts[3] != "admin" {
+				return nil, fmt.Errorf("invalid entry %q, unknown role %q", entry, parts[3])
+			}
+
+			k.Admin = true
+		}
+
+		keys = append(keys, k)
+	}
+
+	return keys, nil
+}
+
+// Validate returns every problem of the configuration.
+func (fc FileConfiguration) Validate() []error {
+	var errs []error
+	problem := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+
+	if fc.HTTP.Addr == "" {
+		problem("http.addr", "is required")
+	}
+
+	for _, t := range []struct {
+		key string
+		d   time.Duration
+	}{
+		{key: "http.read_timeout", d: fc.HTTP.ReadTimeout},
+		{key: "http.read_header_timeout", d: fc.HTTP.ReadHeaderTimeout},
+		{key: "http.write_timeout", d: fc.HTTP.WriteTimeout},
+		{key: "http.idle_timeout", d: fc.HTTP.IdleTimeout},
+	} {
+		if t.d < 0 {
+			problem(t.key, "can't be negative")
+		}
+	}
+
+	if fc.HTTP.ShutdownTimeout <= 0 {
+		problem("http.shutdown_timeout", "need to be positive")
+	}
+
+	if fc.HTTP.MaxJSONBodyKb <= 0 {
+		problem("http.max_json_body_kb", "need to be positive")
+	}
+
+	for _, l := range []struct {
+		key   string
+		rps   float64
+		burst int
+	}{
+		{key: "http.rate_limit.per_ip", rps: fc.HTTP.RateLimit.PerIP.RPS, burst: fc.HTTP.RateLimit.PerIP.Burst},
+		{key: "http.rate_limit.per_api_key", rps: fc.HTTP.RateLimit.PerAPIKey.RPS, burst: fc.HTTP.RateLimit.PerAPIKey.Burst},
+	} {
+		if l.rps < 0 {
+			problem(l.key+".rps", "can't be negative")
+		}
+
+		if l.rps > 0 && l.burst <= 0 {
+			problem(l.key+".burst", "need to be positive when rps is set")
+		}
+	}
+
+	if (fc.HTTP.TLS.CertFile == "") != (fc.HTTP.TLS.KeyFile == "") {
+		problem("http.tls", "cert_file and key_file are required together")
+	}
+
+	for _, f := range []struct{ key, path string }{
+		{key: "http.tls.cert_file", path: fc.HTTP.TLS.CertFile},
+		{key: "http.tls.key_file", path: fc.HTTP.TLS.KeyFile},
+	} {
+		if f.path == "" {
+			continue
+		}
+
+		if _, err := os.Stat(f.path); err != nil {
+			problem(f.key, "%s", err)
+		}
+	}
+
+	switch fc.Storage.Backend {
+	case StorageBackendJSON:
+		if fc.Storage.DataDir == "" {
+			problem("storage.data_dir", "is required by the %q backend", StorageBackendJSON)
+		}
+
+		if fc.Audit.FilePath == "" {
+			problem("audit.file_path", "is required by the %q backend", StorageBackendJSON)
+		}
+
+		if t := fc.Storage.LegacyTenant; t != "" && !slices.ContainsFunc(fc.Auth.APIKeys, func(k APIKeyConfiguration) bool { return k.Tenant == t }) {
+			problem("storage.legacy_tenant", "tenant %q has no API key", t)
+		}
+	case StorageBackendMemory:
+	default:
+		problem("storage.backend", "need to be %q or %q", StorageBackendJSON, StorageBackendMemory)
+	}
+
+	if fc.Uploads.Dir == "" {
+		problem("uploads.dir", "is required")
+	}
+
+	if fc.Uploads.MaxSizeMb <= 0 {
+		problem("uploads.max_size_mb", "need to be positive")
+	}
+
+	if fc.BatteryHistory.Dir == "" {
+		problem("battery_history.dir", "is required")
+	}
+
+	for _, h := range []struct {
+		key string
+		n   int64
+	}{
+		{key: "battery_history.max_file_size_mb", n: fc.BatteryHistory.MaxFileSizeMb},
+		{key: "battery_history.rotation_hours", n: fc.BatteryHistory.RotationHours},
+		{key: "battery_history.retention_days", n: fc.BatteryHistory.RetentionDays},
+	} {
+		if h.n < 0 {
+			problem(h.key, "can't be negative")
+		}
+	}
+
+	if len(fc.Auth.APIKeys) == 0 {
+		problem("auth.api_keys", "at least one key is required")
+	}
+
+	seen := make(map[string]bool)
+	for i, k := range fc.Auth.APIKeys {
+		if k.Subject == "" || k.Tenant == "" || k.Key == "" {
+			problem(fmt.Sprintf("auth.api_keys[%d]", i), "subject, tenant and key are required")
+		}
+
+		if seen[k.Key] {
+			problem(fmt.Sprintf("auth.api_keys[%d]", i), "duplicated key")
+		}
+
+		if k.Tenant == health.ProbeTenant {
+			problem(fmt.Sprintf("auth.api_keys[%d]", i), "tenant %q is reserved", health.ProbeTenant)
+		}
+
+		seen[k.Key] = true
+	}
+
+	if _, ok := assignmentStrategies[fc.Orders.Strategy]; !ok {
+		problem("orders.strategy", "need to be %q or %q", AssignmentBestFit, AssignmentMostBattery)
+	}
+
+	if fc.Policy.MinLoadBattery <= 100 && fc.Orders.BatteryMargin > 100-fc.Policy.MinLoadBattery {
+		problem("orders.battery_margin", "can't exceed %d", 100-fc.Policy.MinLoadBattery)
+	}
+
+	_, policyErrs := fc.policies()
+	errs = append(errs, policyErrs...)
+
+	if fc.Maintenance.MaxCycles < 0 || fc.Maintenance.MaxCycles > math.MaxUint32 {
+		problem("maintenance.max_cycles", "need to be between 0 and %d", uint32(math.MaxUint32))
+	}
+
+	if fc.Maintenance.MaxFlightHours < 0 {
+		problem("maintenance.max_flight_hours", "can't be negative")
+	}
+
+	if fc.Health.CheckTimeout <= 0 {
+		problem("health.check_timeout", "need to be positive")
+	}
+
+	if fc.Health.MinFreeDiskMb < 0 {
+		problem("health.min_free_disk_mb", "can't be negative")
+	}
+
+	if fc.Events.DispatchInterval <= 0 {
+		problem("events.dispatch_interval", "need to be positive")
+	}
+
+	if fc.Webhooks.Timeout <= 0 {
+		problem("webhooks.timeout", "need to be positive")
+	}
+
+	if fc.Webhooks.MaxAttempts < 0 {
+		problem("webhooks.max_attempts", "can't be negative")
+	}
+
+	if fc.Jobs.BatteryAudit != "" {
+		if _, err := scheduler.ParseSchedule(fc.Jobs.BatteryAudit); err != nil {
+			problem("jobs.battery_audit", "%s", err)
+		}
+	}
+
+	if fc.Alerts.BatteryBelow > 100 || fc.Alerts.DeliveringBelow > 100 {
+		problem("alerts", "battery_below and delivering_below need to be percentages")
+	}
+
+	if fc.Alerts.DrainRatePerMinute < 0 {
+		problem("alerts.drain_rate_per_minute", "can't be negative")
+	}
+
+	if fc.Alerts.CooldownMinutes < 0 {
+		problem("alerts.cooldown_minutes", "can't be negative")
+	}
+
+	if fc.Alerts.SMTP.Addr != "" && (fc.Alerts.SMTP.From == "" || len(fc.Alerts.SMTP.To) == 0) {
+		problem("alerts.smtp", "from and to are required with addr")
+	}
+
+	switch fc.Tracing.Exporter {
+	case tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterOTLP:
+	default:
+		problem("tracing.exporter", "need to be %q or %q", tracing.ExporterStdout, tracing.ExporterOTLP)
+	}
+
+	if _, err := logging.ParseLevel(fc.Log.Level); err != nil {
+		problem("log.level", "%s", err)
+	}
+
+	return errs
+}
+
+// Configuration builds the Configuration of the DroneContainer, it has to be valid.
+func (fc FileConfiguration) Configuration(logger *slog.Logger) *Configuration {
+	apiKeys := make([]dronehttp.APIKey, len(fc.Auth.APIKeys))
+	for i, k := range fc.Auth.APIKeys {
+		apiKeys[i] = dronehttp.APIKey{Subject: k.Subject, TenantID: k.Tenant, Key: k.Key, Admin: k.Admin}
+	}
+
+	var jobs JobsConfiguration
+	if fc.Jobs.BatteryAudit != "" {
+		jobs.BatteryAudit, _ = scheduler.ParseSchedule(fc.Jobs.BatteryAudit)
+	}
+
+	return &Configuration{
+		HTTPServer: HTTPServerConfiguration{
+			Addr:               fc.HTTP.Addr,
+			ReadTimeout:        fc.HTTP.ReadTimeout,
+			ReadHeaderTimeout:  fc.HTTP.ReadHeaderTimeout,
+			WriteTimeout:       fc.HTTP.WriteTimeout,
+			IdleTimeout:        fc.HTTP.IdleTimeout,
+			ShutdownTimeout:    fc.HTTP.ShutdownTimeout,
+			MaxJSONBodySize:    fc.HTTP.MaxJSONBodyKb * 1024,
+			RateLimitPerIP:     RateLimitConfiguration{RPS: fc.HTTP.RateLimit.PerIP.RPS, Burst: fc.HTTP.RateLimit.PerIP.Burst},
+			RateLimitPerAPIKey: RateLimitConfiguration{RPS: fc.HTTP.RateLimit.PerAPIKey.RPS, Burst: fc.HTTP.RateLimit.PerAPIKey.Burst},
+			TLSCertFile:        fc.HTTP.TLS.CertFile,
+			TLSKeyFile:         fc.HTTP.TLS.KeyFile,
+		},
+		DroneController: DroneControllerConfiguration{MaxUploadSize: fc.Uploads.MaxSizeMb * (1024 * 1024), UploadDir: fc.Uploads.Dir},
+		Storage:         StorageConfiguration{Backend: fc.Storage.Backend},
+		JSONStorage:     JSONStorageConfiguration{DatabasePath: fc.Storage.DataDir, LegacyTenant: fc.Storage.LegacyTenant},
+		Auth:            AuthConfiguration{APIKeys: apiKeys},
+		Audit:           AuditConfiguration{FilePath: fc.Audit.FilePath},
+		Events:          EventsConfiguration{DispatchInterval: fc.Events.DispatchInterval},
+		Webhooks: WebhooksConfiguration{
+			Timeout:  fc.Webhooks.Timeout,
+			Delivery: webhook.Config{MaxAttempts: fc.Webhooks.MaxAttempts},
+			Egress:   webhook.EgressPolicy{InsecureHosts: fc.Webhooks.InsecureHosts, AllowPrivate: fc.Webhooks.AllowPrivateNetworks},
+		},
+		BatteryHistory: BatteryHistoryConfiguration{
+			Dir: fc.BatteryHistory.Dir,
+			Options: storage.BatteryHistoryOptions{
+				MaxFileSize: fc.BatteryHistory.MaxFileSizeMb * (1024 * 1024),
+				MaxFileAge:  time.Duration(fc.BatteryHistory.RotationHours) * time.Hour,
+				Retention:   time.Duration(fc.BatteryHistory.RetentionDays) * 24 * time.Hour,
+			},
+		},
+		Alerts: fc.alerts(logger),
+		Jobs:   jobs,
+		Orders: OrdersConfiguration{Strategy: assignmentStrategies[fc.Orders.Strategy], BatteryMargin: fc.Orders.BatteryMargin},
+		Policies: func() *drone.Policies {
+			policies, _ := fc.policies()
+			return &policies
+		}(),
+		Maintenance: MaintenanceConfiguration{Rules: drone.MaintenanceRules{
+			MaxCycles:     uint32(fc.Maintenance.MaxCycles),
+			MaxFlightTime: time.Duration(fc.Maintenance.MaxFlightHours) * time.Hour,
+		}},
+		Health:  HealthConfiguration{CheckTimeout: fc.Health.CheckTimeout, MinFreeDisk: uint64(fc.Health.MinFreeDiskMb) * (1024 * 1024)},
+		Tracing: tracing.Config{ServiceName: "drone_server", Exporter: fc.Tracing.Exporter, Endpoint: fc.Tracing.Endpoint, Insecure: fc.Tracing.Insecure},
+		Logging: LoggingConfiguration{Logger: logger},
+	}
+}
+
+// policies builds the default drone.Policy and the overrides of the tenants,
+// returning the problems found as the Validate ones.
+func (fc FileConfiguration) policies() (drone.Policies, []error) {
+	var errs []error
+	maxWeight := func(key string, weights map[string]uint32, into map[drone.Model]uint32) {
+		for name, w := range weights {
+			m, ok := modelsByName[name]
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s: unknown model %q", key, name))
+				continue
+			}
+
+			into[m] = w
+		}
+	}
+	modelCapabilities := func(key string, capabilities map[string][]drone.Capability, into map[drone.Model][]drone.Capability) {
+		for name, caps := range capabilities {
+			m, ok := modelsByName[name]
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s: unknown model %q", key, name))
+				continue
+			}
+
+			into[m] = caps
+		}
+	}
+
+	def := drone.Policy{
+		MaxWeight:          make(map[drone.Model]uint32),
+		MinLoadBattery:     fc.Policy.MinLoadBattery,
+		MinDispatchBattery: fc.Policy.MinDispatchBattery,
+		Reserve:            fc.Policy.Reserve,
+		ModelCapabilities:  make(map[drone.Model][]drone.Capability),
+	}
+	maxWeight("policy.max_weight", fc.Policy.MaxWeight, def.MaxWeight)
+	modelCapabilities("policy.model_capabilities", fc.Policy.ModelCapabilities, def.ModelCapabilities)
+	if err := def.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("policy: %w", err))
+	}
+
+	policies := drone.Policies{Default: def, Tenants: make(map[string]drone.Policy)}
+	for tenantID, o := range fc.Policy.Tenants {
+		key := "policy.tenants." + tenantID
+		p := def
+		p.MaxWeight = make(map[drone.Model]uint32)
+		for m, w := range def.MaxWeight {
+			p.MaxWeight[m] = w
+		}
+		maxWeight(key+".max_weight", o.MaxWeight, p.MaxWeight)
+		p.ModelCapabilities = make(map[drone.Model][]drone.Capability)
+		for m, caps := range def.ModelCapabilities {
+			p.ModelCapabilities[m] = caps
+		}
+		modelCapabilities(key+".model_capabilities", o.ModelCapabilities, p.ModelCapabilities)
+		for _, f := range []struct {
+			value *uint8
+			into  *uint8
+		}{
+			{value: o.MinLoadBattery, into: &p.MinLoadBattery},
+			{value: o.MinDispatchBattery, into: &p.MinDispatchBattery},
+			{value: o.Reserve, into: &p.Reserve},
+		} {
+			if f.value != nil {
+				*f.into = *f.value
+			}
+		}
+
+		if err := p.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+
+		policies.Tenants[tenantID] = p
+	}
+
+	return policies, errs
+}
+
+// modelsByName are the drone models by their name in the configuration.
+var modelsByName = map[string]drone.Model{
+	drone.Lightweight.String():   drone.Lightweight,
+	drone.Middleweight.String():  drone.Middleweight,
+	drone.Cruiserweight.String(): drone.Cruiserweight,
+	drone.Heavyweight.String():   drone.Heavyweight,
+}
+
+// alerts builds the alert rules and notifiers, the alerts are always logged.
+func (fc FileConfiguration) alerts(logger *slog.Logger) AlertsConfiguration {
+	var rules []alert.Rule
+	if fc.Alerts.BatteryBelow > 0 {
+		rules = append(rules, alert.Threshold{Below: fc.Alerts.BatteryBelow})
+	}
+
+	if fc.Alerts.DrainRatePerMinute > 0 {
+		rules = append(rules, alert.DrainRate{MaxPerMinute: fc.Alerts.DrainRatePerMinute})
+	}
+
+	if fc.Alerts.DeliveringBelow > 0 {
+		rules = append(rules, alert.DeliveringBelow{Below: fc.Alerts.DeliveringBelow})
+	}
+
+	notifiers := alert.Notifiers{alert.LogNotifier{Logger: slog.NewLogLogger(logger.Handler(), slog.LevelWarn)}}
+	if fc.Alerts.WebhookURL != "" {
+		notifiers = append(notifiers, alert.WebhookNotifier{URL: fc.Alerts.WebhookURL, Client: &http.Client{Timeout: 10 * time.Second}})
+	}
+
+	if smtpConfig := fc.Alerts.SMTP; smtpConfig.Addr != "" {
+		n := alert.SMTPNotifier{Addr: smtpConfig.Addr, From: smtpConfig.From, To: smtpConfig.To}
+		if smtpConfig.Username != "" {
+			host, _, _ := net.SplitHostPort(smtpConfig.Addr)
+			n.Auth = smtp.PlainAuth("", smtpConfig.Username, smtpConfig.Password, host)
+		}
+
+		notifiers = append(notifiers, n)
+	}
+
+	return AlertsConfiguration{
+		Evaluator: alert.NewEvaluator(time.Duration(fc.Alerts.CooldownMinutes)*time.Minute, rules...),
+		Notifier:  notifiers,
+	}
+}
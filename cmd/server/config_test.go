@@ -0,0 +1,243 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/logging"
+	"github.com/hsequeda/drone/scheduler"
+	"github.com/hsequeda/drone/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// envMap is a lookupEnv backed by a map.
+func envMap(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadFileConfiguration(t *testing.T) {
+	path := writeConfigFile(t, `
+http:
+  addr: ":8484"
+  write_timeout: 30s
+storage:
+  backend: memory
+uploads:
+  max_size_mb: 2
+auth:
+  api_keys:
+    - subject: operator
+      tenant: hospital-a
+      key: file-key
+jobs:
+  battery_audit: "*/5 * * * *"
+alerts:
+  battery_below: 20
+maintenance:
+  max_flight_hours: 50
+policy:
+  max_weight:
+    heavyweight: 800
+  tenants:
+    hospital-b:
+      max_weight:
+        lightweight: 200
+      min_dispatch_battery: 50
+      model_capabilities:
+        lightweight: [hazmat_certified]
+`)
+
+	fc, err := LoadFileConfiguration(path, envMap(map[string]string{
+		"HTTP_SERVER_ADDR":        ":9000",
+		"UPLOAD_SIZE":             "8",
+		"API_KEYS":                "operator:hospital-b:env-key,ops:hospital-b:admin-key:admin",
+		"TRACING_OTLP_INSECURE":   "true",
+		"ALERT_SMTP_TO":           "ops@hospital.local, admin@hospital.local",
+		"ALERT_SMTP_ADDR":         "smtp.hospital.local:25",
+		"ALERT_SMTP_FROM":         "drones@hospital.local",
+		"MAINTENANCE_MAX_CYCLES":  "200",
+		"POLICY_RESERVE":          "15",
+		"WEBHOOKS_INSECURE_HOSTS": "hooks.hospital.local",
+	}))
+	require.NoError(t, err)
+
+	// from the environment
+	assert.Equal(t, ":9000", fc.HTTP.Addr)
+	assert.Equal(t, int64(8), fc.Uploads.MaxSizeMb)
+	assert.Equal(t, []APIKeyConfiguration{
+		{Subject: "operator", Tenant: "hospital-b", Key: "env-key"},
+		{Subject: "ops", Tenant: "hospital-b", Key: "admin-key", Admin: true},
+	}, fc.Auth.APIKeys)
+	assert.True(t, fc.Tracing.Insecure)
+	assert.Equal(t, []string{"ops@hospital.local", "admin@hospital.local"}, fc.Alerts.SMTP.To)
+	// from the file
+	assert.Equal(t, 30*time.Second, fc.HTTP.WriteTimeout)
+	assert.Equal(t, StorageBackendMemory, fc.Storage.Backend)
+	assert.Equal(t, uint8(20), fc.Alerts.BatteryBelow)
+	// defaults
+	assert.Equal(t, 10*time.Second, fc.HTTP.ReadHeaderTimeout)
+	assert.Equal(t, "uploads", fc.Uploads.Dir)
+	assert.Equal(t, time.Second, fc.Events.DispatchInterval)
+
+	config := fc.Configuration(logging.New(os.Stderr, 0))
+	assert.Equal(t, ":9000", config.HTTPServer.Addr)
+	assert.Equal(t, int64(8*1024*1024), config.DroneController.MaxUploadSize)
+	assert.Equal(t, StorageBackendMemory, config.Storage.Backend)
+	require.Len(t, config.Auth.APIKeys, 2)
+	assert.Equal(t, "hospital-b", config.Auth.APIKeys[0].TenantID)
+	assert.True(t, config.Auth.APIKeys[1].Admin)
+	assert.Equal(t, 24*time.Hour, config.BatteryHistory.Options.MaxFileAge)
+	require.NotNil(t, config.Jobs.BatteryAudit)
+	assert.Equal(t, "*/5 * * * *", config.Jobs.BatteryAudit.(*scheduler.Cron).String())
+	assert.NotNil(t, config.Alerts.Evaluator)
+	assert.Equal(t, drone.MaintenanceRules{MaxCycles: 200, MaxFlightTime: 50 * time.Hour}, config.Maintenance.Rules)
+	assert.Equal(t, webhook.EgressPolicy{InsecureHosts: []string{"hooks.hospital.local"}}, config.Webhooks.Egress)
+	require.NotNil(t, config.Policies)
+	assert.Equal(t, drone.Policy{
+		MaxWeight:          map[drone.Model]uint32{drone.Heavyweight: 800},
+		MinLoadBattery:     25,
+		MinDispatchBattery: 25,
+		Reserve:            15,
+		ModelCapabilities:  map[drone.Model][]drone.Capability{},
+	}, config.Policies.For("hospital-a"))
+	assert.Equal(t, drone.Policy{
+		MaxWeight:          map[drone.Model]uint32{drone.Heavyweight: 800, drone.Lightweight: 200},
+		MinLoadBattery:     25,
+		MinDispatchBattery: 50,
+		Reserve:            15,
+		ModelCapabilities:  map[drone.Model][]drone.Capability{drone.Lightweight: {drone.HazmatCertified}},
+	}, config.Policies.For("hospital-b"))
+}
+
+func TestConfigDistIsValid(t *testing.T) {
+	fc, err := LoadFileConfiguration("../../config.dist.yaml", envMap(nil))
+	require.NoError(t, err)
+	assert.Equal(t, DefaultFileConfiguration().Storage, fc.Storage)
+}
+
+func TestLoadFileConfigurationWithoutFile(t *testing.T) {
+	fc, err := LoadFileConfiguration("", envMap(map[string]string{
+		"HTTP_SERVER_ADDR": ":4444",
+		"API_KEYS":         "operator:hospital-a:change-me",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, ":4444", fc.HTTP.Addr)
+	assert.Equal(t, StorageBackendJSON, fc.Storage.Backend)
+	assert.Equal(t, "data", fc.Storage.DataDir)
+}
+
+func TestLoadFileConfigurationReportsAllProblems(t *testing.T) {
+	path := writeConfigFile(t, `
+http:
+  read_timeout: -1s
+  max_json_body_kb: 0
+  rate_limit:
+    per_ip:
+      rps: 5
+  tls:
+    cert_file: missing.pem
+storage:
+  backend: postgres
+uploads:
+  max_size_mb: 0
+auth:
+  api_keys:
+    - subject: operator
+      tenant: hospital-a
+      key: same
+    - subject: operator
+      tenant: hospital-b
+      key: same
+    - subject: operator
+      tenant: _health
+      key: probe
+orders:
+  strategy: random
+maintenance:
+  max_cycles: -1
+policy:
+  min_load_battery: 120
+  model_capabilities:
+    heavyweight: [teleporter]
+  tenants:
+    hospital-b:
+      max_weight:
+        featherweight: 100
+jobs:
+  battery_audit: "every minute"
+tracing:
+  exporter: zipkin
+`)
+
+	_, err := LoadFileConfiguration(path, envMap(map[string]string{
+		"UPLOAD_SIZE": "five",
+		"LOG_LEVEL":   "verbose",
+	}))
+	require.Error(t, err)
+	for _, problem := range []string{
+		"UPLOAD_SIZE: need to be integer",
+		"http.addr: is required",
+		"http.read_timeout: can't be negative",
+		"http.max_json_body_kb: need to be positive",
+		"http.rate_limit.per_ip.burst: need to be positive when rps is set",
+		"http.tls: cert_file and key_file are required together",
+		"http.tls.cert_file: stat missing.pem",
+		`storage.backend: need to be "json" or "memory"`,
+		"uploads.max_size_mb: need to be positive",
+		"auth.api_keys[1]: duplicated key",
+		`auth.api_keys[2]: tenant "_health" is reserved`,
+		`orders.strategy: need to be "best_fit" or "most_battery"`,
+		"maintenance.max_cycles: need to be between 0 and 4294967295",
+		"policy: battery levels need to be percentages",
+		`unknown capability "teleporter" of heavyweight`,
+		`policy.tenants.hospital-b.max_weight: unknown model "featherweight"`,
+		"jobs.battery_audit: invalid cron expression",
+		`tracing.exporter: need to be "stdout" or "otlp"`,
+		`log.level: invalid log level "verbose"`,
+	} {
+		assert.Contains(t, err.Error(), problem)
+	}
+}
+
+func TestLoadFileConfigurationUnknownField(t *testing.T) {
+	path := writeConfigFile(t, `
+http:
+  address: ":8484"
+`)
+
+	_, err := LoadFileConfiguration(path, envMap(nil))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "field address not found")
+}
+
+func TestLoadFileConfigurationLegacyTenant(t *testing.T) {
+	path := writeConfigFile(t, `
+http:
+  addr: ":8080"
+storage:
+  legacy_tenant: hospital-z
+auth:
+  api_keys:
+    - subject: operator
+      tenant: hospital-a
+      key: secret
+`)
+
+	_, err := LoadFileConfiguration(path, envMap(nil))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `storage.legacy_tenant: tenant "hospital-z" has no API key`)
+}
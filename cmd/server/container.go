@@ -1,20 +1,52 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"io"
+	"log/slog"
 	"net/http"
-	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/hsequeda/drone/alert"
+	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/health"
 	dronehttp "github.com/hsequeda/drone/http"
+	"github.com/hsequeda/drone/logging"
+	"github.com/hsequeda/drone/metrics"
+	"github.com/hsequeda/drone/scheduler"
 	"github.com/hsequeda/drone/storage"
+	"github.com/hsequeda/drone/tracing"
+	"github.com/hsequeda/drone/webhook"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sdomino/scribble"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 type Configuration struct {
 	HTTPServer      HTTPServerConfiguration
 	DroneController DroneControllerConfiguration
+	Storage         StorageConfiguration
 	JSONStorage     JSONStorageConfiguration
+	Auth            AuthConfiguration
+	Audit           AuditConfiguration
+	Events          EventsConfiguration
+	Webhooks        WebhooksConfiguration
+	BatteryHistory  BatteryHistoryConfiguration
+	Alerts          AlertsConfiguration
+	Jobs            JobsConfiguration
+	Orders          OrdersConfiguration
+	// Policies are the business rules of the tenants (nil uses drone.DefaultPolicy for all of them).
+	Policies    *drone.Policies
+	Maintenance MaintenanceConfiguration
+	Metrics     MetricsConfiguration
+	Health      HealthConfiguration
+	Tracing     tracing.Config
+	Logging     LoggingConfiguration
 }
 
 type DroneControllerConfiguration struct {
@@ -24,20 +56,161 @@ type DroneControllerConfiguration struct {
 
 type HTTPServerConfiguration struct {
 	Addr string
+	// ReadTimeout, ReadHeaderTimeout, WriteTimeout and IdleTimeout are the timeouts of the http.Server (zero means no timeout).
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// ShutdownTimeout bounds the graceful shutdown of the server.
+	ShutdownTimeout time.Duration
+	// MaxJSONBodySize is the max size in bytes of the JSON request bodies (zero means no limit).
+	MaxJSONBodySize int64
+	// RateLimitPerIP and RateLimitPerAPIKey limit the requests of each client IP and API key.
+	RateLimitPerIP     RateLimitConfiguration
+	RateLimitPerAPIKey RateLimitConfiguration
+	// TLSCertFile and TLSKeyFile enable HTTPS when both are set. The certificate is reloaded when the files change.
+	TLSCertFile string
+	TLSKeyFile  string
+}
+
+// RateLimitConfiguration is a token bucket of RPS requests per second and Burst capacity (zero RPS disables it).
+type RateLimitConfiguration struct {
+	RPS   float64
+	Burst int
+}
+
+// Storage backends of the StorageConfiguration.
+const (
+	StorageBackendJSON   = "json"
+	StorageBackendMemory = "memory"
+)
+
+type StorageConfiguration struct {
+	// Backend is StorageBackendJSON (default) or StorageBackendMemory, the audit
+	// events are kept in memory with the memory backend.
+	Backend string
 }
 
 type JSONStorageConfiguration struct {
 	DatabasePath string
+	// LegacyTenant is the tenant the drones saved before the tenants existed are migrated to ("" skips the migration).
+	LegacyTenant string
+}
+
+type AuditConfiguration struct {
+	// FilePath is the JSON Lines file where the audit events are appended.
+	FilePath string
+}
+
+type EventsConfiguration struct {
+	// DispatchInterval is the interval between the passes of the outbox dispatcher.
+	DispatchInterval time.Duration
+}
+
+type WebhooksConfiguration struct {
+	// Delivery is the retry policy of the webhook deliveries (zero values use webhook.DefaultConfig).
+	Delivery webhook.Config
+	// Timeout is the timeout of each delivery request.
+	Timeout time.Duration
+	// Egress restricts the endpoints the webhooks can be registered for and delivered to.
+	Egress webhook.EgressPolicy
+}
+
+type BatteryHistoryConfiguration struct {
+	// Dir is the directory of the battery history files (shared with the log_register).
+	Dir string
+	// Options are the rotation and retention of the files written by the battery audit job.
+	Options storage.BatteryHistoryOptions
+}
+
+type AlertsConfiguration struct {
+	// Evaluator evaluates the alert rules of the battery audit job (nil raises no alerts).
+	Evaluator *alert.Evaluator
+	// Notifier delivers the alerts (nil writes them to the standard logger).
+	Notifier alert.Notifier
+}
+
+type JobsConfiguration struct {
+	// BatteryAudit is the schedule of the battery audit job (nil disables it).
+	BatteryAudit scheduler.Schedule
+}
+
+type MetricsConfiguration struct {
+	// FleetTimeout bounds the storage query of the fleet metrics on each scrape (zero uses 5s).
+	FleetTimeout time.Duration
+}
+
+// Assignment strategies of the OrdersConfiguration.
+const (
+	AssignmentBestFit     = "best_fit"
+	AssignmentMostBattery = "most_battery"
+)
+
+var assignmentStrategies = map[string]drone.AssignmentStrategy{
+	AssignmentBestFit:     drone.BestFit,
+	AssignmentMostBattery: drone.MostBattery,
+}
+
+type OrdersConfiguration struct {
+	// Strategy selects the drone of each order (nil uses drone.BestFit).
+	Strategy drone.AssignmentStrategy
+	// BatteryMargin is the battery over the MinLoadBattery of the drone.Policy a drone needs to be assigned an order.
+	BatteryMargin uint8
+}
+
+type MaintenanceConfiguration struct {
+	// Rules ground the drones due for maintenance (the zero value never does).
+	Rules drone.MaintenanceRules
+}
+
+type HealthConfiguration struct {
+	// CheckTimeout bounds each readiness check (zero uses 2s).
+	CheckTimeout time.Duration
+	// MinFreeDisk is the min free space in bytes of the upload and data directories to be ready.
+	MinFreeDisk uint64
+}
+
+type LoggingConfiguration struct {
+	// Logger is the structured logger of the server (nil discards the logs).
+	Logger *slog.Logger
+}
+
+type AuthConfiguration struct {
+	// APIKeys are the keys accepted by the /api/v1 routes, each one bound to a tenant.
+	APIKeys []dronehttp.APIKey
 }
 
 type DroneContainer struct {
 	config *Configuration
 
-	router          *chi.Mux
-	v1router        *chi.Mux
-	httpServer      *http.Server
-	jsonStorage     *storage.JSON
-	droneController *dronehttp.DroneController
+	router             *chi.Mux
+	v1router           *chi.Mux
+	httpServer         *http.Server
+	logger             *slog.Logger
+	registry           *prometheus.Registry
+	metrics            *metrics.Metrics
+	metricsHandler     http.Handler
+	tracerProvider     *sdktrace.TracerProvider
+	storage            *metrics.Storage
+	auditStore         *metrics.AuditStore
+	dispatcher         *drone.Dispatcher
+	deliverer          *webhook.Deliverer
+	eventStream        *dronehttp.EventStream
+	droneLink          *dronehttp.DroneLink
+	batteryHistory     *metrics.BatteryHistory
+	batteryAudit       *alert.BatteryAudit
+	scheduler          *scheduler.Scheduler
+	jobController      *dronehttp.JobController
+	droneLocks         *drone.Locks
+	assigner           *drone.Assigner
+	orderController    *dronehttp.OrderController
+	healthChecker      *health.Checker
+	healthController   *dronehttp.HealthController
+	webhookController  *dronehttp.WebhookController
+	geofenceController *dronehttp.GeofenceController
+	dockManager        *drone.DockManager
+	dockController     *dronehttp.DockController
+	droneController    *dronehttp.DroneController
 }
 
 func NewDroneContainer(config *Configuration) *DroneContainer {
@@ -46,38 +219,241 @@ func NewDroneContainer(config *Configuration) *DroneContainer {
 	}
 }
 
-func (c *DroneContainer) Storage() *storage.JSON {
-	if c.jsonStorage == nil {
-		db, err := scribble.New(c.config.JSONStorage.DatabasePath, nil)
+func (c *DroneContainer) Logger() *slog.Logger {
+	if c.logger == nil {
+		c.logger = c.config.Logging.Logger
+		if c.logger == nil {
+			c.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+		}
+	}
+
+	return c.logger
+}
+
+func (c *DroneContainer) Registry() *prometheus.Registry {
+	if c.registry == nil {
+		c.registry = prometheus.NewRegistry()
+		c.registry.MustRegister(
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		)
+	}
+
+	return c.registry
+}
+
+func (c *DroneContainer) Metrics() *metrics.Metrics {
+	if c.metrics == nil {
+		c.metrics = metrics.New(c.Registry())
+	}
+
+	return c.metrics
+}
+
+func (c *DroneContainer) MetricsHandler() http.Handler {
+	if c.metricsHandler == nil {
+		timeout := c.config.Metrics.FleetTimeout
+		if timeout <= 0 {
+			timeout = 5 * time.Second
+		}
+
+		c.Registry().MustRegister(metrics.NewFleetCollector(c.Storage(), timeout))
+		c.metricsHandler = promhttp.HandlerFor(c.Registry(), promhttp.HandlerOpts{})
+	}
+
+	return c.metricsHandler
+}
+
+// TracerProvider builds the TracerProvider and sets it as the global one, used by all the spans.
+func (c *DroneContainer) TracerProvider() *sdktrace.TracerProvider {
+	if c.tracerProvider == nil {
+		config := c.config.Tracing
+		if config.ServiceName == "" {
+			config.ServiceName = "drone_server"
+		}
+
+		tp, err := tracing.NewTracerProvider(context.Background(), config)
 		if err != nil {
 			panic(err)
 		}
 
-		c.jsonStorage = storage.NewJSON(db)
+		tracing.SetGlobal(tp)
+		c.tracerProvider = tp
+	}
+
+	return c.tracerProvider
+}
+
+func (c *DroneContainer) Storage() *metrics.Storage {
+	if c.storage == nil {
+		var backend storage.Backend
+		name := c.config.Storage.Backend
+		switch name {
+		case StorageBackendMemory:
+			backend = storage.NewInMemory()
+		default:
+			db, err := scribble.New(c.config.JSONStorage.DatabasePath, nil)
+			if err != nil {
+				panic(err)
+			}
+
+			name = StorageBackendJSON
+			j := storage.NewJSON(db)
+			if tenantID := c.config.JSONStorage.LegacyTenant; tenantID != "" {
+				migrated, err := storage.MigrateLegacyDrones(context.Background(), j, c.config.JSONStorage.DatabasePath, tenantID)
+				if err != nil {
+					panic(err)
+				}
+
+				if migrated > 0 {
+					c.Logger().Info("legacy drones migrated", slog.String("tenant_id", tenantID), slog.Int("drones", migrated))
+				}
+			}
+
+			backend = j
+		}
+
+		c.TracerProvider()
+		traced := tracing.InstrumentStorage(logging.InstrumentStorage(backend, name), name)
+		c.storage = c.Metrics().InstrumentStorage(traced, name)
+	}
+
+	return c.storage
+}
+
+func (c *DroneContainer) AuditStore() *metrics.AuditStore {
+	if c.auditStore == nil {
+		if c.config.Storage.Backend == StorageBackendMemory {
+			c.auditStore = c.Metrics().InstrumentAuditStore(storage.NewInMemoryAudit(), "audit_memory")
+		} else {
+			c.auditStore = c.Metrics().InstrumentAuditStore(storage.NewFileAudit(c.config.Audit.FilePath), "audit_file")
+		}
+	}
+
+	return c.auditStore
+}
+
+func (c *DroneContainer) BatteryHistory() *metrics.BatteryHistory {
+	if c.batteryHistory == nil {
+		history := storage.NewBatteryHistoryFile(c.config.BatteryHistory.Dir, c.config.BatteryHistory.Options)
+		c.batteryHistory = c.Metrics().InstrumentBatteryHistory(history, "battery_history_file")
+	}
+
+	return c.batteryHistory
+}
+
+func (c *DroneContainer) BatteryAudit() *alert.BatteryAudit {
+	if c.batteryAudit == nil {
+		evaluator := c.config.Alerts.Evaluator
+		if evaluator == nil {
+			evaluator = alert.NewEvaluator(0)
+		}
+
+		notifier := c.config.Alerts.Notifier
+		if notifier == nil {
+			notifier = alert.LogNotifier{Logger: slog.NewLogLogger(c.Logger().Handler(), slog.LevelWarn)}
+		}
+
+		c.batteryAudit = alert.NewBatteryAudit(c.Storage(), c.BatteryHistory(), evaluator, notifier)
+	}
+
+	return c.batteryAudit
+}
+
+func (c *DroneContainer) Scheduler() *scheduler.Scheduler {
+	if c.scheduler == nil {
+		c.scheduler = scheduler.New(func(name string, err error) {
+			c.Logger().Error("job failed", slog.String("job", name), slog.String("error", err.Error()))
+		})
+
+		if schedule := c.config.Jobs.BatteryAudit; schedule != nil {
+			if err := c.scheduler.Add("battery_audit", schedule, c.BatteryAudit().Run); err != nil {
+				panic(err)
+			}
+		}
+	}
+
+	return c.scheduler
+}
+
+func (c *DroneContainer) HealthChecker() *health.Checker {
+	if c.healthChecker == nil {
+		timeout := c.config.Health.CheckTimeout
+		if timeout == 0 {
+			timeout = 2 * time.Second
+		}
+
+		c.healthChecker = health.New(timeout)
+		register := func(name string, check health.Check) {
+			if err := c.healthChecker.Register(name, check); err != nil {
+				panic(err)
+			}
+		}
+
+		register("storage", health.StorageRoundTrip(c.Storage()))
+		register("upload_dir", health.DirWritable(c.config.DroneController.UploadDir))
+		register("upload_disk", health.DiskFree(c.config.DroneController.UploadDir, c.config.Health.MinFreeDisk))
+		if c.config.Storage.Backend != StorageBackendMemory {
+			register("storage_disk", health.DiskFree(c.config.JSONStorage.DatabasePath, c.config.Health.MinFreeDisk))
+		}
+	}
+
+	return c.healthChecker
+}
+
+func (c *DroneContainer) Dispatcher() *drone.Dispatcher {
+	if c.dispatcher == nil {
+		c.dispatcher = drone.NewDispatcher(c.Storage())
+		c.dispatcher.SubscribeAll(c.WebhookDeliverer().HandleEvent)
+		c.dispatcher.SubscribeAll(c.EventStream().Publish)
 	}
 
-	return c.jsonStorage
+	return c.dispatcher
+}
+
+func (c *DroneContainer) EventStream() *dronehttp.EventStream {
+	if c.eventStream == nil {
+		c.eventStream = dronehttp.NewEventStream(c.Storage())
+	}
+
+	return c.eventStream
+}
+
+func (c *DroneContainer) DroneLink() *dronehttp.DroneLink {
+	if c.droneLink == nil {
+		c.droneLink = dronehttp.NewDroneLink(c.Storage(), c.DroneLocks(), c.AuditStore(), c.DockManager(), c.config.Maintenance.Rules)
+	}
+
+	return c.droneLink
+}
+
+func (c *DroneContainer) WebhookDeliverer() *webhook.Deliverer {
+	if c.deliverer == nil {
+		client := c.config.Webhooks.Egress.Client(c.config.Webhooks.Timeout)
+		c.deliverer = webhook.NewDeliverer(c.Storage(), client, c.config.Webhooks.Delivery)
+	}
+
+	return c.deliverer
 }
 
 func (c *DroneContainer) Router() *chi.Mux {
 	if c.router == nil {
+		c.TracerProvider()
 		c.router = chi.NewRouter()
+		c.router.Use(tracing.Middleware, c.Metrics().Middleware)
+		if l := c.config.HTTPServer.RateLimitPerIP; l.RPS > 0 {
+			c.router.Use(dronehttp.NewRateLimiter(l.RPS, l.Burst, dronehttp.ClientIP).Middleware)
+		}
 		c.router.NotFound(c.router.NotFoundHandler())
 		c.router.Route("/api", func(r chi.Router) {
 			r.Mount("/v1", c.V1Router())
 		})
 
-		c.router.Get("/health", func(w http.ResponseWriter, _ *http.Request) {
-			w.WriteHeader(http.StatusOK)
-		})
-		filesDir := http.Dir(c.config.DroneController.UploadDir)
-		c.router.Get("/static/*", func(w http.ResponseWriter, r *http.Request) {
-			rctx := chi.RouteContext(r.Context())
-			pathPrefix := strings.TrimSuffix(rctx.RoutePattern(), "/*")
-			fs := http.StripPrefix(pathPrefix, http.FileServer(filesDir))
-			fs.ServeHTTP(w, r)
-		})
-
+		c.router.With(dronehttp.Authenticate(c.config.Auth.APIKeys), dronehttp.RequireAdmin).Handle("/metrics", c.MetricsHandler())
+		c.router.Get("/livez", c.HealthController().Livez)
+		c.router.Get("/readyz", c.HealthController().Readyz)
+		// NOTE: /health is kept for the existing probes.
+		c.router.Get("/health", c.HealthController().Livez)
 		c.router.Mount("/debug", middleware.Profiler())
 	}
 
@@ -90,15 +466,56 @@ func (c *DroneContainer) V1Router() *chi.Mux {
 		// Add middlewares
 		c.v1router.Use(
 			middleware.RequestID,
-			middleware.Logger,
+			logging.RequestLogger(c.Logger()),
 			middleware.Recoverer,
+			dronehttp.Authenticate(c.config.Auth.APIKeys),
 		)
+		if l := c.config.HTTPServer.RateLimitPerAPIKey; l.RPS > 0 {
+			c.v1router.Use(dronehttp.NewRateLimiter(l.RPS, l.Burst, dronehttp.PrincipalKey).Middleware)
+		}
+
 		c.v1router.Route("/", func(r chi.Router) {
+			// JSON endpoints.
+			r.Group(func(r chi.Router) {
+				if n := c.config.HTTPServer.MaxJSONBodySize; n > 0 {
+					r.Use(dronehttp.LimitBody(n))
+				}
+				r.Post("/drone", c.DroneController().RegisterADrone)
+				r.Post("/drone/{serial}/commands", c.DroneController().SendDroneCommand)
+				r.Put("/drone/{serial}/home", c.DroneController().SetDroneHome)
+				r.Put("/drone/{serial}/destination", c.DroneController().SetDroneDestination)
+				r.Put("/drone/{serial}/maintenance", c.DroneController().SetDroneMaintenance)
+				r.Put("/drone/{serial}/capabilities", c.DroneController().SetDroneCapabilities)
+				r.Post("/webhooks", c.WebhookController().RegisterWebhook)
+				r.Post("/orders", c.OrderController().CreateOrder)
+				r.Post("/orders/plan", c.OrderController().PlanOrder)
+				r.Post("/orders/split", c.OrderController().SplitOrder)
+				r.Post("/geofences", c.GeofenceController().RegisterGeofence)
+				r.Post("/docks", c.DockController().RegisterDock)
+			})
 			r.Get("/drones", c.DroneController().GetAvailableDrones)
-			r.Post("/drone", c.DroneController().RegisterADrone)
+			r.Get("/drone/{serial}", c.DroneController().GetDrone)
 			r.Put("/drone/{serial}", c.DroneController().LoadDrone)
+			r.Get("/drone/{serial}/link", c.DroneLink().Connect)
+			r.Post("/drone/{serial}/link-key", c.DroneController().IssueDroneLinkKey)
 			r.Get("/drone/{serial}/battery", c.DroneController().GetDroneBatteryLevel)
+			r.Get("/drone/{serial}/battery/history", c.DroneController().GetDroneBatteryHistory)
 			r.Get("/drone/{serial}/medications", c.DroneController().GetDroneMedications)
+			r.Get("/drone/{serial}/audit", c.DroneController().GetDroneAudit)
+			r.Get("/drone/{serial}/events", c.EventStream().StreamDroneEvents)
+			r.Get("/static/*", c.DroneController().GetUpload)
+			r.Get("/events", c.EventStream().StreamEvents)
+			r.Get("/geofences", c.GeofenceController().GetGeofences)
+			r.Delete("/geofences/{id}", c.GeofenceController().DeleteGeofence)
+			r.Get("/docks", c.DockController().GetDocks)
+			r.Get("/docks/{id}", c.DockController().GetDock)
+			r.Delete("/docks/{id}", c.DockController().DeleteDock)
+			r.Get("/docks/{id}/sessions", c.DockController().GetDockSessions)
+			r.Get("/webhooks", c.WebhookController().GetWebhooks)
+			r.Get("/webhooks/dead-letters", c.WebhookController().GetWebhookDeadLetters)
+			r.Delete("/webhooks/{id}", c.WebhookController().DeleteWebhook)
+			r.Get("/webhooks/{id}/deliveries", c.WebhookController().GetWebhookDeliveries)
+			r.With(dronehttp.RequireAdmin).Get("/admin/jobs", c.JobController().GetJobs)
 		})
 	}
 
@@ -107,17 +524,111 @@ func (c *DroneContainer) V1Router() *chi.Mux {
 
 func (c *DroneContainer) DroneController() *dronehttp.DroneController {
 	if c.droneController == nil {
-		c.droneController = dronehttp.NewHttpServer(c.Storage(), c.config.DroneController.MaxUploadSize, c.config.DroneController.UploadDir)
+		c.droneController = dronehttp.NewHttpServer(c.Storage(), c.DroneLocks(), c.AuditStore(), c.Storage(), c.Policies(), c.DroneLink(), c.BatteryHistory(), c.config.DroneController.MaxUploadSize, c.config.DroneController.UploadDir)
 	}
 
 	return c.droneController
 }
 
+func (c *DroneContainer) WebhookController() *dronehttp.WebhookController {
+	if c.webhookController == nil {
+		c.webhookController = dronehttp.NewWebhookController(c.Storage(), c.config.Webhooks.Egress)
+	}
+
+	return c.webhookController
+}
+
+func (c *DroneContainer) GeofenceController() *dronehttp.GeofenceController {
+	if c.geofenceController == nil {
+		c.geofenceController = dronehttp.NewGeofenceController(c.Storage())
+	}
+
+	return c.geofenceController
+}
+
+func (c *DroneContainer) DockManager() *drone.DockManager {
+	if c.dockManager == nil {
+		c.dockManager = drone.NewDockManager(c.Storage(), c.Storage())
+	}
+
+	return c.dockManager
+}
+
+func (c *DroneContainer) DockController() *dronehttp.DockController {
+	if c.dockController == nil {
+		c.dockController = dronehttp.NewDockController(c.Storage(), c.DockManager())
+	}
+
+	return c.dockController
+}
+
+func (c *DroneContainer) JobController() *dronehttp.JobController {
+	if c.jobController == nil {
+		c.jobController = dronehttp.NewJobController(c.Scheduler())
+	}
+
+	return c.jobController
+}
+
+func (c *DroneContainer) Policies() drone.Policies {
+	if c.config.Policies == nil {
+		return drone.Policies{Default: drone.DefaultPolicy()}
+	}
+
+	return *c.config.Policies
+}
+
+func (c *DroneContainer) DroneLocks() *drone.Locks {
+	if c.droneLocks == nil {
+		c.droneLocks = drone.NewLocks()
+	}
+
+	return c.droneLocks
+}
+
+func (c *DroneContainer) Assigner() *drone.Assigner {
+	if c.assigner == nil {
+		c.assigner = drone.NewAssigner(c.Storage(), c.DroneLocks(), c.Storage(), c.Policies(), c.config.Orders.Strategy, c.config.Orders.BatteryMargin)
+	}
+
+	return c.assigner
+}
+
+func (c *DroneContainer) OrderController() *dronehttp.OrderController {
+	if c.orderController == nil {
+		c.orderController = dronehttp.NewOrderController(c.Assigner(), c.AuditStore())
+	}
+
+	return c.orderController
+}
+
+func (c *DroneContainer) HealthController() *dronehttp.HealthController {
+	if c.healthController == nil {
+		c.healthController = dronehttp.NewHealthController(c.HealthChecker())
+	}
+
+	return c.healthController
+}
+
 func (c *DroneContainer) HTTPServer() *http.Server {
 	if c.httpServer == nil {
+		var tlsConfig *tls.Config
+		if c.config.HTTPServer.TLSCertFile != "" {
+			certificate, err := dronehttp.NewCertificateReloader(c.config.HTTPServer.TLSCertFile, c.config.HTTPServer.TLSKeyFile)
+			if err != nil {
+				panic(err)
+			}
+			tlsConfig = certificate.TLSConfig()
+		}
+
 		c.httpServer = &http.Server{
-			Addr:    c.config.HTTPServer.Addr,
-			Handler: c.Router(),
+			Addr:              c.config.HTTPServer.Addr,
+			Handler:           c.Router(),
+			ReadTimeout:       c.config.HTTPServer.ReadTimeout,
+			ReadHeaderTimeout: c.config.HTTPServer.ReadHeaderTimeout,
+			WriteTimeout:      c.config.HTTPServer.WriteTimeout,
+			IdleTimeout:       c.config.HTTPServer.IdleTimeout,
+			TLSConfig:         tlsConfig,
 		}
 	}
 	return c.httpServer
@@ -1,20 +1,53 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"io"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/hsequeda/drone/drone"
 	dronehttp "github.com/hsequeda/drone/http"
+	"github.com/hsequeda/drone/logging"
+	"github.com/hsequeda/drone/scheduler"
+	"github.com/hsequeda/drone/tracing"
+	"github.com/hsequeda/drone/webhook"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const (
+	// testTenant is the tenant authenticated by testAPIKey.
+	testTenant = "hospital-a"
+	// testAPIKey is the API key used by the e2e requests.
+	testAPIKey = "hospital-a-key"
+	// otherTenantAPIKey is an API key of a different tenant.
+	otherTenantAPIKey = "hospital-b-key"
+	// ordersTenant is the tenant of ordersAPIKey, isolating the drones chosen by the orders.
+	ordersTenant = "hospital-orders"
+	ordersAPIKey = "hospital-orders-key"
+	// splitTenant is the tenant of splitAPIKey, isolating the drones of the split orders.
+	splitTenant = "hospital-split"
+	splitAPIKey = "hospital-split-key"
+	// docksTenant is the tenant of docksAPIKey, isolating its docks from the returning drones of other tests.
+	docksTenant = "hospital-docks"
+	docksAPIKey = "hospital-docks-key"
+	// adminAPIKey is an admin API key of testTenant.
+	adminAPIKey = "admin-key"
 )
 
 // e2eSuite is a help struct to orchestate the e2e test.
@@ -22,6 +55,39 @@ import (
 type e2eSuite struct {
 	container  *DroneContainer
 	testServer *httptest.Server
+	spans      *tracetest.InMemoryExporter
+	logs       *syncBuffer
+}
+
+// syncBuffer is a bytes.Buffer safe for the concurrent writes of the logger.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// records returns the JSON log records written so far.
+func (b *syncBuffer) records(t *testing.T) []map[string]any {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var recs []map[string]any
+	for _, line := range bytes.Split(b.buf.Bytes(), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+
+		var rec map[string]any
+		require.NoError(t, json.Unmarshal(line, &rec))
+		recs = append(recs, rec)
+	}
+
+	return recs
 }
 
 func TestE2E(t *testing.T) {
@@ -37,6 +103,24 @@ func TestE2E(t *testing.T) {
 	t.Run("TestGetDroneMedications", s.TestGetDroneMedications)
 	t.Run("TestGetAvailableDrones", s.TestGetAvailableDrones)
 	t.Run("TestGetDroneBatteryLevel", s.TestGetDroneBatteryLevel)
+	t.Run("TestUnauthenticated", s.TestUnauthenticated)
+	t.Run("TestTenantIsolation", s.TestTenantIsolation)
+	t.Run("TestGetDroneAudit", s.TestGetDroneAudit)
+	t.Run("TestDispatchDroneEvents", s.TestDispatchDroneEvents)
+	t.Run("TestWebhooks", s.TestWebhooks)
+	t.Run("TestEventStream", s.TestEventStream)
+	t.Run("TestDroneLink", s.TestDroneLink)
+	t.Run("TestGetDroneBatteryHistory", s.TestGetDroneBatteryHistory)
+	t.Run("TestGetJobs", s.TestGetJobs)
+	t.Run("TestMetrics", s.TestMetrics)
+	t.Run("TestRequestLogging", s.TestRequestLogging)
+	t.Run("TestReadiness", s.TestReadiness)
+	t.Run("TestCreateOrder", s.TestCreateOrder)
+	t.Run("TestSplitOrder", s.TestSplitOrder)
+	t.Run("TestDroneLocation", s.TestDroneLocation)
+	t.Run("TestGeofences", s.TestGeofences)
+	t.Run("TestDocks", s.TestDocks)
+	t.Run("TestDroneMaintenance", s.TestDroneMaintenance)
 }
 
 func (s *e2eSuite) TestRegisterADrone(t *testing.T) {
@@ -48,15 +132,36 @@ func (s *e2eSuite) TestRegisterADrone(t *testing.T) {
 		Battery:     30,
 	})
 	require.NoError(t, err)
-	resp, err := http.Post(s.buildURL("/drone"), "application/json", bytes.NewBuffer(b))
-	require.NoError(t, err)
+	resp := s.do(t, http.MethodPost, "/drone", bytes.NewBuffer(b), testAPIKey)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	d, err := s.container.Storage().Drone(context.Background(), testTenant, "1")
+	require.NoError(t, err)
+	assert.Equal(t, testTenant, d.TenantID)
+
+	// the serial is taken, the drone is kept as it was
+	b, err = json.Marshal(dronehttp.RegisterDroneDTO{
+		Serial:      "1",
+		Model:       drone.Heavyweight,
+		WeightLimit: 500,
+		Battery:     100,
+	})
+	require.NoError(t, err)
+	resp = s.do(t, http.MethodPost, "/drone", bytes.NewBuffer(b), testAPIKey)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	d, err = s.container.Storage().Drone(context.Background(), testTenant, "1")
+	require.NoError(t, err)
+	assert.Equal(t, drone.Lightweight, d.Model)
+	assert.Equal(t, uint8(30), d.BatteryCapacity)
+	assert.Len(t, s.droneAudit(t, "1"), 1)
 }
 
 func (s *e2eSuite) TestAddMedication(t *testing.T) {
 	t.Parallel()
 	// setup storage data
 	err := s.container.Storage().SaveDrone(context.Background(), drone.Drone{
+		TenantID:        testTenant,
 		Serial:          "100",
 		Model:           drone.Lightweight,
 		WeightLimit:     400,
@@ -65,18 +170,59 @@ func (s *e2eSuite) TestAddMedication(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	b, err := json.Marshal(dronehttp.LoadMedicationDTO{
+	req := s.loadRequest(t, "100", dronehttp.LoadMedicationDTO{
 		Name:   "Omeprazol-250g",
 		Weight: 250,
 		Code:   "OM_250",
 	})
+	req.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var spans []string
+	for _, span := range s.spans.GetSpans() {
+		if span.SpanContext.TraceID().String() == "0af7651916cd43dd8448eb211c80319c" {
+			spans = append(spans, span.Name)
+		}
+	}
+	assert.ElementsMatch(t, []string{
+		"parse multipart form",
+		"saveFile",
+		"storage.drone",
+		"storage.save_drone",
+		"DroneController.LoadDrone",
+		"PUT /api/v1/drone/{serial}",
+	}, spans)
 
+	events := s.droneAudit(t, "100")
+	require.Len(t, events, 1)
+	assert.Equal(t, drone.AuditLoad, events[0].Action)
+	require.Len(t, events[0].Changes, 1)
+	assert.Equal(t, "Medications", events[0].Changes[0].Field)
+
+	// the picture is served only to its tenant
+	d, err := s.container.Storage().Drone(context.Background(), testTenant, "100")
 	require.NoError(t, err)
+	require.Len(t, d.Medications, 1)
+	picture := "/static/" + filepath.Base(d.Medications[0].Image)
+	resp = s.do(t, http.MethodGet, picture, nil, testAPIKey)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp = s.do(t, http.MethodGet, picture, nil, otherTenantAPIKey)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+// loadRequest builds the multipart request loading the medication, with the test image, into the drone.
+func (s *e2eSuite) loadRequest(t *testing.T, serial string, dto dronehttp.LoadMedicationDTO) *http.Request {
+	t.Helper()
+	b, err := json.Marshal(dto)
+	require.NoError(t, err)
+
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 	err = writer.WriteField("data", string(b))
 	require.NoError(t, err)
-	mediaPart, err := writer.CreateFormFile("picture", "OM_250")
+	mediaPart, err := writer.CreateFormFile("picture", dto.Code)
 	require.NoError(t, err)
 	mediaData, err := os.ReadFile("../../test/test_image.png")
 	require.NoError(t, err)
@@ -85,12 +231,128 @@ func (s *e2eSuite) TestAddMedication(t *testing.T) {
 
 	require.NoError(t, writer.Close())
 
-	req, err := http.NewRequest(http.MethodPut, s.buildURL("/drone/100"), bytes.NewReader(body.Bytes()))
+	req, err := http.NewRequest(http.MethodPut, s.buildURL("/drone/"+serial), bytes.NewReader(body.Bytes()))
+	require.NoError(t, err)
 	req.Header.Add("Content-Type", writer.FormDataContentType())
+	req.Header.Set("X-API-Key", testAPIKey)
+	return req
+}
+
+func (s *e2eSuite) TestValidationErrors(t *testing.T) {
+	t.Parallel()
+	fieldsOf := func(t *testing.T, resp *http.Response) map[string]dronehttp.FieldErrorDTO {
+		t.Helper()
+		require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+		var body dronehttp.ValidationErrorDTO
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return body.Fields
+	}
+
+	// every invalid field of the drone is reported at once
+	b, err := json.Marshal(dronehttp.RegisterDroneDTO{Model: drone.Model(9), WeightLimit: 100, Battery: 120})
 	require.NoError(t, err)
-	resp, err := http.DefaultClient.Do(req)
+	fields := fieldsOf(t, s.do(t, http.MethodPost, "/drone", bytes.NewBuffer(b), testAPIKey))
+	assert.Equal(t, map[string]dronehttp.FieldErrorDTO{
+		"serial":  {Code: drone.CodeRequired, Message: "serial is empty"},
+		"model":   {Code: drone.CodeUnknown, Message: "unknown model 9"},
+		"battery": {Code: drone.CodeOutOfRange, Message: "battery capacity exceed 100%"},
+	}, fields)
+
+	err = s.container.Storage().SaveDrone(context.Background(), drone.Drone{
+		TenantID:        testTenant,
+		Serial:          "4949",
+		Model:           drone.Lightweight,
+		WeightLimit:     400,
+		BatteryCapacity: 80,
+		State:           drone.Idle,
+	})
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(s.loadRequest(t, "4949", dronehttp.LoadMedicationDTO{Name: "bad name!", Weight: 10, Code: "bad"}))
+	require.NoError(t, err)
+	fields = fieldsOf(t, resp)
+	assert.Equal(t, drone.CodeInvalidFormat, fields["name"].Code)
+	assert.Equal(t, drone.CodeInvalidFormat, fields["code"].Code)
+
+	d, err := s.container.Storage().Drone(context.Background(), testTenant, "4949")
+	require.NoError(t, err)
+	assert.Empty(t, d.Medications)
+}
+
+func (s *e2eSuite) TestMedicationHandling(t *testing.T) {
+	t.Parallel()
+	b, err := json.Marshal(dronehttp.RegisterDroneDTO{
+		Serial:       "5050",
+		Model:        drone.Lightweight,
+		WeightLimit:  300,
+		Battery:      80,
+		Capabilities: []drone.Capability{drone.HazmatCertified},
+	})
 	require.NoError(t, err)
+	resp := s.do(t, http.MethodPost, "/drone", bytes.NewBuffer(b), testAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	load := func(dto dronehttp.LoadMedicationDTO) *http.Response {
+		resp, err := http.DefaultClient.Do(s.loadRequest(t, "5050", dto))
+		require.NoError(t, err)
+		return resp
+	}
+
+	// a lightweight drone has no insulated bay
+	resp = load(dronehttp.LoadMedicationDTO{Name: "Insulin", Weight: 50, Code: "IN_50", Handling: []drone.HandlingClass{drone.ColdChain}})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = load(dronehttp.LoadMedicationDTO{Name: "Solvent", Weight: 50, Code: "SO_50", Handling: []drone.HandlingClass{drone.Hazardous}, Incompatible: []string{"OX_50"}})
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = load(dronehttp.LoadMedicationDTO{Name: "Oxidizer", Weight: 50, Code: "OX_50"})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = load(dronehttp.LoadMedicationDTO{Name: "Aspirin", Weight: 50, Code: "AS_50", Handling: []drone.HandlingClass{"frozen"}})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	resp = s.do(t, http.MethodGet, "/drone/5050", nil, testAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var d dronehttp.DroneDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&d))
+	assert.Equal(t, []drone.Capability{drone.HazmatCertified}, d.Capabilities)
+
+	resp = s.do(t, http.MethodGet, "/drone/5050/medications", nil, testAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var meds []dronehttp.MedicationDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&meds))
+	require.Len(t, meds, 1)
+	assert.Equal(t, "SO_50", meds[0].Code)
+	assert.Equal(t, []drone.HandlingClass{drone.Hazardous}, meds[0].Handling)
+	assert.Equal(t, []string{"OX_50"}, meds[0].Incompatible)
+
+	setCapabilities := func(caps ...drone.Capability) *http.Response {
+		b, err := json.Marshal(dronehttp.SetDroneCapabilitiesDTO{Capabilities: caps})
+		require.NoError(t, err)
+		return s.do(t, http.MethodPut, "/drone/5050/capabilities", bytes.NewReader(b), testAPIKey)
+	}
+
+	// the loaded solvent needs the hazmat certification
+	resp = setCapabilities(drone.InsulatedBay)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = setCapabilities("teleporter")
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	resp = setCapabilities(drone.HazmatCertified, drone.InsulatedBay)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var caps dronehttp.DroneCapabilitiesDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&caps))
+	assert.Equal(t, []drone.Capability{drone.InsulatedBay, drone.HazmatCertified}, caps.Capabilities)
+
+	// the insulin can go now
+	resp = load(dronehttp.LoadMedicationDTO{Name: "Insulin", Weight: 50, Code: "IN_50", Handling: []drone.HandlingClass{drone.ColdChain}})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	audit := s.droneAudit(t, "5050")
+	require.NotEmpty(t, audit)
+	assert.Equal(t, drone.AuditLoad, audit[len(audit)-1].Action)
+	assert.Equal(t, drone.AuditCapabilities, audit[len(audit)-2].Action)
 }
 
 func (s *e2eSuite) TestGetDroneMedications(t *testing.T) {
@@ -99,6 +361,7 @@ func (s *e2eSuite) TestGetDroneMedications(t *testing.T) {
 	m1 := drone.Medication{Name: "Omeprazol-250g", Weight: 250, Code: "OM_250", Image: "image_path"}
 	m2 := drone.Medication{Name: "Advil-250g", Weight: 500, Code: "AD_500", Image: "another_image_path"}
 	err := s.container.Storage().SaveDrone(context.Background(), drone.Drone{
+		TenantID:        testTenant,
 		Serial:          "102",
 		Model:           drone.Lightweight,
 		WeightLimit:     400,
@@ -108,8 +371,7 @@ func (s *e2eSuite) TestGetDroneMedications(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	resp, err := http.Get(s.buildURL("/drone/102/medications"))
-	require.NoError(t, err)
+	resp := s.do(t, http.MethodGet, "/drone/102/medications", nil, testAPIKey)
 	var body []dronehttp.MedicationDTO
 	err = json.NewDecoder(resp.Body).Decode(&body)
 	require.NoError(t, err)
@@ -123,6 +385,7 @@ func (s *e2eSuite) TestGetAvailableDrones(t *testing.T) {
 	t.Parallel()
 	// setup storage data
 	availableD1 := drone.Drone{
+		TenantID:        testTenant,
 		Serial:          "111",
 		Model:           drone.Cruiserweight,
 		WeightLimit:     400,
@@ -132,6 +395,7 @@ func (s *e2eSuite) TestGetAvailableDrones(t *testing.T) {
 	err := s.container.Storage().SaveDrone(context.Background(), availableD1)
 	require.NoError(t, err)
 	availableD2 := drone.Drone{
+		TenantID:        testTenant,
 		Serial:          "112",
 		Model:           drone.Cruiserweight,
 		WeightLimit:     200,
@@ -141,6 +405,7 @@ func (s *e2eSuite) TestGetAvailableDrones(t *testing.T) {
 	err = s.container.Storage().SaveDrone(context.Background(), availableD2)
 	require.NoError(t, err)
 	unavailableD1 := drone.Drone{ // low battery
+		TenantID:        testTenant,
 		Serial:          "113",
 		Model:           drone.Cruiserweight,
 		WeightLimit:     200,
@@ -150,6 +415,7 @@ func (s *e2eSuite) TestGetAvailableDrones(t *testing.T) {
 	err = s.container.Storage().SaveDrone(context.Background(), unavailableD1)
 	require.NoError(t, err)
 	unavailableD2 := drone.Drone{ // invalid state
+		TenantID:        testTenant,
 		Serial:          "114",
 		Model:           drone.Cruiserweight,
 		WeightLimit:     200,
@@ -159,6 +425,7 @@ func (s *e2eSuite) TestGetAvailableDrones(t *testing.T) {
 	err = s.container.Storage().SaveDrone(context.Background(), unavailableD2)
 	require.NoError(t, err)
 	unavailableD3 := drone.Drone{ // WeightLimit reached
+		TenantID:        testTenant,
 		Serial:          "115",
 		Model:           drone.Cruiserweight,
 		WeightLimit:     250,
@@ -169,15 +436,13 @@ func (s *e2eSuite) TestGetAvailableDrones(t *testing.T) {
 	err = s.container.Storage().SaveDrone(context.Background(), unavailableD3)
 	require.NoError(t, err)
 
-	resp, err := http.Get(s.buildURL("/drones"))
-	require.NoError(t, err)
-	require.NoError(t, err)
+	resp := s.do(t, http.MethodGet, "/drones", nil, testAPIKey)
 	var body []dronehttp.AvailableDroneDTO
 	err = json.NewDecoder(resp.Body).Decode(&body)
 	require.NoError(t, err)
 	assert.GreaterOrEqual(t, len(body), 2) // could be more than 3 (because we're sharing the Storage with the rest of the test)
 	for _, add := range body {
-		drone, err := s.container.Storage().Drone(context.Background(), add.Serial)
+		drone, err := s.container.Storage().Drone(context.Background(), testTenant, add.Serial)
 		require.NoError(t, err)
 		s.assertAvailableDrone(t, drone, add)
 	}
@@ -189,6 +454,7 @@ func (s *e2eSuite) TestGetDroneBatteryLevel(t *testing.T) {
 	t.Parallel()
 	// setup storage data
 	d := drone.Drone{
+		TenantID:        testTenant,
 		Serial:          "1010",
 		Model:           drone.Cruiserweight,
 		WeightLimit:     400,
@@ -198,8 +464,7 @@ func (s *e2eSuite) TestGetDroneBatteryLevel(t *testing.T) {
 	err := s.container.Storage().SaveDrone(context.Background(), d)
 	require.NoError(t, err)
 
-	resp, err := http.Get(s.buildURL("/drone/1010/battery"))
-	require.NoError(t, err)
+	resp := s.do(t, http.MethodGet, "/drone/1010/battery", nil, testAPIKey)
 	var body dronehttp.DroneBatteryLevelDTO
 	err = json.NewDecoder(resp.Body).Decode(&body)
 	require.NoError(t, err)
@@ -207,12 +472,941 @@ func (s *e2eSuite) TestGetDroneBatteryLevel(t *testing.T) {
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
+func (s *e2eSuite) TestRequestLogging(t *testing.T) {
+	t.Parallel()
+	resp := s.do(t, http.MethodGet, "/drone/7777", nil, testAPIKey)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	var failed, served map[string]any
+	for _, rec := range s.logs.records(t) {
+		switch {
+		case rec["msg"] == "request failed" && rec["serial"] == "7777":
+			failed = rec
+		case rec["msg"] == "request served" && rec["path"] == "/api/v1/drone/7777":
+			served = rec
+		}
+	}
+
+	require.NotNil(t, failed)
+	require.NotNil(t, served)
+	assert.Equal(t, "WARN", failed["level"])
+	assert.Equal(t, testTenant, failed["tenant_id"])
+	assert.Equal(t, drone.ErrNotFound.Error(), failed["error"])
+	assert.NotEmpty(t, failed["request_id"])
+	assert.Equal(t, failed["request_id"], served["request_id"])
+	assert.Equal(t, float64(http.StatusBadRequest), served["status"])
+}
+
+func (s *e2eSuite) TestCreateOrder(t *testing.T) {
+	t.Parallel()
+	// setup storage data
+	for _, d := range []drone.Drone{
+		{TenantID: ordersTenant, Serial: "8080", Model: drone.Heavyweight, WeightLimit: 500, BatteryCapacity: 90, State: drone.Idle},
+		{TenantID: ordersTenant, Serial: "8081", Model: drone.Lightweight, WeightLimit: 200, BatteryCapacity: 90, State: drone.Idle},
+		{TenantID: ordersTenant, Serial: "8082", Model: drone.Lightweight, WeightLimit: 200, BatteryCapacity: 30, State: drone.Idle},
+	} {
+		require.NoError(t, s.container.Storage().SaveDrone(context.Background(), d))
+	}
+
+	order := func(dto dronehttp.CreateOrderDTO) *http.Response {
+		b, err := json.Marshal(dto)
+		require.NoError(t, err)
+		return s.do(t, http.MethodPost, "/orders", bytes.NewBuffer(b), ordersAPIKey)
+	}
+
+	resp := order(dronehttp.CreateOrderDTO{Medications: []dronehttp.OrderMedicationDTO{
+		{Name: "Ibuprofen-100g", Weight: 100, Code: "IB_100"},
+		{Name: "Aspirin-50g", Weight: 50, Code: "AS_50"},
+	}})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var assignment dronehttp.OrderAssignmentDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&assignment))
+	assert.Equal(t, "8081", assignment.Serial) // the best fit with battery margin
+	assert.Equal(t, uint32(150), assignment.ConsumedWeight)
+	require.Len(t, assignment.Medications, 2)
+
+	d, err := s.container.Storage().Drone(context.Background(), ordersTenant, "8081")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(150), d.MedicationWeight())
+	events, err := s.container.AuditStore().AuditEvents(context.Background(), ordersTenant, "8081")
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, drone.AuditLoad, events[0].Action)
+
+	// the drone of the previous order can't carry 100g more
+	resp = order(dronehttp.CreateOrderDTO{Medications: []dronehttp.OrderMedicationDTO{{Name: "Omeprazol-100g", Weight: 100, Code: "OM_100"}}})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&assignment))
+	assert.Equal(t, "8080", assignment.Serial)
+
+	resp = order(dronehttp.CreateOrderDTO{Model: drone.Lightweight, Medications: []dronehttp.OrderMedicationDTO{{Name: "Omeprazol-100g", Weight: 100, Code: "OM_100"}}})
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	resp = order(dronehttp.CreateOrderDTO{})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	meds := []dronehttp.OrderMedicationDTO{{Name: "Aspirin-10g", Weight: 10, Code: "AS_10"}}
+
+	resp = order(dronehttp.CreateOrderDTO{Medications: []dronehttp.OrderMedicationDTO{meds[0], {Name: "bad name!", Weight: 10, Code: "BAD"}}})
+	require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	var invalid dronehttp.ValidationErrorDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&invalid))
+	assert.Equal(t, map[string]dronehttp.FieldErrorDTO{
+		"medications[1].name": {Code: drone.CodeInvalidFormat, Message: "name only allows letters, numbers, '-' and '_'"},
+	}, invalid.Fields)
+
+	// no drone has a home to compute the range to the destination
+	resp = order(dronehttp.CreateOrderDTO{Medications: meds, Destination: &dronehttp.CoordinatesDTO{Latitude: 40.4268, Longitude: -3.7038}})
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	resp = order(dronehttp.CreateOrderDTO{Medications: meds, Destination: &dronehttp.CoordinatesDTO{Longitude: 181}})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *e2eSuite) TestSplitOrder(t *testing.T) {
+	t.Parallel()
+	// setup storage data
+	for _, d := range []drone.Drone{
+		{TenantID: splitTenant, Serial: "8090", Model: drone.Heavyweight, WeightLimit: 500, BatteryCapacity: 90, State: drone.Idle},
+		{TenantID: splitTenant, Serial: "8091", Model: drone.Cruiserweight, WeightLimit: 400, BatteryCapacity: 90, State: drone.Idle},
+		{TenantID: splitTenant, Serial: "8092", Model: drone.Lightweight, WeightLimit: 200, BatteryCapacity: 90, State: drone.Idle},
+	} {
+		require.NoError(t, s.container.Storage().SaveDrone(context.Background(), d))
+	}
+
+	manifest := dronehttp.CreateOrderDTO{Medications: []dronehttp.OrderMedicationDTO{
+		{Name: "Insulin-300g", Weight: 300, Code: "IN_300"},
+		{Name: "Saline-350g", Weight: 350, Code: "SA_350"},
+		{Name: "Aspirin-150g", Weight: 150, Code: "AS_150"},
+	}}
+	b, err := json.Marshal(manifest)
+	require.NoError(t, err)
+
+	// the dry run plans 800g in the two biggest drones
+	resp := s.do(t, http.MethodPost, "/orders/plan", bytes.NewReader(b), splitAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var plan dronehttp.OrderPlanDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&plan))
+	assert.True(t, plan.Complete)
+	assert.Equal(t, 2, plan.Drones)
+	require.Len(t, plan.Loads, 2)
+	assert.Equal(t, "8090", plan.Loads[0].Serial)
+	assert.Equal(t, uint32(500), plan.Loads[0].PlannedWeight)
+	assert.Equal(t, "8091", plan.Loads[1].Serial)
+	assert.Equal(t, uint32(300), plan.Loads[1].PlannedWeight)
+	d, err := s.container.Storage().Drone(context.Background(), splitTenant, "8090")
+	require.NoError(t, err)
+	assert.Empty(t, d.Medications)
+
+	resp = s.do(t, http.MethodPost, "/orders/split", bytes.NewReader(b), splitAPIKey)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var committed dronehttp.OrderPlanDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&committed))
+	assert.Equal(t, plan, committed)
+	for _, l := range committed.Loads {
+		d, err := s.container.Storage().Drone(context.Background(), splitTenant, l.Serial)
+		require.NoError(t, err)
+		assert.Equal(t, l.PlannedWeight, d.MedicationWeight())
+		events, err := s.container.AuditStore().AuditEvents(context.Background(), splitTenant, l.Serial)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, drone.AuditLoad, events[0].Action)
+	}
+
+	// only the 200g of 8092 and 100g of 8091 are left
+	resp = s.do(t, http.MethodPost, "/orders/plan", bytes.NewReader(b), splitAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&plan))
+	assert.False(t, plan.Complete)
+	assert.Len(t, plan.Unassigned, 2)
+
+	resp = s.do(t, http.MethodPost, "/orders/split", bytes.NewReader(b), splitAPIKey)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
+
+func (s *e2eSuite) TestDroneLocation(t *testing.T) {
+	t.Parallel()
+	home := dronehttp.CoordinatesDTO{Latitude: 40.4168, Longitude: -3.7038}
+	b, err := json.Marshal(dronehttp.RegisterDroneDTO{
+		Serial:      "9090",
+		Model:       drone.Heavyweight,
+		WeightLimit: 500,
+		Battery:     90,
+		Home:        &home,
+	})
+	require.NoError(t, err)
+	resp := s.do(t, http.MethodPost, "/drone", bytes.NewBuffer(b), testAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	destination := dronehttp.CoordinatesDTO{Latitude: 40.4268, Longitude: -3.7038}
+	b, err = json.Marshal(destination)
+	require.NoError(t, err)
+	resp = s.do(t, http.MethodPut, "/drone/9090/destination", bytes.NewBuffer(b), testAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	d := s.getDrone(t, "9090")
+	assert.Equal(t, &home, d.Home)
+	assert.Equal(t, &destination, d.Destination)
+
+	b, err = json.Marshal(dronehttp.CoordinatesDTO{Latitude: 91})
+	require.NoError(t, err)
+	resp = s.do(t, http.MethodPut, "/drone/9090/home", bytes.NewBuffer(b), testAPIKey)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	audit := s.droneAudit(t, "9090")
+	require.NotEmpty(t, audit)
+	assert.Equal(t, drone.AuditLocation, audit[len(audit)-1].Action)
+}
+
+func (s *e2eSuite) TestGeofences(t *testing.T) {
+	t.Parallel()
+	// setup storage data, far from the drones of the other tests
+	home := drone.Coordinates{Latitude: 10.005, Longitude: 10}
+	require.NoError(t, s.container.Storage().SaveDrone(context.Background(), drone.Drone{
+		TenantID: testTenant, Serial: "9191", Model: drone.Lightweight, WeightLimit: 200, BatteryCapacity: 90, State: drone.Idle, Home: &home,
+	}))
+
+	b, err := json.Marshal(dronehttp.GeofenceDTO{
+		Type:       "Feature",
+		Properties: dronehttp.GeofencePropertiesDTO{Name: "Airport"},
+		Geometry: dronehttp.PolygonDTO{Type: "Polygon", Coordinates: [][][]float64{{
+			{10.01, 10}, {10.02, 10}, {10.02, 10.02}, {10.01, 10.02}, {10.01, 10},
+		}}},
+	})
+	require.NoError(t, err)
+	resp := s.do(t, http.MethodPost, "/geofences", bytes.NewBuffer(b), testAPIKey)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var zone dronehttp.GeofenceDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&zone))
+	assert.NotEmpty(t, zone.ID)
+
+	resp = s.do(t, http.MethodGet, "/geofences", nil, testAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var collection dronehttp.GeofenceCollectionDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&collection))
+	assert.Equal(t, "FeatureCollection", collection.Type)
+	assert.Contains(t, collection.Features, zone)
+	resp = s.do(t, http.MethodGet, "/geofences", nil, otherTenantAPIKey)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&collection))
+	assert.Empty(t, collection.Features)
+
+	setDestination := func(c dronehttp.CoordinatesDTO) *http.Response {
+		b, err := json.Marshal(c)
+		require.NoError(t, err)
+		return s.do(t, http.MethodPut, "/drone/9191/destination", bytes.NewBuffer(b), testAPIKey)
+	}
+
+	// inside the zone
+	assert.Equal(t, http.StatusBadRequest, setDestination(dronehttp.CoordinatesDTO{Latitude: 10.01, Longitude: 10.015}).StatusCode)
+
+	// behind the zone, the route goes around it
+	require.Equal(t, http.StatusOK, setDestination(dronehttp.CoordinatesDTO{Latitude: 10.005, Longitude: 10.03}).StatusCode)
+	assert.NotEmpty(t, s.getDrone(t, "9191").Waypoints)
+
+	resp = s.do(t, http.MethodDelete, "/geofences/"+zone.ID, nil, testAPIKey)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp = s.do(t, http.MethodDelete, "/geofences/"+zone.ID, nil, testAPIKey)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	b, err = json.Marshal(dronehttp.GeofenceDTO{Type: "Feature", Geometry: dronehttp.PolygonDTO{Type: "Point"}})
+	require.NoError(t, err)
+	resp = s.do(t, http.MethodPost, "/geofences", bytes.NewBuffer(b), testAPIKey)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *e2eSuite) TestDocks(t *testing.T) {
+	t.Parallel()
+	register := func(dto dronehttp.RegisterDockDTO) *http.Response {
+		b, err := json.Marshal(dto)
+		require.NoError(t, err)
+		return s.do(t, http.MethodPost, "/docks", bytes.NewBuffer(b), docksAPIKey)
+	}
+
+	location := dronehttp.CoordinatesDTO{Latitude: 41.3851, Longitude: 2.1734}
+	assert.Equal(t, http.StatusBadRequest, register(dronehttp.RegisterDockDTO{Name: "Roof", Location: &location, ChargeRate: 2}).StatusCode)
+	resp := register(dronehttp.RegisterDockDTO{Name: "Roof", Location: &location, Capacity: 1, ChargeRate: 2})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var dock dronehttp.DockDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&dock))
+	assert.NotEmpty(t, dock.ID)
+
+	require.NoError(t, s.container.Storage().SaveDrone(context.Background(), drone.Drone{
+		TenantID: docksTenant, Serial: "5050", Model: drone.Lightweight, WeightLimit: 200, BatteryCapacity: 40, State: drone.Delivered,
+		Home: &drone.Coordinates{Latitude: 41.3861, Longitude: 2.1734},
+	}))
+	wsURL := "ws" + strings.TrimPrefix(s.buildURL("/drone/5050/link"), "http")
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, s.linkHeader(t, "5050", docksAPIKey))
+	require.NoError(t, err)
+	defer conn.Close()
+	telemetry := func(msg dronehttp.LinkMessageDTO) {
+		msg.Type = dronehttp.LinkTelemetry
+		require.NoError(t, conn.WriteJSON(msg))
+		var ack dronehttp.LinkMessageDTO
+		require.NoError(t, conn.ReadJSON(&ack))
+		require.Equal(t, dronehttp.LinkAck, ack.Type, ack.Error)
+	}
+	getDrone := func() dronehttp.DroneDTO {
+		resp := s.do(t, http.MethodGet, "/drone/5050", nil, docksAPIKey)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var d dronehttp.DroneDTO
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&d))
+		return d
+	}
+
+	// the returning drone is assigned to the dock and charges once landed
+	returning, idle := drone.Returning, drone.Idle
+	telemetry(dronehttp.LinkMessageDTO{State: &returning})
+	assert.Equal(t, dock.ID, getDrone().DockID)
+	telemetry(dronehttp.LinkMessageDTO{State: &idle})
+	assert.True(t, getDrone().Charging)
+
+	resp = s.do(t, http.MethodGet, "/docks", nil, docksAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var docks []dronehttp.DockDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&docks))
+	require.Len(t, docks, 1)
+	assert.Equal(t, 1, docks[0].Docked)
+	assert.Equal(t, 1, docks[0].Charging)
+
+	resp = s.do(t, http.MethodGet, "/docks/"+dock.ID, nil, docksAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var detail dronehttp.DockDetailDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&detail))
+	require.Len(t, detail.Drones, 1)
+	assert.Equal(t, "5050", detail.Drones[0].Serial)
+	assert.NotNil(t, detail.Drones[0].EstimatedChargedAt)
+
+	// a charging drone isn't available
+	resp = s.do(t, http.MethodGet, "/drones", nil, docksAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var available []dronehttp.AvailableDroneDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&available))
+	assert.Empty(t, available)
+
+	assert.Equal(t, http.StatusConflict, s.do(t, http.MethodDelete, "/docks/"+dock.ID, nil, docksAPIKey).StatusCode)
+
+	// the session ends at the charged level and the drone leaves the dock on delivery
+	battery := drone.ChargedLevel
+	telemetry(dronehttp.LinkMessageDTO{Battery: &battery})
+	assert.False(t, getDrone().Charging)
+	for _, state := range []drone.State{drone.Loading, drone.Loaded, drone.Delivering} {
+		state := state
+		telemetry(dronehttp.LinkMessageDTO{State: &state})
+	}
+	assert.Empty(t, getDrone().DockID)
+
+	resp = s.do(t, http.MethodGet, "/docks/"+dock.ID+"/sessions", nil, docksAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var sessions []dronehttp.ChargingSessionDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&sessions))
+	require.Len(t, sessions, 1)
+	assert.Equal(t, uint8(40), sessions[0].StartBattery)
+	require.NotNil(t, sessions[0].EndBattery)
+	assert.Equal(t, drone.ChargedLevel, *sessions[0].EndBattery)
+
+	assert.Equal(t, http.StatusNotFound, s.do(t, http.MethodGet, "/docks/"+dock.ID, nil, otherTenantAPIKey).StatusCode)
+	assert.Equal(t, http.StatusNoContent, s.do(t, http.MethodDelete, "/docks/"+dock.ID, nil, docksAPIKey).StatusCode)
+	assert.Equal(t, http.StatusNotFound, s.do(t, http.MethodDelete, "/docks/"+dock.ID, nil, docksAPIKey).StatusCode)
+}
+
+func (s *e2eSuite) TestDroneMaintenance(t *testing.T) {
+	t.Parallel()
+	// setup storage data
+	require.NoError(t, s.container.Storage().SaveDrone(context.Background(), drone.Drone{
+		TenantID: testTenant, Serial: "9393", Model: drone.Lightweight, WeightLimit: 200, BatteryCapacity: 90, State: drone.Loaded,
+		Medications: []drone.Medication{{Name: "Aspirin", Weight: 50, Code: "A01"}},
+	}))
+	setMaintenance := func(dto dronehttp.SetDroneMaintenanceDTO) *http.Response {
+		b, err := json.Marshal(dto)
+		require.NoError(t, err)
+		return s.do(t, http.MethodPut, "/drone/9393/maintenance", bytes.NewBuffer(b), testAPIKey)
+	}
+
+	// the drone is grounded once the delivery completes the cycle limit (1 in the e2e)
+	wsURL := "ws" + strings.TrimPrefix(s.buildURL("/drone/9393/link"), "http")
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, s.linkHeader(t, "9393", testAPIKey))
+	require.NoError(t, err)
+	defer conn.Close()
+	for _, state := range []drone.State{drone.Delivering, drone.Delivered, drone.Returning, drone.Idle} {
+		state := state
+		require.NoError(t, conn.WriteJSON(dronehttp.LinkMessageDTO{Type: dronehttp.LinkTelemetry, State: &state}))
+		var ack dronehttp.LinkMessageDTO
+		require.NoError(t, conn.ReadJSON(&ack))
+		require.Equal(t, dronehttp.LinkAck, ack.Type, ack.Error)
+	}
+
+	d := s.getDrone(t, "9393")
+	assert.True(t, d.Maintenance.Grounded)
+	assert.Equal(t, "cycle limit reached", d.Maintenance.Reason)
+	assert.Equal(t, uint32(1), d.Maintenance.Cycles)
+	resp := s.do(t, http.MethodGet, "/drones", nil, testAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var available []dronehttp.AvailableDroneDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&available))
+	for _, a := range available {
+		assert.NotEqual(t, "9393", a.Serial)
+	}
+
+	// the service returns it to service
+	assert.Equal(t, http.StatusBadRequest, setMaintenance(dronehttp.SetDroneMaintenanceDTO{}).StatusCode)
+	grounded := false
+	resp = setMaintenance(dronehttp.SetDroneMaintenanceDTO{Grounded: &grounded})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var m dronehttp.MaintenanceDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&m))
+	assert.False(t, m.Grounded)
+	assert.Equal(t, uint32(1), m.Cycles)
+	assert.Zero(t, m.CyclesSinceService)
+	assert.NotNil(t, m.LastServiceAt)
+
+	// and the operators ground it with a reason
+	grounded = true
+	assert.Equal(t, http.StatusBadRequest, setMaintenance(dronehttp.SetDroneMaintenanceDTO{Grounded: &grounded}).StatusCode)
+	require.Equal(t, http.StatusOK, setMaintenance(dronehttp.SetDroneMaintenanceDTO{Grounded: &grounded, Reason: "camera repair"}).StatusCode)
+	assert.Equal(t, "camera repair", s.getDrone(t, "9393").Maintenance.Reason)
+
+	events := s.droneAudit(t, "9393")
+	require.NotEmpty(t, events)
+	assert.Equal(t, drone.AuditMaintenance, events[len(events)-1].Action)
+}
+
+func (s *e2eSuite) TestReadiness(t *testing.T) {
+	t.Parallel()
+	resp, err := http.Get(s.testServer.URL + "/livez")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(s.testServer.URL + "/readyz")
+	require.NoError(t, err)
+	var body dronehttp.ReadinessDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Equal(t, "up", body.Status)
+	var names []string
+	for _, c := range body.Checks {
+		names = append(names, c.Name)
+		assert.Equal(t, "up", c.Status, c.Error)
+	}
+	assert.Equal(t, []string{"storage", "upload_dir", "upload_disk", "storage_disk"}, names)
+}
+
+func (s *e2eSuite) TestUnauthenticated(t *testing.T) {
+	t.Parallel()
+	resp, err := http.Get(s.buildURL("/drones"))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp = s.do(t, http.MethodGet, "/drones", nil, "unknown-key")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (s *e2eSuite) TestTenantIsolation(t *testing.T) {
+	t.Parallel()
+	// setup storage data
+	d := drone.Drone{
+		TenantID:        testTenant,
+		Serial:          "2020",
+		Model:           drone.Middleweight,
+		WeightLimit:     300,
+		BatteryCapacity: 90,
+		State:           drone.Idle,
+	}
+	err := s.container.Storage().SaveDrone(context.Background(), d)
+	require.NoError(t, err)
+
+	resp := s.do(t, http.MethodGet, "/drone/2020/battery", nil, otherTenantAPIKey)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = s.do(t, http.MethodGet, "/drones", nil, otherTenantAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var body []dronehttp.AvailableDroneDTO
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	require.NoError(t, err)
+	for _, add := range body {
+		assert.NotEqual(t, d.Serial, add.Serial)
+	}
+}
+
+func (s *e2eSuite) TestGetDroneAudit(t *testing.T) {
+	t.Parallel()
+	b, err := json.Marshal(dronehttp.RegisterDroneDTO{
+		Serial:      "3030",
+		Model:       drone.Heavyweight,
+		WeightLimit: 500,
+		Battery:     100,
+	})
+	require.NoError(t, err)
+	resp := s.do(t, http.MethodPost, "/drone", bytes.NewBuffer(b), testAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	events := s.droneAudit(t, "3030")
+	require.Len(t, events, 1)
+	assert.Equal(t, "e2e", events[0].Actor)
+	assert.Equal(t, "3030", events[0].Serial)
+	assert.Equal(t, drone.AuditRegister, events[0].Action)
+	assert.NotEmpty(t, events[0].RequestID)
+	assert.NotEmpty(t, events[0].Changes)
+
+	// the audit of a drone can't be read from another tenant
+	resp = s.do(t, http.MethodGet, "/drone/3030/audit", nil, otherTenantAPIKey)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *e2eSuite) TestDispatchDroneEvents(t *testing.T) {
+	t.Parallel()
+	var (
+		mu         sync.Mutex
+		registered []drone.Event
+	)
+	s.container.Dispatcher().Subscribe(drone.DroneRegistered, func(_ context.Context, e drone.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if e.Serial == "4040" {
+			registered = append(registered, e)
+		}
+		return nil
+	})
+
+	b, err := json.Marshal(dronehttp.RegisterDroneDTO{
+		Serial:      "4040",
+		Model:       drone.Middleweight,
+		WeightLimit: 300,
+		Battery:     90,
+	})
+	require.NoError(t, err)
+	resp := s.do(t, http.MethodPost, "/drone", bytes.NewBuffer(b), testAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.NoError(t, s.container.Dispatcher().Dispatch(context.Background()))
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, registered, 1)
+	assert.Equal(t, testTenant, registered[0].TenantID)
+}
+
+func (s *e2eSuite) TestWebhooks(t *testing.T) {
+	t.Parallel()
+	received := make(chan webhook.Payload, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !webhook.Verify("s3cr3t", body, r.Header.Get(webhook.TimestampHeader), r.Header.Get(webhook.SignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var p webhook.Payload
+		_ = json.Unmarshal(body, &p)
+		if p.Serial == "5050" {
+			received <- p
+		}
+	}))
+	t.Cleanup(receiver.Close)
+
+	b, err := json.Marshal(dronehttp.RegisterWebhookDTO{
+		URL:    receiver.URL,
+		Secret: "s3cr3t",
+		Events: []drone.EventType{drone.DroneRegistered},
+	})
+	require.NoError(t, err)
+	resp := s.do(t, http.MethodPost, "/webhooks", bytes.NewBuffer(b), testAPIKey)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var wh dronehttp.WebhookDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&wh))
+
+	// the webhook isn't visible from another tenant
+	resp = s.do(t, http.MethodGet, "/webhooks", nil, otherTenantAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var otherWebhooks []dronehttp.WebhookDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&otherWebhooks))
+	for _, ow := range otherWebhooks {
+		assert.NotEqual(t, wh.ID, ow.ID)
+	}
+
+	b, err = json.Marshal(dronehttp.RegisterDroneDTO{
+		Serial:      "5050",
+		Model:       drone.Middleweight,
+		WeightLimit: 300,
+		Battery:     90,
+	})
+	require.NoError(t, err)
+	resp = s.do(t, http.MethodPost, "/drone", bytes.NewBuffer(b), testAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, s.container.Dispatcher().Dispatch(context.Background()))
+
+	select {
+	case p := <-received:
+		assert.Equal(t, drone.DroneRegistered, p.Type)
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not delivered")
+	}
+
+	require.Eventually(t, func() bool {
+		resp := s.do(t, http.MethodGet, "/webhooks/"+wh.ID+"/deliveries", nil, testAPIKey)
+		var deliveries []dronehttp.WebhookDeliveryDTO
+		_ = json.NewDecoder(resp.Body).Decode(&deliveries)
+		return len(deliveries) > 0 && deliveries[0].Succeeded
+	}, 5*time.Second, 10*time.Millisecond)
+
+	resp = s.do(t, http.MethodDelete, "/webhooks/"+wh.ID, nil, otherTenantAPIKey)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp = s.do(t, http.MethodDelete, "/webhooks/"+wh.ID, nil, testAPIKey)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *e2eSuite) TestEventStream(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	resp := s.openStream(t, ctx, "/events", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	b, err := json.Marshal(dronehttp.RegisterDroneDTO{
+		Serial:      "6060",
+		Model:       drone.Middleweight,
+		WeightLimit: 300,
+		Battery:     90,
+	})
+	require.NoError(t, err)
+	registerResp := s.do(t, http.MethodPost, "/drone", bytes.NewBuffer(b), testAPIKey)
+	require.Equal(t, http.StatusOK, registerResp.StatusCode)
+	require.NoError(t, s.container.Dispatcher().Dispatch(context.Background()))
+
+	reader := bufio.NewReader(resp.Body)
+	var registered dronehttp.DroneEventDTO
+	for registered.Serial != "6060" {
+		registered = readStreamEvent(t, reader)
+	}
+	assert.Equal(t, drone.DroneRegistered, registered.Type)
+
+	// the drone stream resumes from the Last-Event-ID
+	d, err := s.container.Storage().Drone(context.Background(), testTenant, "6060")
+	require.NoError(t, err)
+	d.UpdateBattery(70)
+	require.NoError(t, s.container.Storage().SaveDrone(context.Background(), d))
+	require.NoError(t, s.container.Dispatcher().Dispatch(context.Background()))
+
+	droneResp := s.openStream(t, ctx, "/drone/6060/events", registered.ID)
+	require.Equal(t, http.StatusOK, droneResp.StatusCode)
+	droneReader := bufio.NewReader(droneResp.Body)
+	batteryChanged := readStreamEvent(t, droneReader)
+	assert.Equal(t, drone.BatteryChanged, batteryChanged.Type)
+	assert.Equal(t, uint8(70), batteryChanged.BatteryLevel)
+
+	// an event redelivered by the outbox is sent once
+	require.NoError(t, s.container.EventStream().Publish(context.Background(), drone.Event{
+		ID:           batteryChanged.ID,
+		TenantID:     testTenant,
+		Serial:       "6060",
+		Type:         drone.BatteryChanged,
+		BatteryLevel: 70,
+	}))
+	d.UpdateBattery(65)
+	require.NoError(t, s.container.Storage().SaveDrone(context.Background(), d))
+	require.NoError(t, s.container.Dispatcher().Dispatch(context.Background()))
+	next := readStreamEvent(t, droneReader)
+	assert.NotEqual(t, batteryChanged.ID, next.ID)
+	assert.Equal(t, uint8(65), next.BatteryLevel)
+
+	// the drones of another tenant can't be streamed
+	otherResp := s.do(t, http.MethodGet, "/drone/6060/events", nil, otherTenantAPIKey)
+	assert.Equal(t, http.StatusBadRequest, otherResp.StatusCode)
+}
+
+func (s *e2eSuite) TestDroneLink(t *testing.T) {
+	t.Parallel()
+	// setup storage data
+	err := s.container.Storage().SaveDrone(context.Background(), drone.Drone{
+		TenantID:        testTenant,
+		Serial:          "7070",
+		Model:           drone.Heavyweight,
+		WeightLimit:     500,
+		BatteryCapacity: 90,
+		State:           drone.Loading,
+		Medications:     []drone.Medication{{Name: "Aspirin", Weight: 50, Code: "A01"}},
+	})
+	require.NoError(t, err)
+
+	// a command can't be sent while the drone is offline
+	b, err := json.Marshal(dronehttp.DroneCommandDTO{Command: drone.Dispatch})
+	require.NoError(t, err)
+	resp := s.do(t, http.MethodPost, "/drone/7070/commands", bytes.NewReader(b), testAPIKey)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	wsURL := "ws" + strings.TrimPrefix(s.buildURL("/drone/7070/link"), "http")
+	// the tenant key alone can't link a drone
+	_, resp, err = websocket.DefaultDialer.Dial(wsURL, http.Header{"X-API-Key": []string{testAPIKey}})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	header := s.linkHeader(t, "7070", testAPIKey)
+	conn, resp, err := websocket.DefaultDialer.Dial(wsURL, header)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	assert.True(t, s.getDrone(t, "7070").Connected)
+
+	// the telemetry updates the drone
+	battery, state := uint8(60), drone.Loaded
+	require.NoError(t, conn.WriteJSON(dronehttp.LinkMessageDTO{Type: dronehttp.LinkTelemetry, Battery: &battery, State: &state}))
+	var msg dronehttp.LinkMessageDTO
+	require.NoError(t, conn.ReadJSON(&msg))
+	require.Equal(t, dronehttp.LinkAck, msg.Type, msg.Error)
+	d := s.getDrone(t, "7070")
+	assert.Equal(t, battery, d.BatteryCapacity)
+	assert.Equal(t, state, d.State)
+	assert.Nil(t, d.ETA) // no destination
+
+	invalidState := drone.State(42)
+	require.NoError(t, conn.WriteJSON(dronehttp.LinkMessageDTO{Type: dronehttp.LinkTelemetry, State: &invalidState}))
+	require.NoError(t, conn.ReadJSON(&msg))
+	assert.Equal(t, dronehttp.LinkError, msg.Type)
+
+	// the malformed messages are answered without dropping the link
+	for _, raw := range []string{`{"type":"telemetry","battery":"x"}`, `{"type":}`} {
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(raw)))
+		msg = dronehttp.LinkMessageDTO{}
+		require.NoError(t, conn.ReadJSON(&msg))
+		assert.Equal(t, dronehttp.LinkError, msg.Type, raw)
+	}
+
+	// the commands issued through the API reach the drone
+	// NOTE: the drone has no home nor destination, so it's dispatched without the range check.
+	resp = s.do(t, http.MethodPost, "/drone/7070/commands", bytes.NewReader(b), testAPIKey)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	var sent dronehttp.SentDroneCommandDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&sent))
+	require.NoError(t, conn.ReadJSON(&msg))
+	assert.Equal(t, dronehttp.LinkCommand, msg.Type)
+	assert.Equal(t, sent.ID, msg.CommandID)
+	assert.Equal(t, drone.Dispatch, msg.Command)
+
+	// invalid commands for the drone state are rejected
+	b, err = json.Marshal(dronehttp.DroneCommandDTO{Command: drone.Return})
+	require.NoError(t, err)
+	resp = s.do(t, http.MethodPost, "/drone/7070/commands", bytes.NewReader(b), testAPIKey)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// the ETA is estimated once the drone has a home and a destination
+	for path, c := range map[string]dronehttp.CoordinatesDTO{
+		"/drone/7070/home":        {Latitude: 40.4168, Longitude: -3.7038},
+		"/drone/7070/destination": {Latitude: 40.4268, Longitude: -3.7038},
+	} {
+		b, err = json.Marshal(c)
+		require.NoError(t, err)
+		resp = s.do(t, http.MethodPut, path, bytes.NewReader(b), testAPIKey)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+	d = s.getDrone(t, "7070")
+	require.NotNil(t, d.ETA)
+	assert.InDelta(t, 1112, d.ETA.RemainingDistance, 2)
+
+	// the ETA is recalculated from the reported position
+	delivering, position := drone.Delivering, dronehttp.CoordinatesDTO{Latitude: 40.4218, Longitude: -3.7038}
+	require.NoError(t, conn.WriteJSON(dronehttp.LinkMessageDTO{Type: dronehttp.LinkTelemetry, State: &delivering, Position: &position}))
+	require.NoError(t, conn.ReadJSON(&msg))
+	require.Equal(t, dronehttp.LinkAck, msg.Type, msg.Error)
+	d = s.getDrone(t, "7070")
+	assert.Equal(t, &position, d.Position)
+	require.NotNil(t, d.ETA)
+	assert.InDelta(t, 556, d.ETA.RemainingDistance, 2)
+	assert.Greater(t, d.ETA.RemainingSeconds, int64(0))
+
+	// a delivering drone can't report it's loaded again
+	loaded := drone.Loaded
+	require.NoError(t, conn.WriteJSON(dronehttp.LinkMessageDTO{Type: dronehttp.LinkTelemetry, State: &loaded}))
+	require.NoError(t, conn.ReadJSON(&msg))
+	assert.Equal(t, dronehttp.LinkError, msg.Type)
+	assert.Equal(t, drone.Delivering, s.getDrone(t, "7070").State)
+
+	require.NoError(t, conn.Close())
+	require.Eventually(t, func() bool {
+		return !s.getDrone(t, "7070").Connected
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// a drone of another tenant can't connect
+	otherURL := "ws" + strings.TrimPrefix(s.buildURL("/drone/7070/link"), "http")
+	_, resp, err = websocket.DefaultDialer.Dial(otherURL, http.Header{
+		"X-API-Key":              []string{otherTenantAPIKey},
+		dronehttp.DroneKeyHeader: header[dronehttp.DroneKeyHeader],
+	})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *e2eSuite) TestGetDroneBatteryHistory(t *testing.T) {
+	t.Parallel()
+	// setup storage data
+	d := drone.Drone{
+		TenantID:        testTenant,
+		Serial:          "8080",
+		Model:           drone.Cruiserweight,
+		WeightLimit:     400,
+		BatteryCapacity: 80,
+		State:           drone.Delivering,
+	}
+	err := s.container.Storage().SaveDrone(context.Background(), d)
+	require.NoError(t, err)
+	at := time.Date(2023, time.January, 10, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		d.BatteryCapacity = uint8(80 - i*10)
+		err = s.container.BatteryHistory().AppendBatteryRecords(context.Background(), drone.NewBatteryRecord(d, at.Add(time.Duration(i)*time.Minute)))
+		require.NoError(t, err)
+	}
+
+	from := url.QueryEscape(at.Add(time.Minute).Format(time.RFC3339))
+	resp := s.do(t, http.MethodGet, "/drone/8080/battery/history?from="+from, nil, testAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var body []dronehttp.BatteryRecordDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body, 2)
+	assert.Equal(t, uint8(70), body[0].BatteryLevel)
+	assert.Equal(t, uint8(60), body[1].BatteryLevel)
+	assert.Equal(t, drone.Delivering, body[1].State)
+
+	resp = s.do(t, http.MethodGet, "/drone/8080/battery/history?to=yesterday", nil, testAPIKey)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *e2eSuite) TestGetJobs(t *testing.T) {
+	t.Parallel()
+	resp := s.do(t, http.MethodGet, "/admin/jobs", nil, adminAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var body []dronehttp.JobStatusDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body, 1)
+	assert.Equal(t, "battery_audit", body[0].Name)
+	assert.Equal(t, "@every 1h0m0s", body[0].Schedule)
+	assert.False(t, body[0].Running)
+	assert.Nil(t, body[0].LastStartedAt)
+	require.NotNil(t, body[0].NextRunAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *body[0].NextRunAt, time.Minute)
+
+	resp = s.do(t, http.MethodGet, "/admin/jobs", nil, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	// a tenant key isn't enough
+	resp = s.do(t, http.MethodGet, "/admin/jobs", nil, testAPIKey)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func (s *e2eSuite) TestMetrics(t *testing.T) {
+	t.Parallel()
+	// setup storage data
+	err := s.container.Storage().SaveDrone(context.Background(), drone.Drone{
+		TenantID:        testTenant,
+		Serial:          "9090",
+		Model:           drone.Heavyweight,
+		WeightLimit:     500,
+		BatteryCapacity: 90,
+		State:           drone.Returning,
+	})
+	require.NoError(t, err)
+	s.getDrone(t, "9090")
+
+	scrape := func(apiKey string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, s.testServer.URL+"/metrics", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	// only the admin keys can scrape the metrics
+	assert.Equal(t, http.StatusUnauthorized, scrape("").StatusCode)
+	assert.Equal(t, http.StatusForbidden, scrape(testAPIKey).StatusCode)
+	resp := scrape(adminAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	body := string(b)
+	assert.Contains(t, body, `drone_http_request_duration_seconds_count{method="GET",route="/api/v1/drone/{serial}",status="200"}`)
+	assert.Contains(t, body, `drone_storage_operation_duration_seconds_count{backend="json",operation="save_drone",result="ok"}`)
+	assert.Contains(t, body, `drone_fleet_drones_by_model{model="heavyweight",tenant="hospital-a"}`)
+	assert.Regexp(t, `drone_fleet_drones\{state="returning",tenant="hospital-a"\} [1-9]`, body)
+	assert.Contains(t, body, `drone_fleet_battery_level_percent_bucket{tenant="hospital-a",le="90"}`)
+	assert.Contains(t, body, `drone_fleet_loaded_weight_grams{tenant="hospital-a"}`)
+}
+
+// linkHeader issues a link key for the drone and returns the headers of its link.
+func (s *e2eSuite) linkHeader(t *testing.T, serial, apiKey string) http.Header {
+	t.Helper()
+	resp := s.do(t, http.MethodPost, "/drone/"+serial+"/link-key", nil, apiKey)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var body dronehttp.DroneLinkKeyDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.NotEmpty(t, body.LinkKey)
+	return http.Header{"X-API-Key": []string{apiKey}, dronehttp.DroneKeyHeader: []string{body.LinkKey}}
+}
+
+func (s *e2eSuite) getDrone(t *testing.T, serial string) dronehttp.DroneDTO {
+	t.Helper()
+	resp := s.do(t, http.MethodGet, "/drone/"+serial, nil, testAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var body dronehttp.DroneDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return body
+}
+
+func (s *e2eSuite) openStream(t *testing.T, ctx context.Context, path, lastEventID string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.buildURL(path), nil)
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", testAPIKey)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+// readStreamEvent reads the next Server-Sent Event, skipping the comments.
+func readStreamEvent(t *testing.T, reader *bufio.Reader) dronehttp.DroneEventDTO {
+	t.Helper()
+	var e dronehttp.DroneEventDTO
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "data: "):
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e))
+		case line == "" && e.ID != "":
+			return e
+		}
+	}
+}
+
+func (s *e2eSuite) droneAudit(t *testing.T, serial string) []dronehttp.AuditEventDTO {
+	t.Helper()
+	resp := s.do(t, http.MethodGet, "/drone/"+serial+"/audit", nil, testAPIKey)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var body []dronehttp.AuditEventDTO
+	err := json.NewDecoder(resp.Body).Decode(&body)
+	require.NoError(t, err)
+	return body
+}
+
+func (s *e2eSuite) do(t *testing.T, method, path string, body io.Reader, apiKey string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, s.buildURL(path), body)
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", apiKey)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
 func (s *e2eSuite) buildURL(path string) string {
 	return s.testServer.URL + "/api/v1" + path
 }
 
 func (s *e2eSuite) startServer(t *testing.T) {
 	t.Helper()
+	s.spans = tracetest.NewInMemoryExporter()
+	s.logs = &syncBuffer{}
 	s.container = NewDroneContainer(
 		&Configuration{
 			DroneController: DroneControllerConfiguration{
@@ -222,9 +1416,49 @@ func (s *e2eSuite) startServer(t *testing.T) {
 			JSONStorage: JSONStorageConfiguration{
 				DatabasePath: "../../test/test_e2e_data",
 			},
+			BatteryHistory: BatteryHistoryConfiguration{
+				Dir: "../../test/test_e2e_data/battery",
+			},
+			Audit: AuditConfiguration{
+				FilePath: "../../test/test_e2e_data/audit/events.jsonl",
+			},
+			Logging: LoggingConfiguration{
+				Logger: logging.New(s.logs, slog.LevelDebug),
+			},
+			Tracing: tracing.Config{
+				SpanExporter: s.spans,
+			},
+			Jobs: JobsConfiguration{
+				BatteryAudit: scheduler.Every(time.Hour),
+			},
+			Orders: OrdersConfiguration{
+				BatteryMargin: 10,
+			},
+			Maintenance: MaintenanceConfiguration{
+				Rules: drone.MaintenanceRules{MaxCycles: 1},
+			},
+			Webhooks: WebhooksConfiguration{
+				Egress: webhook.EgressPolicy{InsecureHosts: []string{"127.0.0.1"}, AllowPrivate: true},
+			},
+			Auth: AuthConfiguration{
+				APIKeys: []dronehttp.APIKey{
+					{Key: testAPIKey, Subject: "e2e", TenantID: testTenant},
+					{Key: otherTenantAPIKey, Subject: "e2e", TenantID: "hospital-b"},
+					{Key: ordersAPIKey, Subject: "e2e", TenantID: ordersTenant},
+					{Key: splitAPIKey, Subject: "e2e", TenantID: splitTenant},
+					{Key: docksAPIKey, Subject: "e2e", TenantID: docksTenant},
+					{Key: adminAPIKey, Subject: "e2e-admin", TenantID: testTenant, Admin: true},
+				},
+			},
 		})
 
+	require.NoError(t, os.MkdirAll(s.container.config.DroneController.UploadDir, os.ModePerm))
 	s.testServer = httptest.NewServer(s.container.Router())
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go s.container.WebhookDeliverer().Run(ctx)
+	s.container.Scheduler().Start(ctx)
+	t.Cleanup(func() { _ = s.container.Scheduler().Stop(context.Background()) })
 }
 
 func (s *e2eSuite) assertMedication(t *testing.T, expected drone.Medication, actual dronehttp.MedicationDTO) bool {
@@ -2,76 +2,93 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
-	"path/filepath"
-	"strconv"
 	"strings"
-	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/hsequeda/drone/logging"
 )
 
 func main() {
-	httpAddr, ok := os.LookupEnv("HTTP_SERVER_ADDR")
-	if !ok {
-		log.Fatalf("HTTP_SERVER_ADDR is empty")
-		return
-	}
-
-	uploadSizeStr, ok := os.LookupEnv("UPLOAD_SIZE")
-	if !ok {
-		log.Fatalf("UPLOAD_SIZE is empty")
-		return
-	}
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "path of the YAML configuration file")
+	flag.Parse()
 
-	uploadSize, err := strconv.ParseInt(uploadSizeStr, 10, 64)
+	fc, err := LoadFileConfiguration(*configPath, os.LookupEnv)
 	if err != nil {
-		log.Fatalf("UPLOAD_SIZE need to be integer")
-		return
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%s\n", err)
+		os.Exit(1)
 	}
 
-	pwd, _ := os.Getwd()
-	execute(NewDroneContainer(&Configuration{
-		HTTPServer:      HTTPServerConfiguration{Addr: httpAddr},
-		DroneController: DroneControllerConfiguration{MaxUploadSize: uploadSize * (1024 * 1024), UploadDir: filepath.Join(pwd, "/uploads")},
-		JSONStorage:     JSONStorageConfiguration{DatabasePath: filepath.Join(pwd, "/data")},
-	}))
+	level, _ := logging.ParseLevel(fc.Log.Level)
+	logger := logging.New(os.Stdout, level)
+	slog.SetDefault(logger)
+
+	execute(NewDroneContainer(fc.Configuration(logger)))
 }
 
 func execute(c *DroneContainer) {
-	log.Printf("Running server in addr %s", c.config.HTTPServer.Addr)
-	debugRoutes(c.Router())
+	logger := c.Logger()
+	logger.Info("running server", slog.String("addr", c.config.HTTPServer.Addr))
+	debugRoutes(logger, c.Router())
+	// NOTE: a missing upload dir is reported by /readyz.
+	if err := os.MkdirAll(c.config.DroneController.UploadDir, os.ModePerm); err != nil {
+		logger.Error("create upload dir", slog.String("error", err.Error()))
+	}
+
 	go func() {
-		if err := c.HTTPServer().ListenAndServe(); err != nil {
-			fmt.Printf("error: %s", err.Error())
+		var err error
+		if c.HTTPServer().TLSConfig != nil {
+			// NOTE: the certificate is served by the TLSConfig, reloading it on changes.
+			err = c.HTTPServer().ListenAndServeTLS("", "")
+		} else {
+			err = c.HTTPServer().ListenAndServe()
+		}
+
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("listen and serve", slog.String("error", err.Error()))
 		}
 	}()
 
 	ctx, _ := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
+	go c.WebhookDeliverer().Run(ctx)
+	go c.Dispatcher().Run(ctx, c.config.Events.DispatchInterval, func(err error) {
+		logger.Error("dispatch events", slog.String("error", err.Error()))
+	})
+	c.Scheduler().Start(ctx)
+
 	<-ctx.Done()
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), c.config.HTTPServer.ShutdownTimeout)
 	defer func() {
 		cancel()
 	}()
 
 	if err := c.HTTPServer().Shutdown(shutdownCtx); err != nil {
-		log.Fatalf("server Shutdown Failed:%+s", err)
-		return
+		logger.Error("server shutdown failed", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	if err := c.Scheduler().Stop(shutdownCtx); err != nil {
+		logger.Error("scheduler stop failed", slog.String("error", err.Error()))
+	}
+
+	if err := c.TracerProvider().Shutdown(shutdownCtx); err != nil {
+		logger.Error("tracer provider shutdown failed", slog.String("error", err.Error()))
 	}
 
-	log.Println("server exited properly")
+	logger.Info("server exited properly")
 }
 
-func debugRoutes(router *chi.Mux) {
-	log.Println("Routes defined in the server:")
+func debugRoutes(logger *slog.Logger, router *chi.Mux) {
 	_ = chi.Walk(router, func(method string, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
 		if !strings.Contains(route, "debug") { // omit /debug
-			fmt.Printf("%s %s\n", method, route)
+			logger.Debug("route defined", slog.String("method", method), slog.String("route", route))
 		}
 		return nil
 	})
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+	dronehttp "github.com/hsequeda/drone/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hardenedConfiguration is the configuration of a server with small limits.
+func hardenedConfiguration(t *testing.T) *Configuration {
+	t.Helper()
+	return &Configuration{
+		HTTPServer: HTTPServerConfiguration{
+			ReadTimeout:        200 * time.Millisecond,
+			WriteTimeout:       200 * time.Millisecond,
+			MaxJSONBodySize:    256,
+			RateLimitPerAPIKey: RateLimitConfiguration{RPS: 0.1, Burst: 3},
+		},
+		DroneController: DroneControllerConfiguration{MaxUploadSize: 1024, UploadDir: t.TempDir()},
+		Storage:         StorageConfiguration{Backend: StorageBackendMemory},
+		BatteryHistory:  BatteryHistoryConfiguration{Dir: t.TempDir()},
+		Auth: AuthConfiguration{
+			APIKeys: []dronehttp.APIKey{
+				{Key: testAPIKey, Subject: "hardening", TenantID: testTenant},
+				{Key: otherTenantAPIKey, Subject: "hardening", TenantID: "hospital-b"},
+			},
+		},
+	}
+}
+
+// startHardenedServer serves the container with the timeouts of its configuration.
+func startHardenedServer(t *testing.T, c *DroneContainer) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(c.Router())
+	srv.Config.ReadTimeout = c.config.HTTPServer.ReadTimeout
+	srv.Config.WriteTimeout = c.config.HTTPServer.WriteTimeout
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestBodyLimit(t *testing.T) {
+	srv := startHardenedServer(t, NewDroneContainer(hardenedConfiguration(t)))
+
+	b, err := json.Marshal(dronehttp.RegisterDroneDTO{Serial: strings.Repeat("1", 512), Model: drone.Lightweight, WeightLimit: 100, Battery: 90})
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/api/v1/drone", bytes.NewReader(b))
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", testAPIKey)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+}
+
+func TestRateLimitPerAPIKey(t *testing.T) {
+	srv := startHardenedServer(t, NewDroneContainer(hardenedConfiguration(t)))
+
+	get := func(apiKey string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/v1/drones", nil)
+		require.NoError(t, err)
+		req.Header.Set("X-API-Key", apiKey)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	for i := 0; i < 3; i++ {
+		require.Equal(t, http.StatusOK, get(testAPIKey).StatusCode)
+	}
+
+	resp := get(testAPIKey)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "10", resp.Header.Get("Retry-After"))
+
+	// the other API keys have their own bucket
+	assert.Equal(t, http.StatusOK, get(otherTenantAPIKey).StatusCode)
+}
+
+func TestEventStreamOutlivesWriteTimeout(t *testing.T) {
+	c := NewDroneContainer(hardenedConfiguration(t))
+	srv := startHardenedServer(t, c)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/v1/events", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", testAPIKey)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	time.Sleep(2 * c.config.HTTPServer.WriteTimeout)
+	d, err := drone.NewDrone(drone.DefaultPolicy(), testTenant, "1", drone.Lightweight, 100, 90)
+	require.NoError(t, err)
+	require.NoError(t, c.Storage().SaveDrone(context.Background(), d))
+	require.NoError(t, c.Dispatcher().Dispatch(context.Background()))
+
+	e := readStreamEvent(t, bufio.NewReader(resp.Body))
+	assert.Equal(t, drone.DroneRegistered, e.Type)
+}
+
+func TestTLSCertificateReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeCertificate(t, certFile, keyFile, 1, time.Now().Add(-time.Minute))
+
+	config := hardenedConfiguration(t)
+	config.HTTPServer.TLSCertFile, config.HTTPServer.TLSKeyFile = certFile, keyFile
+	server := NewDroneContainer(config).HTTPServer()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = server.Serve(tls.NewListener(ln, server.TLSConfig)) }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	servedSerial := func() int64 {
+		conn, err := tls.Dial("tcp", ln.Addr().String(), &tls.Config{InsecureSkipVerify: true}) //nolint:gosec // self-signed test certificate
+		require.NoError(t, err)
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+	}
+
+	assert.Equal(t, int64(1), servedSerial())
+
+	writeCertificate(t, certFile, keyFile, 2, time.Now())
+	assert.Equal(t, int64(2), servedSerial())
+
+	// an invalid rotation keeps the previous certificate
+	require.NoError(t, os.WriteFile(keyFile, []byte("invalid"), 0o600))
+	require.NoError(t, os.Chtimes(keyFile, time.Now().Add(time.Minute), time.Now().Add(time.Minute)))
+	assert.Equal(t, int64(2), servedSerial())
+}
+
+// writeCertificate writes a self-signed key pair with the serial and files modified at modTime.
+func writeCertificate(t *testing.T, certFile, keyFile string, serial int64, modTime time.Time) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	for _, name := range []string{certFile, keyFile} {
+		require.NoError(t, os.Chtimes(name, modTime, modTime))
+	}
+}
+
+func TestReadinessReportsMissingUploadDir(t *testing.T) {
+	config := hardenedConfiguration(t)
+	config.DroneController.UploadDir = filepath.Join(t.TempDir(), "missing")
+	srv := startHardenedServer(t, NewDroneContainer(config))
+
+	resp, err := http.Get(srv.URL + "/readyz")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	var body dronehttp.ReadinessDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "down", body.Status)
+	require.Len(t, body.Checks, 3)
+	assert.Equal(t, "storage", body.Checks[0].Name)
+	assert.Equal(t, "up", body.Checks[0].Status)
+	assert.Equal(t, "upload_dir", body.Checks[1].Name)
+	assert.Equal(t, "down", body.Checks[1].Status)
+	assert.NotEmpty(t, body.Checks[1].Error)
+
+	// the liveness doesn't depend on the checks
+	resp, err = http.Get(srv.URL + "/livez")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
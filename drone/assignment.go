@@ -0,0 +1,372 @@
+package drone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	// ErrNoDroneAvailable error occurs when no drone of the tenant can carry an Order.
+	ErrNoDroneAvailable = errors.New("no drone available for the order")
+	// ErrEmptyOrder error occurs when an Order has no medications.
+	ErrEmptyOrder = errors.New("order without medications")
+)
+
+// Order is a medication manifest to be carried by a single drone.
+type Order struct {
+	TenantID    string
+	Medications []Medication
+	// Model restricts the drones of the Order to a Model (zero accepts any Model).
+	Model Model
+	// Destination is where the Order goes (optional). When set, the drones must
+	// have the range for the round trip with their load around the no-fly zones.
+	Destination *Coordinates
+
+	// zones are the no-fly zones of the tenant, loaded by the Assigner.
+	zones []Geofence
+	// policy is the Policy of the tenant, set by the Assigner (DefaultPolicy if nil).
+	policy *Policy
+	// legs are the routes of the drones to the Destination by serial, see routed.
+	legs map[string]leg
+}
+
+// leg is the route of a drone from its Home to the Destination of an Order.
+type leg struct {
+	home Coordinates
+	// distance is the one way distance in meters around the no-fly zones.
+	distance float64
+	// err is the failure to route the drone (e.g. ErrNoRoute).
+	err error
+}
+
+// Weight returns the total weight of the medications of the Order.
+func (o Order) Weight() uint32 {
+	var w uint32
+	for _, m := range o.Medications {
+		w += m.Weight
+	}
+	return w
+}
+
+// rules returns the Policy the drones of the Order are loaded with.
+func (o Order) rules() Policy {
+	if o.policy == nil {
+		return DefaultPolicy()
+	}
+
+	return *o.policy
+}
+
+// reachable returns if the drone can complete the round trip to the
+// Destination of the Order carrying also the medications.
+func (o Order) reachable(d Drone, meds []Medication) bool {
+	if o.Destination == nil {
+		return true
+	}
+
+	if d.Home == nil {
+		return false
+	}
+
+	l, ok := o.legs[d.Serial]
+	if !ok || l.home != *d.Home {
+		l = o.route(d)
+	}
+
+	if l.err != nil {
+		return false
+	}
+
+	d.Medications = append(append([]Medication(nil), d.Medications...), meds...)
+	_, err := d.checkRange(o.rules(), l.distance)
+	return err == nil
+}
+
+// route routes the drone, with a Home, to the Destination of the Order around the no-fly zones.
+func (o Order) route(d Drone) leg {
+	d.Destination = o.Destination
+	if err := d.Route(o.zones); err != nil {
+		return leg{home: *d.Home, err: err}
+	}
+
+	return leg{home: *d.Home, distance: d.flightDistance()}
+}
+
+// routed returns the Order with the legs of the drones to its Destination.
+// NOTE: a route only depends on the Home of the drone, so it's computed once
+// per drone instead of once per checked load.
+func (o Order) routed(drones []Drone) Order {
+	if o.Destination == nil {
+		return o
+	}
+
+	legs := make(map[string]leg, len(o.legs)+len(drones))
+	for serial, l := range o.legs {
+		legs[serial] = l
+	}
+
+	for _, d := range drones {
+		if d.Home == nil {
+			continue
+		}
+
+		if l, ok := legs[d.Serial]; !ok || l.home != *d.Home {
+			legs[d.Serial] = o.route(d)
+		}
+	}
+
+	o.legs = legs
+	return o
+}
+
+// fits returns if the drone can carry also the medications (see CanCarry)
+// and still reach the Destination of the Order.
+func (o Order) fits(d Drone, meds []Medication) bool {
+	return d.CanCarry(o.rules(), meds) == nil && o.reachable(d, meds)
+}
+
+// AssignmentStrategy selects the drone to carry an Order.
+type AssignmentStrategy interface {
+	// Select returns the chosen drone among the candidates, which can all carry the Order.
+	Select(candidates []Drone, o Order) Drone
+}
+
+// AssignmentStrategyFunc adapts a function to the AssignmentStrategy interface.
+type AssignmentStrategyFunc func(candidates []Drone, o Order) Drone
+
+func (f AssignmentStrategyFunc) Select(candidates []Drone, o Order) Drone {
+	return f(candidates, o)
+}
+
+// BestFit selects the drone left with the least free weight after loading the
+// Order, keeping the bigger drones for the heavier orders. Ties go to the
+// highest battery, then to the lightest Model.
+var BestFit AssignmentStrategy = AssignmentStrategyFunc(func(candidates []Drone, o Order) Drone {
+	return first(candidates, func(a, b Drone) bool {
+		if fa, fb := freeWeight(a), freeWeight(b); fa != fb {
+			return fa < fb
+		}
+
+		if a.BatteryCapacity != b.BatteryCapacity {
+			return a.BatteryCapacity > b.BatteryCapacity
+		}
+
+		return a.Model < b.Model
+	})
+})
+
+// MostBattery selects the drone with the highest battery, spreading the
+// orders across the fleet. Ties go to the best fit.
+var MostBattery AssignmentStrategy = AssignmentStrategyFunc(func(candidates []Drone, o Order) Drone {
+	return first(candidates, func(a, b Drone) bool {
+		if a.BatteryCapacity != b.BatteryCapacity {
+			return a.BatteryCapacity > b.BatteryCapacity
+		}
+
+		return freeWeight(a) < freeWeight(b)
+	})
+})
+
+// first returns the first drone in the order of less, breaking the ties by serial.
+func first(candidates []Drone, less func(a, b Drone) bool) Drone {
+	sorted := append([]Drone(nil), candidates...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if less(sorted[i], sorted[j]) {
+			return true
+		}
+
+		if less(sorted[j], sorted[i]) {
+			return false
+		}
+
+		return sorted[i].Serial < sorted[j].Serial
+	})
+
+	return sorted[0]
+}
+
+// Assigner selects and loads the drone of an Order.
+type Assigner struct {
+	storage   Storage
+	locks     *Locks
+	geofences GeofenceStore
+	policies  Policies
+	strategy  AssignmentStrategy
+	// batteryMargin is the battery over the MinLoadBattery of the Policy a drone needs to be assigned.
+	batteryMargin uint8
+
+	// orders serializes the assignments of each tenant (the drones are never
+	// shared across tenants) so two orders never select the same free weight.
+	// NOTE: it only covers the assignments of this process.
+	orders *Locks
+}
+
+// NewAssigner builds an Assigner choosing the drones with the given strategy
+// (BestFit if nil) under the Policy of their tenant, and routing them around
+// the no-fly zones of the geofences (none if nil). The loads hold the locks
+// of their drones.
+func NewAssigner(storage Storage, locks *Locks, geofences GeofenceStore, policies Policies, strategy AssignmentStrategy, batteryMargin uint8) *Assigner {
+	if strategy == nil {
+		strategy = BestFit
+	}
+
+	return &Assigner{storage: storage, locks: locks, geofences: geofences, policies: policies, strategy: strategy, batteryMargin: batteryMargin, orders: NewLocks()}
+}
+
+// Assign loads the whole Order in the drone selected by the strategy and
+// returns the drone before and after the load. Nothing is loaded on failure.
+func (a *Assigner) Assign(ctx context.Context, o Order) (before, after Drone, err error) {
+	if len(o.Medications) == 0 {
+		return Drone{}, Drone{}, ErrEmptyOrder
+	}
+
+	o, err = a.airspace(ctx, o)
+	if err != nil {
+		return Drone{}, Drone{}, err
+	}
+
+	unlockOrders := a.orders.Lock(o.TenantID, "")
+	defer unlockOrders()
+
+	o, drones, err := a.candidates(ctx, o)
+	if err != nil {
+		return Drone{}, Drone{}, err
+	}
+
+	var candidates []Drone
+	for _, d := range drones {
+		if freeWeight(d) >= o.Weight() && o.fits(d, o.Medications) {
+			candidates = append(candidates, d)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return Drone{}, Drone{}, ErrNoDroneAvailable
+	}
+
+	selected := a.strategy.Select(candidates, o)
+	unlock := a.locks.Lock(selected.TenantID, selected.Serial)
+	defer unlock()
+
+	before, err = a.reread(ctx, o, selected, o.Medications)
+	if err != nil {
+		return Drone{}, Drone{}, err
+	}
+
+	after = before
+	// NOTE: the medications are copied so the loads don't share the backing array of before.
+	after.Medications = append([]Medication(nil), before.Medications...)
+	if err := o.load(&after, o.Medications); err != nil {
+		return Drone{}, Drone{}, err
+	}
+
+	if err := a.storage.SaveDrone(ctx, after); err != nil {
+		return Drone{}, Drone{}, fmt.Errorf("save drone %s: %w", after.Serial, err)
+	}
+
+	return before, after, nil
+}
+
+// load adds the medications to the drone and sets the Destination of the Order.
+func (o Order) load(d *Drone, meds []Medication) error {
+	for _, m := range meds {
+		if err := d.AddMedications(o.rules(), m); err != nil {
+			return fmt.Errorf("load drone %s: %w", d.Serial, err)
+		}
+	}
+
+	if o.Destination != nil {
+		if err := d.SetDestination(*o.Destination); err != nil {
+			return fmt.Errorf("set destination of drone %s: %w", d.Serial, err)
+		}
+
+		if err := d.Route(o.zones); err != nil {
+			return fmt.Errorf("route drone %s: %w", d.Serial, err)
+		}
+	}
+
+	return nil
+}
+
+// airspace returns the Order with the Policy and the no-fly zones of the
+// tenant, failing with ErrNoFlyZone if its Destination is inside one of them.
+func (a *Assigner) airspace(ctx context.Context, o Order) (Order, error) {
+	p := a.policies.For(o.TenantID)
+	o.policy = &p
+	if o.Destination == nil || a.geofences == nil {
+		return o, nil
+	}
+
+	zones, err := a.geofences.Geofences(ctx, o.TenantID)
+	if err != nil {
+		return o, fmt.Errorf("list geofences: %w", err)
+	}
+
+	for _, g := range zones {
+		if g.Contains(*o.Destination) {
+			return o, fmt.Errorf("%w: destination inside %q", ErrNoFlyZone, g.Name)
+		}
+	}
+
+	o.zones = zones
+	return o, nil
+}
+
+// candidates returns the drones of the tenant that can be assigned to the
+// Order, whatever its weight, and the Order routed for them.
+func (a *Assigner) candidates(ctx context.Context, o Order) (Order, []Drone, error) {
+	drones, err := a.storage.Drones(ctx, o.TenantID)
+	if err != nil {
+		return o, nil, fmt.Errorf("list drones: %w", err)
+	}
+
+	var available []Drone
+	for _, d := range drones {
+		if o.Model != 0 && d.Model != o.Model {
+			continue
+		}
+
+		if a.available(d, o) {
+			available = append(available, d)
+		}
+	}
+
+	o = o.routed(available)
+	var candidates []Drone
+	for _, d := range available {
+		if o.reachable(d, nil) {
+			candidates = append(candidates, d)
+		}
+	}
+
+	return o, candidates, nil
+}
+
+// available returns if the drone is available for the Order with the battery margin.
+func (a *Assigner) available(d Drone, o Order) bool {
+	p := o.rules()
+	return d.IsAvailable(p) && int(d.BatteryCapacity) >= int(p.MinLoadBattery)+int(a.batteryMargin)
+}
+
+// eligible returns if the drone can be assigned to the Order, whatever its weight.
+func (a *Assigner) eligible(d Drone, o Order) bool {
+	return a.available(d, o) && o.reachable(d, nil)
+}
+
+// reread reads the drone again, as it could have changed since listed, and
+// fails with ErrNoDroneAvailable if it can no longer carry the medications.
+// NOTE: the caller holds the lock of the drone.
+func (a *Assigner) reread(ctx context.Context, o Order, d Drone, meds []Medication) (Drone, error) {
+	current, err := a.storage.Drone(ctx, d.TenantID, d.Serial)
+	if err != nil {
+		return Drone{}, fmt.Errorf("read drone %s: %w", d.Serial, err)
+	}
+
+	if !a.eligible(current, o) || freeWeight(current) < (PlannedLoad{Medications: meds}).Weight() || !o.fits(current, meds) {
+		return Drone{}, fmt.Errorf("%w: drone %s changed while assigned", ErrNoDroneAvailable, d.Serial)
+	}
+
+	return current, nil
+}
@@ -0,0 +1,188 @@
+package drone_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssignmentStrategies(t *testing.T) {
+	candidates := []drone.Drone{
+		{Serial: "big", Model: drone.Heavyweight, WeightLimit: 500, BatteryCapacity: 60},
+		{Serial: "tight", Model: drone.Middleweight, WeightLimit: 300, BatteryCapacity: 50, Medications: []drone.Medication{{Weight: 100}}},
+		{Serial: "tight-charged", Model: drone.Middleweight, WeightLimit: 200, BatteryCapacity: 70},
+		{Serial: "charged", Model: drone.Cruiserweight, WeightLimit: 400, BatteryCapacity: 90},
+	}
+	o := drone.Order{Medications: []drone.Medication{{Weight: 150}}}
+
+	testCases := []struct {
+		name     string
+		strategy drone.AssignmentStrategy
+		expected string
+	}{
+		{name: "BestFit: least free weight, then most battery", strategy: drone.BestFit, expected: "tight-charged"},
+		{name: "MostBattery", strategy: drone.MostBattery, expected: "charged"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.strategy.Select(candidates, o).Serial)
+		})
+	}
+}
+
+func TestAssigner(t *testing.T) {
+	ctx := context.Background()
+	st := storage.NewInMemory()
+	for _, d := range []drone.Drone{
+		{TenantID: "hospital-a", Serial: "light", Model: drone.Lightweight, WeightLimit: 200, BatteryCapacity: 80, State: drone.Idle},
+		{TenantID: "hospital-a", Serial: "heavy", Model: drone.Heavyweight, WeightLimit: 500, BatteryCapacity: 80, State: drone.Idle},
+		{TenantID: "hospital-a", Serial: "low-battery", Model: drone.Lightweight, WeightLimit: 200, BatteryCapacity: 30, State: drone.Idle},
+		{TenantID: "hospital-a", Serial: "delivering", Model: drone.Lightweight, WeightLimit: 200, BatteryCapacity: 90, State: drone.Delivering},
+		{TenantID: "hospital-b", Serial: "other-tenant", Model: drone.Lightweight, WeightLimit: 200, BatteryCapacity: 90, State: drone.Idle},
+	} {
+		require.NoError(t, st.SaveDrone(ctx, d))
+	}
+
+	assigner := drone.NewAssigner(st, drone.NewLocks(), nil, drone.Policies{Default: drone.DefaultPolicy()}, drone.BestFit, 10)
+	order := func(model drone.Model, weights ...uint32) drone.Order {
+		o := drone.Order{TenantID: "hospital-a", Model: model}
+		for _, w := range weights {
+			o.Medications = append(o.Medications, drone.Medication{Name: "Med", Code: "MED", Weight: w})
+		}
+		return o
+	}
+
+	// the tightest drone with enough battery margin carries the whole order
+	before, after, err := assigner.Assign(ctx, order(0, 100, 50))
+	require.NoError(t, err)
+	assert.Equal(t, "light", after.Serial)
+	assert.Empty(t, before.Medications)
+	assert.Equal(t, uint32(150), after.MedicationWeight())
+	saved, err := st.Drone(ctx, "hospital-a", "light")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(150), saved.MedicationWeight())
+
+	// the remaining weight of "light" isn't enough anymore
+	_, after, err = assigner.Assign(ctx, order(0, 100))
+	require.NoError(t, err)
+	assert.Equal(t, "heavy", after.Serial)
+
+	// the model restricts the candidates
+	_, _, err = assigner.Assign(ctx, order(drone.Lightweight, 100))
+	assert.ErrorIs(t, err, drone.ErrNoDroneAvailable)
+
+	_, _, err = assigner.Assign(ctx, order(0, 450))
+	assert.ErrorIs(t, err, drone.ErrNoDroneAvailable)
+
+	_, _, err = assigner.Assign(ctx, order(0))
+	assert.ErrorIs(t, err, drone.ErrEmptyOrder)
+
+	// the events of the loads are committed
+	pending, err := st.PendingEvents(ctx, 10)
+	require.NoError(t, err)
+	assert.Len(t, pending, 3)
+}
+
+func TestAssignerConcurrentOrders(t *testing.T) {
+	ctx := context.Background()
+	st := storage.NewInMemory()
+	require.NoError(t, st.SaveDrone(ctx, drone.Drone{TenantID: "hospital-a", Serial: "1", Model: drone.Lightweight, WeightLimit: 100, BatteryCapacity: 80, State: drone.Idle}))
+	assigner := drone.NewAssigner(st, drone.NewLocks(), nil, drone.Policies{Default: drone.DefaultPolicy()}, nil, 0)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		assigned int
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := assigner.Assign(ctx, drone.Order{TenantID: "hospital-a", Medications: []drone.Medication{{Weight: 30}}})
+			if err == nil {
+				mu.Lock()
+				assigned++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// only 3 orders of 30g fit in 100g
+	assert.Equal(t, 3, assigned)
+	d, err := st.Drone(ctx, "hospital-a", "1")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(90), d.MedicationWeight())
+}
+
+// blockingStorage blocks the listing of the drones of the tenant until release is closed.
+type blockingStorage struct {
+	*storage.InMemory
+	tenantID string
+	listing  chan struct{}
+	release  chan struct{}
+}
+
+func (s blockingStorage) Drones(ctx context.Context, tenantID string) ([]drone.Drone, error) {
+	if tenantID == s.tenantID {
+		close(s.listing)
+		<-s.release
+	}
+
+	return s.InMemory.Drones(ctx, tenantID)
+}
+
+func TestAssignerTenantsDontWait(t *testing.T) {
+	ctx := context.Background()
+	st := storage.NewInMemory()
+	for _, tenantID := range []string{"hospital-a", "hospital-b"} {
+		require.NoError(t, st.SaveDrone(ctx, drone.Drone{TenantID: tenantID, Serial: "1", Model: drone.Lightweight, WeightLimit: 100, BatteryCapacity: 80, State: drone.Idle}))
+	}
+	bs := blockingStorage{InMemory: st, tenantID: "hospital-a", listing: make(chan struct{}), release: make(chan struct{})}
+	assigner := drone.NewAssigner(bs, drone.NewLocks(), nil, drone.Policies{Default: drone.DefaultPolicy()}, nil, 0)
+
+	done := make(chan error)
+	go func() {
+		_, _, err := assigner.Assign(ctx, drone.Order{TenantID: "hospital-a", Medications: []drone.Medication{{Weight: 30}}})
+		done <- err
+	}()
+	<-bs.listing
+
+	// the order of hospital-b goes on while the one of hospital-a is assigned
+	_, after, err := assigner.Assign(ctx, drone.Order{TenantID: "hospital-b", Medications: []drone.Medication{{Weight: 30}}})
+	require.NoError(t, err)
+	assert.Equal(t, "hospital-b", after.TenantID)
+
+	close(bs.release)
+	assert.NoError(t, <-done)
+}
+
+func TestAssignerAvoidsNoFlyZones(t *testing.T) {
+	ctx := context.Background()
+	st := storage.NewInMemory()
+	home := drone.Coordinates{Latitude: 0.005, Longitude: 0}
+	require.NoError(t, st.SaveDrone(ctx, drone.Drone{TenantID: "hospital-a", Serial: "1", Model: drone.Lightweight, WeightLimit: 200, BatteryCapacity: 90, State: drone.Idle, Home: &home}))
+	zone := square(t, "airport", 0, 0.01, 0.02, 0.02)
+	require.NoError(t, st.SaveGeofence(ctx, zone))
+	assigner := drone.NewAssigner(st, drone.NewLocks(), st, drone.Policies{Default: drone.DefaultPolicy()}, nil, 10)
+
+	meds := []drone.Medication{{Name: "Med", Code: "MED", Weight: 50}}
+	_, _, err := assigner.Assign(ctx, drone.Order{TenantID: "hospital-a", Medications: meds, Destination: &drone.Coordinates{Latitude: 0.01, Longitude: 0.015}})
+	assert.ErrorIs(t, err, drone.ErrNoFlyZone)
+
+	_, after, err := assigner.Assign(ctx, drone.Order{TenantID: "hospital-a", Medications: meds, Destination: &drone.Coordinates{Latitude: 0.005, Longitude: 0.03}})
+	require.NoError(t, err)
+	assert.NotEmpty(t, after.Waypoints)
+	assert.NoError(t, after.CheckRoute([]drone.Geofence{zone}))
+	// the ETA of the loaded drone goes around the zone
+	eta, err := drone.EstimateETA(after, time.Now())
+	require.NoError(t, err)
+	assert.Greater(t, eta.Distance, drone.Distance(home, *after.Destination))
+}
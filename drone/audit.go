@@ -0,0 +1,83 @@
+package drone
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// AuditAction defines the kind of mutation recorded in an AuditEvent.
+type AuditAction string
+
+const (
+	AuditRegister  AuditAction = "register"
+	AuditLoad      AuditAction = "load"
+	AuditTelemetry AuditAction = "telemetry"
+	AuditLocation  AuditAction = "location"
+	// AuditMaintenance records the drones grounded or returned to service.
+	AuditMaintenance AuditAction = "maintenance"
+	// AuditLinkKey records the link keys issued to the drones.
+	AuditLinkKey AuditAction = "link_key"
+	// AuditCapabilities records the changes of the capabilities declared by the drones.
+	AuditCapabilities AuditAction = "capabilities"
+	// AuditRevert records the loads reverted by a failed split order.
+	AuditRevert AuditAction = "revert"
+)
+
+// redacted is the value recorded for the changes of the secret fields of a Drone.
+var redacted = json.RawMessage(`"[redacted]"`)
+
+// FieldChange describes the before/after value of a Drone field.
+// NOTE: The values are kept JSON encoded so every AuditStore returns the same shape.
+type FieldChange struct {
+	Field  string
+	Before json.RawMessage
+	After  json.RawMessage
+}
+
+// AuditEvent is an append-only record of a mutation over a Drone.
+type AuditEvent struct {
+	TenantID  string
+	Serial    string
+	Actor     string
+	RequestID string
+	Action    AuditAction
+	At        time.Time
+	Changes   []FieldChange
+}
+
+// AuditStore persists the AuditEvents of the drones.
+type AuditStore interface {
+	// AppendAuditEvent appends a new AuditEvent. Stored events are never modified.
+	AppendAuditEvent(ctx context.Context, e AuditEvent) error
+	// AuditEvents returns the AuditEvents of a Drone of the tenant, oldest first.
+	AuditEvents(ctx context.Context, tenantID, serial string) ([]AuditEvent, error)
+}
+
+// Diff returns the list of fields that differ between two snapshots of a Drone.
+func Diff(before, after Drone) []FieldChange {
+	var changes []FieldChange
+	bv, av := reflect.ValueOf(before), reflect.ValueOf(after)
+	for i := 0; i < bv.NumField(); i++ {
+		field := bv.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		// NOTE: the fields of Drone are always JSON encodable.
+		b, _ := json.Marshal(bv.Field(i).Interface())
+		a, _ := json.Marshal(av.Field(i).Interface())
+		if !bytes.Equal(b, a) {
+			if field.Tag.Get("audit") == "redacted" {
+				// NOTE: the change is recorded, not the secret values.
+				b, a = redacted, redacted
+			}
+
+			changes = append(changes, FieldChange{Field: field.Name, Before: b, After: a})
+		}
+	}
+
+	return changes
+}
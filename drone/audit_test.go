@@ -0,0 +1,42 @@
+package drone_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	om250g := drone.Medication{Name: "Omeprazol-250g", Weight: 250, Code: "OM_250", Image: "1023123asf"}
+	before := drone.Drone{
+		TenantID:        "hospital-a",
+		Serial:          "12345",
+		Model:           drone.Cruiserweight,
+		WeightLimit:     400,
+		BatteryCapacity: 80,
+		State:           drone.Idle,
+	}
+	after := before
+	after.State = drone.Loading
+	after.Medications = []drone.Medication{om250g}
+
+	medications, err := json.Marshal(after.Medications)
+	assert.NoError(t, err)
+	assert.Equal(t, []drone.FieldChange{
+		{Field: "State", Before: json.RawMessage("1"), After: json.RawMessage("2")},
+		{Field: "Medications", Before: json.RawMessage("null"), After: medications},
+	}, drone.Diff(before, after))
+	assert.Empty(t, drone.Diff(before, before))
+}
+
+func TestDiffRedactsLinkKey(t *testing.T) {
+	before := drone.Drone{TenantID: "hospital-a", Serial: "12345"}
+	after := before
+	after.IssueLinkKey()
+
+	assert.Equal(t, []drone.FieldChange{
+		{Field: "LinkKeyHash", Before: json.RawMessage(`"[redacted]"`), After: json.RawMessage(`"[redacted]"`)},
+	}, drone.Diff(before, after))
+}
@@ -0,0 +1,35 @@
+package drone
+
+import (
+	"context"
+	"time"
+)
+
+// BatteryRecord is a snapshot of the battery level of a Drone.
+type BatteryRecord struct {
+	At       time.Time
+	TenantID string
+	Serial   string
+	Battery  uint8
+	State    State
+}
+
+// NewBatteryRecord builds a BatteryRecord with the current battery and state of the Drone.
+func NewBatteryRecord(d Drone, at time.Time) BatteryRecord {
+	return BatteryRecord{
+		At:       at,
+		TenantID: d.TenantID,
+		Serial:   d.Serial,
+		Battery:  d.BatteryCapacity,
+		State:    d.State,
+	}
+}
+
+// BatteryHistory persists the BatteryRecords of the drones.
+type BatteryHistory interface {
+	// AppendBatteryRecords appends new records to the history.
+	AppendBatteryRecords(ctx context.Context, records ...BatteryRecord) error
+	// BatteryRecords returns the records of a Drone of the tenant taken in [from, to), oldest first.
+	// NOTE: a zero from or to leaves that side of the range open.
+	BatteryRecords(ctx context.Context, tenantID, serial string, from, to time.Time) ([]BatteryRecord, error)
+}
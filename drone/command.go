@@ -0,0 +1,73 @@
+package drone
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Command defines the orders an operator can send to a connected drone.
+type Command string
+
+const (
+	// Dispatch orders a loaded drone to start the delivery.
+	Dispatch Command = "dispatch"
+	// Return orders a drone to come back to its base.
+	Return Command = "return"
+	// Abort cancels the current loading or delivery.
+	Abort Command = "abort"
+)
+
+var (
+	// ErrUnknownCommand error occurs when the Command is not one of the defined ones.
+	ErrUnknownCommand = errors.New("unknown command")
+	// ErrCommandNotAllowed error occurs when the Command can't be executed in the current State of the Drone.
+	ErrCommandNotAllowed = errors.New("command not allowed in the current drone state")
+)
+
+// CanExecute returns an error if the Drone can't execute the Command in its
+// current State, or if it lacks the battery of the Policy for a Dispatch.
+// The range of a Dispatch is the round trip when the Drone has both a Home and
+// a Destination, else the flight back Home (see CheckReturn). A Drone without
+// Home is dispatched without range check, unless its Position is known and it
+// has no Destination (ErrNoDestination).
+func (d *Drone) CanExecute(p Policy, c Command) error {
+	switch c {
+	case Dispatch:
+		if (d.State != Loading && d.State != Loaded) || len(d.Medications) == 0 {
+			return ErrCommandNotAllowed
+		}
+
+		if d.Maintenance.Grounded {
+			return ErrGrounded
+		}
+
+		if d.BatteryCapacity < p.MinDispatchBattery {
+			return fmt.Errorf("%w: %d%% of battery, %d%% needed to dispatch", ErrLowBattery, d.BatteryCapacity, p.MinDispatchBattery)
+		}
+
+		switch {
+		case d.Home != nil && d.Destination != nil:
+			if _, err := d.CheckRange(p); err != nil {
+				return err
+			}
+		case d.Home != nil:
+			if _, err := d.CheckReturn(p); err != nil {
+				return err
+			}
+		case d.Position != nil && d.Destination == nil:
+			return fmt.Errorf("%w: the range of a drone without home needs its destination", ErrNoDestination)
+		}
+	case Return:
+		if d.State != Delivering && d.State != Delivered {
+			return ErrCommandNotAllowed
+		}
+	case Abort:
+		if d.State != Loading && d.State != Loaded && d.State != Delivering {
+			return ErrCommandNotAllowed
+		}
+	default:
+		return ErrUnknownCommand
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package drone_test
+
+import (
+	"testing"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDroneCanExecute(t *testing.T) {
+	om250g := drone.Medication{Name: "Omeprazol-250g", Weight: 250, Code: "OM_250", Image: "1023123asf"}
+	home := &drone.Coordinates{Latitude: 40.4168, Longitude: -3.7038}
+	near := &drone.Coordinates{Latitude: 40.4268, Longitude: -3.7038} // ~1.1 km
+	far := &drone.Coordinates{Latitude: 40.6168, Longitude: -3.7038}  // ~22 km
+	testCases := []struct {
+		name             string
+		expectedErr      error
+		droneState       drone.State
+		droneMedications []drone.Medication
+		droneHome        *drone.Coordinates
+		droneDestination *drone.Coordinates
+		dronePosition    *drone.Coordinates
+		command          drone.Command
+	}{
+		{
+			name:             "OK-Dispatch-Loaded",
+			droneState:       drone.Loaded,
+			droneMedications: []drone.Medication{om250g},
+			droneHome:        home,
+			droneDestination: near,
+			command:          drone.Dispatch,
+		},
+		{
+			name:             "Err-Dispatch-OutOfRange",
+			expectedErr:      drone.ErrOutOfRange,
+			droneState:       drone.Loaded,
+			droneMedications: []drone.Medication{om250g},
+			droneHome:        home,
+			droneDestination: far,
+			command:          drone.Dispatch,
+		},
+		{
+			name:             "OK-Dispatch-NoDestination",
+			droneState:       drone.Loaded,
+			droneMedications: []drone.Medication{om250g},
+			droneHome:        home,
+			command:          drone.Dispatch,
+		},
+		{
+			name:             "OK-Dispatch-NoDestination-NearHome",
+			droneState:       drone.Loaded,
+			droneMedications: []drone.Medication{om250g},
+			droneHome:        home,
+			dronePosition:    near,
+			command:          drone.Dispatch,
+		},
+		{
+			name:             "Err-Dispatch-NoDestination-FarFromHome",
+			expectedErr:      drone.ErrOutOfRange,
+			droneState:       drone.Loaded,
+			droneMedications: []drone.Medication{om250g},
+			droneHome:        home,
+			dronePosition:    far,
+			command:          drone.Dispatch,
+		},
+		{
+			name:             "Err-Dispatch-Positioned-NoHome-NoDestination",
+			expectedErr:      drone.ErrNoDestination,
+			droneState:       drone.Loaded,
+			droneMedications: []drone.Medication{om250g},
+			dronePosition:    near,
+			command:          drone.Dispatch,
+		},
+		{
+			name:             "OK-Dispatch-NoHome",
+			droneState:       drone.Loaded,
+			droneMedications: []drone.Medication{om250g},
+			droneDestination: near,
+			command:          drone.Dispatch,
+		},
+		{
+			name:             "OK-Dispatch-NoCoordinates",
+			droneState:       drone.Loaded,
+			droneMedications: []drone.Medication{om250g},
+			command:          drone.Dispatch,
+		},
+		{
+			name:        "Err-Dispatch-Empty",
+			expectedErr: drone.ErrCommandNotAllowed,
+			droneState:  drone.Loading,
+			command:     drone.Dispatch,
+		},
+		{
+			name:             "Err-Dispatch-Delivering",
+			expectedErr:      drone.ErrCommandNotAllowed,
+			droneState:       drone.Delivering,
+			droneMedications: []drone.Medication{om250g},
+			command:          drone.Dispatch,
+		},
+		{
+			name:       "OK-Return-Delivered",
+			droneState: drone.Delivered,
+			command:    drone.Return,
+		},
+		{
+			name:        "Err-Return-Idle",
+			expectedErr: drone.ErrCommandNotAllowed,
+			droneState:  drone.Idle,
+			command:     drone.Return,
+		},
+		{
+			name:       "OK-Abort-Delivering",
+			droneState: drone.Delivering,
+			command:    drone.Abort,
+		},
+		{
+			name:        "Err-Abort-Returning",
+			expectedErr: drone.ErrCommandNotAllowed,
+			droneState:  drone.Returning,
+			command:     drone.Abort,
+		},
+		{
+			name:        "Err-Unknown",
+			expectedErr: drone.ErrUnknownCommand,
+			droneState:  drone.Idle,
+			command:     "self-destruct",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := drone.Drone{
+				Serial:          "12345",
+				Model:           drone.Cruiserweight,
+				WeightLimit:     400,
+				BatteryCapacity: 80,
+				State:           tc.droneState,
+				Medications:     tc.droneMedications,
+				Home:            tc.droneHome,
+				Destination:     tc.droneDestination,
+				Position:        tc.dronePosition,
+			}
+			assert.ErrorIs(t, d.CanExecute(drone.DefaultPolicy(), tc.command), tc.expectedErr)
+		})
+	}
+}
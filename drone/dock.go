@@ -0,0 +1,308 @@
+package drone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrDockNotFound error occurs when a Dock doesn't exist inside the tenant.
+	ErrDockNotFound = errors.New("dock not found")
+	// ErrDockOccupied error occurs when a Dock with drones is removed.
+	ErrDockOccupied = errors.New("dock occupied")
+	// ErrNoDockAvailable error occurs when every Dock of the tenant is full.
+	ErrNoDockAvailable = errors.New("no dock available")
+	// ErrCharging error occurs when is tried 'to Load' a Drone in an active ChargingSession.
+	ErrCharging = errors.New("drone charging")
+)
+
+// ChargedLevel is the battery level a charging session ends at.
+const ChargedLevel uint8 = 95
+
+// reservationTTL bounds the time a Dock is reserved to a Drone not saved yet.
+const reservationTTL = time.Minute
+
+// Dock is a charging station of a tenant with room for Capacity drones.
+type Dock struct {
+	TenantID string
+	ID       string
+	Name     string
+	Location Coordinates
+	Capacity int
+	// ChargeRate is the battery percent a Drone charges per minute.
+	ChargeRate float64
+}
+
+// NewDock builds a Dock with a random ID.
+func NewDock(tenantID, name string, location Coordinates, capacity int, chargeRate float64) (Dock, error) {
+	if tenantID == "" {
+		return Dock{}, errors.New("tenant id is empty")
+	}
+
+	if name == "" {
+		return Dock{}, errors.New("name is empty")
+	}
+
+	if err := location.Validate(); err != nil {
+		return Dock{}, err
+	}
+
+	if capacity <= 0 {
+		return Dock{}, errors.New("capacity must be positive")
+	}
+
+	if chargeRate <= 0 || math.IsNaN(chargeRate) || math.IsInf(chargeRate, 0) {
+		return Dock{}, errors.New("charge rate must be positive")
+	}
+
+	return Dock{TenantID: tenantID, ID: newRandomID(), Name: name, Location: location, Capacity: capacity, ChargeRate: chargeRate}, nil
+}
+
+// ChargingSession is the charge of a Drone in a Dock.
+type ChargingSession struct {
+	TenantID     string
+	ID           string
+	DockID       string
+	Serial       string
+	StartedAt    time.Time
+	StartBattery uint8
+	// EndedAt is zero while the session is active.
+	EndedAt    time.Time
+	EndBattery uint8
+}
+
+// Active returns if the Drone is still charging.
+func (s ChargingSession) Active() bool {
+	return s.EndedAt.IsZero()
+}
+
+// EstimatedEnd returns when the Drone reaches ChargedLevel at the ChargeRate of the Dock.
+func (s ChargingSession) EstimatedEnd(dock Dock) time.Time {
+	if s.StartBattery >= ChargedLevel {
+		return s.StartedAt
+	}
+
+	minutes := float64(ChargedLevel-s.StartBattery) / dock.ChargeRate
+	return s.StartedAt.Add(time.Duration(minutes * float64(time.Minute)))
+}
+
+// DockStore persists the Docks and the ChargingSessions of the tenants.
+type DockStore interface {
+	// SaveDock persists a Dock.
+	SaveDock(ctx context.Context, d Dock) error
+	// Dock returns a Dock of the tenant.
+	// NOTE: Returns ErrDockNotFound if id doesn't match inside the tenant.
+	Dock(ctx context.Context, tenantID, id string) (Dock, error)
+	// Docks returns the Docks of the tenant.
+	Docks(ctx context.Context, tenantID string) ([]Dock, error)
+	// DeleteDock removes a Dock of the tenant.
+	// NOTE: Returns ErrDockNotFound if id doesn't match inside the tenant.
+	DeleteDock(ctx context.Context, tenantID, id string) error
+	// SaveChargingSession persists a ChargingSession.
+	SaveChargingSession(ctx context.Context, s ChargingSession) error
+	// ChargingSessions returns the ChargingSessions of a Dock of the tenant, oldest first.
+	ChargingSessions(ctx context.Context, tenantID, dockID string) ([]ChargingSession, error)
+}
+
+// Charging returns if the Drone is in an active ChargingSession.
+func (d *Drone) Charging() bool {
+	return d.ChargingSessionID != ""
+}
+
+// DockManager assigns the returning drones to the Docks of their tenant and
+// tracks their ChargingSessions.
+type DockManager struct {
+	storage Storage
+	docks   DockStore
+	now     func() time.Time
+
+	// mu serializes the dock assignments so a Dock never exceeds its Capacity.
+	// NOTE: it only covers the assignments of this process.
+	mu sync.Mutex
+	// reserved are the Docks assigned to the drones that may not be saved yet.
+	reserved map[reservationKey]reservation
+}
+
+// reservationKey identifies a Drone with a Dock reserved.
+type reservationKey struct {
+	tenantID string
+	serial   string
+}
+
+// reservation is a Dock assigned to a Drone at a time.
+type reservation struct {
+	dockID string
+	at     time.Time
+}
+
+// NewDockManager builds a DockManager.
+func NewDockManager(storage Storage, docks DockStore) *DockManager {
+	return &DockManager{
+		storage:  storage,
+		docks:    docks,
+		now:      func() time.Time { return time.Now().UTC() },
+		reserved: make(map[reservationKey]reservation),
+	}
+}
+
+// Occupancy returns the drones of the tenant docked in each Dock by its ID.
+func (m *DockManager) Occupancy(ctx context.Context, tenantID string) (map[string][]Drone, error) {
+	drones, err := m.storage.Drones(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("list drones: %w", err)
+	}
+
+	occupancy := make(map[string][]Drone)
+	for _, d := range drones {
+		if d.DockID != "" {
+			occupancy[d.DockID] = append(occupancy[d.DockID], d)
+		}
+	}
+
+	return occupancy, nil
+}
+
+// Track updates the dock of the Drone after a change of its state or battery:
+//   - Returning: it's assigned to the nearest Dock with room (if any).
+//   - Idle in its Dock: a ChargingSession starts, unless already charged.
+//   - Charging: the ChargingSession ends when the battery reaches ChargedLevel.
+//   - Delivering: it leaves its Dock, ending the ChargingSession (if any).
+//
+// NOTE: the ChargingSessions are saved before the Drone, a failure saving the
+// Drone leaves them ahead of it.
+func (m *DockManager) Track(ctx context.Context, d *Drone) error {
+	switch {
+	case d.State == Returning && d.DockID == "":
+		if err := m.assign(ctx, d); err != nil && !errors.Is(err, ErrNoDockAvailable) {
+			return err
+		}
+	case d.State == Delivering && d.DockID != "":
+		if err := m.endSession(ctx, d); err != nil {
+			return err
+		}
+
+		d.DockID = ""
+	case d.Charging() && d.BatteryCapacity >= ChargedLevel:
+		return m.endSession(ctx, d)
+	case d.State == Idle && d.DockID != "" && !d.Charging() && d.BatteryCapacity < ChargedLevel:
+		s := ChargingSession{
+			TenantID:     d.TenantID,
+			ID:           newRandomID(),
+			DockID:       d.DockID,
+			Serial:       d.Serial,
+			StartedAt:    m.now(),
+			StartBattery: d.BatteryCapacity,
+		}
+		if err := m.docks.SaveChargingSession(ctx, s); err != nil {
+			return fmt.Errorf("save charging session: %w", err)
+		}
+
+		d.ChargingSessionID = s.ID
+	}
+
+	return nil
+}
+
+// assign docks the Drone in the nearest Dock (to its Position, else its Home) with room.
+func (m *DockManager) assign(ctx context.Context, d *Drone) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	docks, err := m.docks.Docks(ctx, d.TenantID)
+	if err != nil {
+		return fmt.Errorf("list docks: %w", err)
+	}
+
+	drones, err := m.storage.Drones(ctx, d.TenantID)
+	if err != nil {
+		return fmt.Errorf("list drones: %w", err)
+	}
+
+	// the serials docked in each Dock, saved or reserved
+	occupancy := make(map[string]map[string]bool)
+	dock := func(dockID, serial string) {
+		if occupancy[dockID] == nil {
+			occupancy[dockID] = make(map[string]bool)
+		}
+		occupancy[dockID][serial] = true
+	}
+	docked := make(map[string]string)
+	for _, stored := range drones {
+		if stored.DockID != "" {
+			dock(stored.DockID, stored.Serial)
+			docked[stored.Serial] = stored.DockID
+		}
+	}
+
+	now := m.now()
+	delete(m.reserved, reservationKey{tenantID: d.TenantID, serial: d.Serial})
+	for k, r := range m.reserved {
+		if k.tenantID != d.TenantID {
+			continue
+		}
+
+		// NOTE: the reservation ends once saved, or expires if the Drone is never saved.
+		if docked[k.serial] == r.dockID || now.Sub(r.at) > reservationTTL {
+			delete(m.reserved, k)
+			continue
+		}
+
+		dock(r.dockID, k.serial)
+	}
+
+	from := d.Position
+	if from == nil {
+		from = d.Home
+	}
+
+	sort.SliceStable(docks, func(i, j int) bool {
+		if from != nil {
+			if di, dj := Distance(*from, docks[i].Location), Distance(*from, docks[j].Location); di != dj {
+				return di < dj
+			}
+		}
+
+		return docks[i].ID < docks[j].ID
+	})
+
+	for _, candidate := range docks {
+		if len(occupancy[candidate.ID]) < candidate.Capacity {
+			d.DockID = candidate.ID
+			m.reserved[reservationKey{tenantID: d.TenantID, serial: d.Serial}] = reservation{dockID: candidate.ID, at: now}
+			return nil
+		}
+	}
+
+	return ErrNoDockAvailable
+}
+
+// endSession ends the active ChargingSession of the Drone (if any).
+func (m *DockManager) endSession(ctx context.Context, d *Drone) error {
+	if !d.Charging() {
+		return nil
+	}
+
+	sessions, err := m.docks.ChargingSessions(ctx, d.TenantID, d.DockID)
+	if err != nil {
+		return fmt.Errorf("list charging sessions: %w", err)
+	}
+
+	for _, s := range sessions {
+		if s.ID != d.ChargingSessionID {
+			continue
+		}
+
+		s.EndedAt, s.EndBattery = m.now(), d.BatteryCapacity
+		if err := m.docks.SaveChargingSession(ctx, s); err != nil {
+			return fmt.Errorf("save charging session: %w", err)
+		}
+	}
+
+	d.ChargingSessionID = ""
+	return nil
+}
@@ -0,0 +1,113 @@
+package drone_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDock(t *testing.T) {
+	location := drone.Coordinates{Latitude: 40.42, Longitude: -3.7}
+	testCases := []struct {
+		name          string
+		location      drone.Coordinates
+		capacity      int
+		chargeRate    float64
+		expectedError bool
+	}{
+		{name: "OK", location: location, capacity: 2, chargeRate: 1.5},
+		{name: "Err: invalid location", location: drone.Coordinates{Latitude: 91}, capacity: 2, chargeRate: 1.5, expectedError: true},
+		{name: "Err: no capacity", location: location, chargeRate: 1.5, expectedError: true},
+		{name: "Err: no charge rate", location: location, capacity: 2, expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := drone.NewDock("hospital-a", "Roof", tc.location, tc.capacity, tc.chargeRate)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEmpty(t, d.ID)
+		})
+	}
+}
+
+func TestChargingSessionEstimatedEnd(t *testing.T) {
+	at := time.Date(2023, time.January, 10, 12, 0, 0, 0, time.UTC)
+	dock := drone.Dock{ChargeRate: 2.5}
+
+	assert.Equal(t, at.Add(30*time.Minute), drone.ChargingSession{StartedAt: at, StartBattery: 20}.EstimatedEnd(dock))
+	assert.Equal(t, at, drone.ChargingSession{StartedAt: at, StartBattery: 97}.EstimatedEnd(dock))
+}
+
+func TestDockManager(t *testing.T) {
+	ctx := context.Background()
+	st := storage.NewInMemory()
+	near, err := drone.NewDock("hospital-a", "Near", drone.Coordinates{Latitude: 0.001}, 1, 2)
+	require.NoError(t, err)
+	far, err := drone.NewDock("hospital-a", "Far", drone.Coordinates{Latitude: 0.1}, 1, 2)
+	require.NoError(t, err)
+	require.NoError(t, st.SaveDock(ctx, near))
+	require.NoError(t, st.SaveDock(ctx, far))
+	m := drone.NewDockManager(st, st)
+
+	home := drone.Coordinates{}
+	returning := func(serial string) drone.Drone {
+		return drone.Drone{TenantID: "hospital-a", Serial: serial, WeightLimit: 200, BatteryCapacity: 30, State: drone.Returning, Home: &home}
+	}
+
+	// the returning drones fill the docks, nearest first
+	first := returning("first")
+	require.NoError(t, m.Track(ctx, &first))
+	assert.Equal(t, near.ID, first.DockID)
+	second := returning("second")
+	require.NoError(t, m.Track(ctx, &second))
+	assert.Equal(t, far.ID, second.DockID, "the near dock is reserved to the unsaved first drone")
+	require.NoError(t, st.SaveDrone(ctx, first))
+	require.NoError(t, st.SaveDrone(ctx, second))
+	third := returning("third")
+	require.NoError(t, m.Track(ctx, &third))
+	assert.Empty(t, third.DockID)
+
+	// the docked drone charges once idle
+	first.ChangeState(drone.Idle)
+	require.NoError(t, m.Track(ctx, &first))
+	assert.True(t, first.Charging())
+	assert.False(t, first.IsAvailable(drone.DefaultPolicy()))
+	require.NoError(t, st.SaveDrone(ctx, first))
+	occupancy, err := m.Occupancy(ctx, "hospital-a")
+	require.NoError(t, err)
+	assert.Len(t, occupancy[near.ID], 1)
+
+	// the session ends at the charged level
+	first.UpdateBattery(drone.ChargedLevel)
+	require.NoError(t, m.Track(ctx, &first))
+	assert.False(t, first.Charging())
+	assert.True(t, first.IsAvailable(drone.DefaultPolicy()))
+	sessions, err := st.ChargingSessions(ctx, "hospital-a", near.ID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.False(t, sessions[0].Active())
+	assert.Equal(t, uint8(30), sessions[0].StartBattery)
+	assert.Equal(t, drone.ChargedLevel, sessions[0].EndBattery)
+
+	// a charged drone doesn't charge again
+	require.NoError(t, m.Track(ctx, &first))
+	assert.False(t, first.Charging())
+
+	// the drone leaves the dock on delivery
+	first.ChangeState(drone.Delivering)
+	require.NoError(t, m.Track(ctx, &first))
+	assert.Empty(t, first.DockID)
+	require.NoError(t, st.SaveDrone(ctx, first))
+	require.NoError(t, m.Track(ctx, &third))
+	assert.Equal(t, near.ID, third.DockID)
+}
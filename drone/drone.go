@@ -2,62 +2,142 @@ package drone
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
 )
 
+// serialValidation keeps the serials safe to use as file names (no separators, no dots).
+var serialValidation = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
 // Drone defines the properties of a drone.
 type Drone struct {
+	// TenantID identifies the fleet owner the drone belongs to.
+	TenantID        string
 	Serial          string
 	Model           Model
 	WeightLimit     uint32
 	BatteryCapacity uint8
 	State           State
 	Medications     []Medication
+	// Home is the base the Drone flies from and returns to (nil if unknown).
+	Home *Coordinates
+	// Destination is where the current delivery goes (nil if there is none).
+	Destination *Coordinates
+	// Waypoints is the route from Home to the Destination around the no-fly
+	// zones (nil for the straight flight), flown backwards to return.
+	Waypoints []Coordinates
+	// Position is the last position reported by the Drone (nil if unknown).
+	Position *Coordinates
+	// DockID is the Dock the Drone is assigned to or docked in ("" if none).
+	DockID string
+	// ChargingSessionID is the active ChargingSession of the Drone ("" if it isn't charging).
+	ChargingSessionID string
+	// Maintenance is the service record of the Drone, grounded drones are out of service.
+	Maintenance Maintenance
+	// Capabilities are the Capabilities declared by the Drone, on top of the ones of its Model.
+	Capabilities []Capability
+	// LinkKeyHash is the SHA-256 of the key the Drone authenticates its link with ("" until issued).
+	LinkKeyHash string `audit:"redacted"`
+
+	// events are the domain events not committed yet (never persisted with the Drone).
+	events []Event
 }
 
 var (
 	// ErrOverweight error occurs when the addition of a Medication exceed the `Weight Limit` of the drone.
 	ErrOverweight = errors.New("unable to add medication: overweight")
-	// ErrLowBattery error occurs when is tried 'to Load' a Drone with less than 25% of battery.
-	ErrLowBattery = errors.New("unable to add medication: overweight")
+	// ErrLowBattery error occurs when is tried 'to Load' or dispatch a Drone under the battery of its Policy.
+	ErrLowBattery = errors.New("low battery")
 	// ErrInvalidDroneState error occurs when is tried 'to Load' a Drone in a 'Loaded', 'Delivering', 'Delivered' or 'Returning' state.
 	ErrInvalidDroneState = errors.New("invalid drone state")
+	// ErrAlreadyRegistered error occurs when is registered a Drone with the serial of another one of the tenant.
+	ErrAlreadyRegistered = errors.New("drone already registered")
 )
 
-// NewDrone builds a new IDLE drone instance.
-func NewDrone(serial string, model Model, weightLimit uint32, battery uint8) (Drone, error) {
-	if weightLimit > 500 {
-		return Drone{}, errors.New("weight limit exceed 500g")
+// NewDrone builds a new IDLE drone instance owned by the given tenant, with
+// a weight limit up to the max weight of its model in the Policy, and the
+// capabilities it declares (if any).
+// NOTE: Returns a *ValidationError with every invalid field.
+func NewDrone(p Policy, tenantID string, serial string, model Model, weightLimit uint32, battery uint8, capabilities ...Capability) (Drone, error) {
+	verr := new(ValidationError)
+	if tenantID == "" {
+		verr.Add("tenant_id", CodeRequired, "tenant id is empty")
+	}
+
+	switch {
+	case serial == "":
+		verr.Add("serial", CodeRequired, "serial is empty")
+	case utf8.RuneCountInString(serial) > MaxSerialLength:
+		verr.Add("serial", CodeOutOfRange, fmt.Sprintf("serial exceed %d characters", MaxSerialLength))
+	case !serialValidation.MatchString(serial):
+		verr.Add("serial", CodeInvalidFormat, "serial only allows letters, numbers, '-' and '_'")
+	}
+
+	if model < Lightweight || model > Heavyweight {
+		verr.Add("model", CodeUnknown, fmt.Sprintf("unknown model %d", model))
+	} else if limit := p.MaxWeightOf(model); weightLimit > limit {
+		verr.Add("weight_limit", CodeOutOfRange, fmt.Sprintf("weight limit exceed %dg", limit))
 	}
 
 	if battery > 100 {
-		return Drone{}, errors.New("battery capacity exceed 100%")
+		verr.Add("battery", CodeOutOfRange, "battery capacity exceed 100%")
+	}
+
+	for _, c := range capabilities {
+		if !c.Valid() {
+			verr.Add("capabilities", CodeUnknown, fmt.Sprintf("unknown capability %q", c))
+			break
+		}
+	}
+
+	if err := verr.Err(); err != nil {
+		return Drone{}, err
 	}
 
-	return Drone{
+	d := Drone{
+		TenantID:        tenantID,
 		Serial:          serial,
 		Model:           model,
 		WeightLimit:     weightLimit,
 		BatteryCapacity: battery,
 		State:           Idle,
-	}, nil
+		Capabilities:    capabilities,
+	}
+	d.record(Event{Type: DroneRegistered, State: Idle})
+	return d, nil
 }
 
-// IsAvailable method returns if the current drone is available for load.
-func (d *Drone) IsAvailable() bool {
+// IsAvailable method returns if the current drone is available for load
+// with the MinLoadBattery of the Policy.
+// NOTE: a drone charging in its Dock isn't available until the charge ends,
+// and a grounded drone until it returns to service.
+func (d *Drone) IsAvailable(p Policy) bool {
 	return (d.State == Idle ||
 		d.State == Loading) &&
-		d.BatteryCapacity > 25 &&
+		!d.Charging() &&
+		!d.Maintenance.Grounded &&
+		d.BatteryCapacity >= p.MinLoadBattery &&
 		d.MedicationWeight() < d.WeightLimit
 }
 
-// AddMedications method adds a new medication to the Drone if it doesn't
-// exceed it WeightLimit.
-func (d *Drone) AddMedications(m Medication) error {
+// AddMedications method adds a new medication to the Drone, not grounded nor
+// charging, if it doesn't exceed it WeightLimit, has the MinLoadBattery of the Policy, and can carry
+// it with the loaded medications (see CanCarry).
+func (d *Drone) AddMedications(p Policy, m Medication) error {
 	if d.State != Idle && d.State != Loading {
 		return ErrInvalidDroneState
 	}
 
-	if d.BatteryCapacity < 25 {
+	if d.Maintenance.Grounded {
+		return ErrGrounded
+	}
+
+	if d.Charging() {
+		return ErrCharging
+	}
+
+	if d.BatteryCapacity < p.MinLoadBattery {
 		return ErrLowBattery
 	}
 
@@ -66,10 +146,99 @@ func (d *Drone) AddMedications(m Medication) error {
 		return ErrOverweight
 	}
 
+	if err := d.CanCarry(p, []Medication{m}); err != nil {
+		return err
+	}
+
 	d.Medications = append(d.Medications, m)
+	d.record(Event{Type: MedicationLoaded, Medication: &m, State: d.State})
+	return nil
+}
+
+// SetHome moves the base of the Drone.
+func (d *Drone) SetHome(c Coordinates) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	d.Home = &c
+	d.Waypoints = nil
+	return nil
+}
+
+// UpdatePosition sets the position reported by the Drone.
+func (d *Drone) UpdatePosition(c Coordinates) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	d.Position = &c
 	return nil
 }
 
+// SetDestination sets where the delivery goes, only before the Drone is dispatched.
+func (d *Drone) SetDestination(c Coordinates) error {
+	if d.State != Idle && d.State != Loading && d.State != Loaded {
+		return ErrInvalidDroneState
+	}
+
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	d.Destination = &c
+	d.Waypoints = nil
+	return nil
+}
+
+// ApplyReportedState moves the Drone to the State it reported, returning
+// ErrInvalidTransition if the State can't follow the current one (e.g. an
+// Idle Drone can't have Delivered).
+func (d *Drone) ApplyReportedState(s State) error {
+	if !s.Valid() {
+		return ErrInvalidDroneState
+	}
+
+	if d.State != s && !d.State.CanMoveTo(s) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, d.State, s)
+	}
+
+	d.ChangeState(s)
+	return nil
+}
+
+// ChangeState moves the Drone to a new State. A Drone back Idle from
+// Returning has finished its flight, so its Destination and Waypoints are
+// cleared (and it has no ETA anymore).
+func (d *Drone) ChangeState(s State) {
+	if d.State == s {
+		return
+	}
+
+	prev := d.State
+	d.State = s
+	if prev == Returning && s == Idle {
+		d.Destination, d.Waypoints = nil, nil
+	}
+
+	d.record(Event{Type: StateChanged, PreviousState: prev, State: s})
+}
+
+// UpdateBattery sets the battery level of the Drone, emitting a BatteryLow
+// event when the level drops under LowBatteryLevel.
+func (d *Drone) UpdateBattery(level uint8) {
+	prev := d.BatteryCapacity
+	if prev == level {
+		return
+	}
+
+	d.BatteryCapacity = level
+	d.record(Event{Type: BatteryChanged, State: d.State})
+	if prev >= LowBatteryLevel && level < LowBatteryLevel {
+		d.record(Event{Type: BatteryLow, State: d.State})
+	}
+}
+
 // MedicationWeight method returns how many Weight is loading the drone.
 func (d *Drone) MedicationWeight() uint32 {
 	var w uint32
@@ -1,7 +1,9 @@
 package drone_test
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hsequeda/drone/drone"
 	"github.com/stretchr/testify/assert"
@@ -12,7 +14,10 @@ func TestNewDrone(t *testing.T) {
 	testCases := []struct {
 		name        string
 		expectedErr bool
+		// invalidFields are the fields of the ValidationError.
+		invalidFields []string
 
+		droneTenant  string
 		droneSerial  string
 		droneModel   drone.Model
 		droneWeight  uint32
@@ -23,11 +28,13 @@ func TestNewDrone(t *testing.T) {
 		{
 			name:         "OK: Lightweight drone",
 			expectedErr:  false,
+			droneTenant:  "hospital-a",
 			droneSerial:  "1",
 			droneModel:   drone.Lightweight,
 			droneWeight:  100,
 			droneBattery: 80,
 			expected: drone.Drone{
+				TenantID:        "hospital-a",
 				Serial:          "1",
 				Model:           drone.Lightweight,
 				WeightLimit:     100,
@@ -38,11 +45,13 @@ func TestNewDrone(t *testing.T) {
 		{
 			name:         "OK: Heavyweight drone",
 			expectedErr:  false,
+			droneTenant:  "hospital-a",
 			droneSerial:  "2",
 			droneModel:   drone.Heavyweight,
 			droneWeight:  200,
 			droneBattery: 50,
 			expected: drone.Drone{
+				TenantID:        "hospital-a",
 				Serial:          "2",
 				Model:           drone.Heavyweight,
 				WeightLimit:     200,
@@ -51,32 +60,82 @@ func TestNewDrone(t *testing.T) {
 			},
 		},
 		{
-			name:         "Err: 'weight limit exceed 500g'",
-			expectedErr:  true,
-			droneSerial:  "1",
-			droneModel:   drone.Lightweight,
-			droneWeight:  800,
-			droneBattery: 80,
+			name:          "Err: 'weight limit exceed 500g'",
+			expectedErr:   true,
+			invalidFields: []string{"weight_limit"},
+			droneTenant:   "hospital-a",
+			droneSerial:   "1",
+			droneModel:    drone.Lightweight,
+			droneWeight:   800,
+			droneBattery:  80,
 		},
 		{
-			name:         "Err 'battery capacity exceed 100%'",
-			expectedErr:  true,
-			droneSerial:  "1",
-			droneModel:   drone.Lightweight,
-			droneWeight:  800,
-			droneBattery: 80,
+			name:          "Err 'battery capacity exceed 100%'",
+			expectedErr:   true,
+			invalidFields: []string{"battery"},
+			droneTenant:   "hospital-a",
+			droneSerial:   "1",
+			droneModel:    drone.Lightweight,
+			droneWeight:   100,
+			droneBattery:  120,
+		},
+		{
+			name:          "Err 'tenant id is empty'",
+			expectedErr:   true,
+			invalidFields: []string{"tenant_id"},
+			droneSerial:   "1",
+			droneModel:    drone.Lightweight,
+			droneWeight:   100,
+			droneBattery:  80,
+		},
+		{
+			name:          "Err 'serial exceed 100 characters'",
+			expectedErr:   true,
+			invalidFields: []string{"serial"},
+			droneTenant:   "hospital-a",
+			droneSerial:   strings.Repeat("S", drone.MaxSerialLength+1),
+			droneModel:    drone.Lightweight,
+			droneWeight:   100,
+			droneBattery:  80,
+		},
+		{
+			name:          "Err 'serial only allows letters, numbers, '-' and '_''",
+			expectedErr:   true,
+			invalidFields: []string{"serial"},
+			droneTenant:   "hospital-a",
+			droneSerial:   "../hospital-b/X",
+			droneModel:    drone.Lightweight,
+			droneWeight:   100,
+			droneBattery:  80,
+		},
+		{
+			name:          "Err: every invalid field",
+			expectedErr:   true,
+			invalidFields: []string{"tenant_id", "serial", "model", "battery"},
+			droneModel:    drone.Model(9),
+			droneWeight:   800,
+			droneBattery:  120,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			newDrone, err := drone.NewDrone(tc.droneSerial, tc.droneModel, tc.droneWeight, tc.droneBattery)
+			newDrone, err := drone.NewDrone(drone.DefaultPolicy(), tc.droneTenant, tc.droneSerial, tc.droneModel, tc.droneWeight, tc.droneBattery)
 			if tc.expectedErr {
-				require.Error(t, err)
+				var verr *drone.ValidationError
+				require.ErrorAs(t, err, &verr)
+				var fields []string
+				for _, f := range verr.Fields {
+					fields = append(fields, f.Field)
+				}
+				assert.Equal(t, tc.invalidFields, fields)
 				return
 			}
 
 			require.NoError(t, err)
+			require.Len(t, newDrone.Events(), 1)
+			assert.Equal(t, drone.DroneRegistered, newDrone.Events()[0].Type)
+			newDrone.ClearEvents()
 			assert.Equal(t, tc.expected, newDrone)
 		})
 	}
@@ -90,12 +149,13 @@ func TestAddMedicationToDrone(t *testing.T) {
 		Image:  "1023123asf",
 	}
 	testCases := []struct {
-		name             string
-		expectedErr      bool
-		droneMedications []drone.Medication
-		droneBattery     uint8
-		droneState       drone.State
-		newMedication    drone.Medication
+		name              string
+		expectedErr       bool
+		droneMedications  []drone.Medication
+		droneBattery      uint8
+		droneState        drone.State
+		chargingSessionID string
+		newMedication     drone.Medication
 	}{
 		{
 			name:          "OK-Idle",
@@ -123,6 +183,14 @@ func TestAddMedicationToDrone(t *testing.T) {
 			droneState:    drone.Loading,
 			newMedication: om250g,
 		},
+		{
+			name:              "Err-Charging",
+			droneBattery:      80,
+			expectedErr:       true,
+			droneState:        drone.Idle,
+			chargingSessionID: "session-1",
+			newMedication:     om250g,
+		},
 		{
 			name:             "Err-TooMuchWeight",
 			droneBattery:     80,
@@ -136,14 +204,15 @@ func TestAddMedicationToDrone(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			newDrone := drone.Drone{
-				Serial:          "12345",
-				Model:           drone.Cruiserweight,
-				WeightLimit:     400,
-				BatteryCapacity: tc.droneBattery,
-				State:           tc.droneState,
-				Medications:     tc.droneMedications,
+				Serial:            "12345",
+				Model:             drone.Cruiserweight,
+				WeightLimit:       400,
+				BatteryCapacity:   tc.droneBattery,
+				State:             tc.droneState,
+				Medications:       tc.droneMedications,
+				ChargingSessionID: tc.chargingSessionID,
 			}
-			err := newDrone.AddMedications(tc.newMedication)
+			err := newDrone.AddMedications(drone.DefaultPolicy(), tc.newMedication)
 			if tc.expectedErr {
 				require.Error(t, err)
 				return
@@ -153,3 +222,70 @@ func TestAddMedicationToDrone(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyReportedState(t *testing.T) {
+	testCases := []struct {
+		name        string
+		expectedErr error
+		from, to    drone.State
+	}{
+		{name: "OK-Load", from: drone.Idle, to: drone.Loading},
+		{name: "OK-CancelLoading", from: drone.Loaded, to: drone.Idle},
+		{name: "OK-TakeOff", from: drone.Loaded, to: drone.Delivering},
+		{name: "OK-Abort", from: drone.Delivering, to: drone.Returning},
+		{name: "OK-Land", from: drone.Returning, to: drone.Idle},
+		{name: "OK-Unchanged", from: drone.Delivering, to: drone.Delivering},
+		{name: "Err-IdleToDelivered", expectedErr: drone.ErrInvalidTransition, from: drone.Idle, to: drone.Delivered},
+		{name: "Err-DeliveringToLoaded", expectedErr: drone.ErrInvalidTransition, from: drone.Delivering, to: drone.Loaded},
+		{name: "Err-DeliveredToIdle", expectedErr: drone.ErrInvalidTransition, from: drone.Delivered, to: drone.Idle},
+		{name: "Err-UnknownState", expectedErr: drone.ErrInvalidDroneState, from: drone.Idle, to: drone.State(42)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := drone.Drone{Serial: "12345", State: tc.from}
+			err := d.ApplyReportedState(tc.to)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				assert.Equal(t, tc.from, d.State)
+				assert.Empty(t, d.Events())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.to, d.State)
+		})
+	}
+}
+
+func TestChangeStateEndsTheFlight(t *testing.T) {
+	home := drone.Coordinates{Latitude: 40.4168, Longitude: -3.7038}
+	destination := drone.Coordinates{Latitude: 40.4268, Longitude: -3.7038}
+	d := drone.Drone{
+		Serial:      "12345",
+		Model:       drone.Lightweight,
+		State:       drone.Loaded,
+		Medications: []drone.Medication{{Name: "Aspirin", Weight: 50, Code: "A01"}},
+		Home:        &home,
+		Destination: &destination,
+		Waypoints:   []drone.Coordinates{destination},
+	}
+
+	// an aborted load keeps the destination
+	d.ChangeState(drone.Idle)
+	assert.Equal(t, &destination, d.Destination)
+	_, err := drone.EstimateETA(d, time.Now())
+	assert.NoError(t, err)
+
+	d.ChangeState(drone.Delivering)
+	d.ChangeState(drone.Returning)
+	assert.Equal(t, &destination, d.Destination)
+
+	// back home the flight is over
+	d.ChangeState(drone.Idle)
+	assert.Nil(t, d.Destination)
+	assert.Nil(t, d.Waypoints)
+	_, err = drone.EstimateETA(d, time.Now())
+	assert.ErrorIs(t, err, drone.ErrNoDestination)
+	assert.Equal(t, &home, d.Home)
+}
@@ -0,0 +1,112 @@
+package drone
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrNoDelivery error occurs when the ETA of a Drone without medications on the way is estimated.
+var ErrNoDelivery = errors.New("no delivery in progress")
+
+// CruiseSpeeds are the speeds in m/s each Model flies empty, the heavier models fly slower.
+var CruiseSpeeds = map[Model]float64{
+	Lightweight:   16,
+	Middleweight:  14,
+	Cruiserweight: 12,
+	Heavyweight:   10,
+}
+
+const (
+	// PayloadSlowdown is the part of the cruise speed lost for each 100g of payload.
+	PayloadSlowdown = 0.02
+	// minSpeedFactor bounds the PayloadSlowdown to the half of the cruise speed.
+	minSpeedFactor = 0.5
+)
+
+// ETA is the estimated arrival of a Drone at its Destination.
+type ETA struct {
+	Arrival time.Time
+	// Distance is the distance in meters left to the Destination.
+	Distance float64
+	// Speed is the estimated speed in m/s with the payload.
+	Speed      float64
+	ComputedAt time.Time
+}
+
+// Remaining returns the flight time left to the Destination.
+func (e ETA) Remaining() time.Duration {
+	return e.Arrival.Sub(e.ComputedAt)
+}
+
+// EstimateETA estimates at now the arrival of the Drone at its Destination
+// through the Waypoints, at the cruise speed of its Model slowed by its
+// Medications. A Drone Loading, Loaded or Idle with Medications is estimated
+// as if dispatched now, a Drone Delivering flies from its last reported
+// Position (from Home if unknown). The other drones fail with ErrNoDelivery.
+func EstimateETA(d Drone, now time.Time) (ETA, error) {
+	pending := d.State == Loading || d.State == Loaded || (d.State == Idle && len(d.Medications) > 0)
+	if !pending && d.State != Delivering {
+		return ETA{}, ErrNoDelivery
+	}
+
+	if d.Destination == nil {
+		return ETA{}, ErrNoDestination
+	}
+
+	speed, ok := CruiseSpeeds[d.Model]
+	if !ok {
+		return ETA{}, fmt.Errorf("unknown cruise speed of model %s", d.Model)
+	}
+
+	speed *= math.Max(minSpeedFactor, 1-PayloadSlowdown*float64(d.MedicationWeight())/100)
+
+	var distance float64
+	switch {
+	case d.State == Delivering && d.Position != nil && d.Home == nil:
+		distance = Distance(*d.Position, *d.Destination)
+	case d.State == Delivering && d.Position != nil:
+		distance = remainingDistance(d.path(), *d.Position)
+	case d.Home == nil:
+		return ETA{}, ErrNoHome
+	default:
+		path := d.path()
+		for i := 1; i < len(path); i++ {
+			distance += Distance(path[i-1], path[i])
+		}
+	}
+
+	flight := time.Duration(distance / speed * float64(time.Second))
+	return ETA{Arrival: now.Add(flight), Distance: distance, Speed: speed, ComputedAt: now}, nil
+}
+
+// remainingDistance returns the distance from the position to the end of the
+// path, continuing from the end of the leg of the path closest to the position.
+func remainingDistance(path []Coordinates, position Coordinates) float64 {
+	p := toPoint(position)
+	leg, closest := 1, math.Inf(1)
+	for i := 1; i < len(path); i++ {
+		if d := segmentDistance(p, toPoint(path[i-1]), toPoint(path[i])); d < closest {
+			leg, closest = i, d
+		}
+	}
+
+	distance := Distance(position, path[leg])
+	for i := leg + 1; i < len(path); i++ {
+		distance += Distance(path[i-1], path[i])
+	}
+
+	return distance
+}
+
+// segmentDistance returns the distance in the plane from p to the segment from a to b.
+func segmentDistance(p, a, b point) float64 {
+	ab, ap := b.sub(a), p.sub(a)
+	t := 0.0
+	if l := ab.x*ab.x + ab.y*ab.y; l > 0 {
+		t = math.Max(0, math.Min(1, (ap.x*ab.x+ap.y*ab.y)/l))
+	}
+
+	return math.Hypot(ap.x-t*ab.x, ap.y-t*ab.y)
+}
@@ -0,0 +1,102 @@
+package drone_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateETA(t *testing.T) {
+	now := time.Date(2023, time.January, 10, 12, 0, 0, 0, time.UTC)
+	home := drone.Coordinates{}
+	destination := drone.Coordinates{Latitude: 0.01} // ~1112 m north
+	halfway := drone.Coordinates{Latitude: 0.005, Longitude: 0.0001}
+	meds := []drone.Medication{{Weight: 300}, {Weight: 200}}
+
+	testCases := []struct {
+		name          string
+		drone         drone.Drone
+		distance      float64
+		speed         float64
+		expectedError error
+	}{
+		{
+			name:     "OK: loaded, as if dispatched now",
+			drone:    drone.Drone{Model: drone.Lightweight, State: drone.Loaded, Home: &home, Destination: &destination},
+			distance: 1112,
+			speed:    16,
+		},
+		{
+			name:     "OK: the payload slows the drone",
+			drone:    drone.Drone{Model: drone.Lightweight, State: drone.Loaded, Medications: meds, Home: &home, Destination: &destination},
+			distance: 1112,
+			speed:    14.4,
+		},
+		{
+			name:     "OK: delivering from the reported position",
+			drone:    drone.Drone{Model: drone.Heavyweight, State: drone.Delivering, Home: &home, Destination: &destination, Position: &halfway},
+			distance: 556,
+			speed:    10,
+		},
+		{
+			name: "OK: delivering through the waypoints",
+			drone: drone.Drone{
+				Model: drone.Heavyweight, State: drone.Delivering, Home: &home, Destination: &destination, Position: &halfway,
+				Waypoints: []drone.Coordinates{{Latitude: 0.005}, {Latitude: 0.005, Longitude: 0.005}},
+			},
+			distance: 545 + 786, // along the leg to the second waypoint, then to the destination
+			speed:    10,
+		},
+		{
+			name:     "OK: delivering without position, from home",
+			drone:    drone.Drone{Model: drone.Heavyweight, State: drone.Delivering, Home: &home, Destination: &destination},
+			distance: 1112,
+			speed:    10,
+		},
+		{
+			name:     "OK: idle with medications",
+			drone:    drone.Drone{Model: drone.Lightweight, State: drone.Idle, Medications: meds, Home: &home, Destination: &destination},
+			distance: 1112,
+			speed:    14.4,
+		},
+		{
+			name:          "Err: idle without medications",
+			drone:         drone.Drone{Model: drone.Lightweight, State: drone.Idle, Home: &home, Destination: &destination},
+			expectedError: drone.ErrNoDelivery,
+		},
+		{
+			name:          "Err: returning",
+			drone:         drone.Drone{Model: drone.Heavyweight, State: drone.Returning, Home: &home, Destination: &destination},
+			expectedError: drone.ErrNoDelivery,
+		},
+		{
+			name:          "Err: without destination",
+			drone:         drone.Drone{Model: drone.Heavyweight, State: drone.Loaded, Home: &home},
+			expectedError: drone.ErrNoDestination,
+		},
+		{
+			name:          "Err: loaded without home",
+			drone:         drone.Drone{Model: drone.Heavyweight, State: drone.Loaded, Destination: &destination},
+			expectedError: drone.ErrNoHome,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			eta, err := drone.EstimateETA(tc.drone, now)
+			if tc.expectedError != nil {
+				require.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.InDelta(t, tc.distance, eta.Distance, 2)
+			assert.InDelta(t, tc.speed, eta.Speed, 0.001)
+			assert.Equal(t, now, eta.ComputedAt)
+			assert.InDelta(t, tc.distance/tc.speed, eta.Remaining().Seconds(), 0.5)
+		})
+	}
+}
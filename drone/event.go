@@ -0,0 +1,81 @@
+package drone
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// EventType defines the different domain events emitted by a Drone.
+type EventType string
+
+const (
+	DroneRegistered  EventType = "drone.registered"
+	MedicationLoaded EventType = "drone.medication_loaded"
+	StateChanged     EventType = "drone.state_changed"
+	BatteryChanged   EventType = "drone.battery_changed"
+	BatteryLow       EventType = "drone.battery_low"
+	// DroneGrounded and DroneReturnedToService track the Maintenance of a Drone.
+	DroneGrounded          EventType = "drone.grounded"
+	DroneReturnedToService EventType = "drone.returned_to_service"
+	// MedicationUnloaded compensates the MedicationLoaded of a load reverted by a failed split order.
+	MedicationUnloaded EventType = "drone.medication_unloaded"
+)
+
+// LowBatteryLevel is the battery level under which a Drone emits a BatteryLow event.
+const LowBatteryLevel uint8 = 25
+
+// Event is a domain event collected on the Drone aggregate and committed
+// through the Outbox when the Drone is saved.
+type Event struct {
+	// ID identifies the event. IDs sort in the order the events occurred.
+	ID         string
+	Type       EventType
+	TenantID   string
+	Serial     string
+	OccurredAt time.Time
+	// Medication is the medication of a MedicationLoaded or MedicationUnloaded event.
+	Medication *Medication `json:",omitempty"`
+	// PreviousState and State describe the transition of a StateChanged event.
+	PreviousState State `json:",omitempty"`
+	State         State `json:",omitempty"`
+	// BatteryLevel is the battery of the drone when the event occurred.
+	BatteryLevel uint8
+}
+
+// Events returns the domain events recorded on the Drone that are not committed yet.
+func (d *Drone) Events() []Event {
+	return d.events
+}
+
+// ClearEvents discards the recorded domain events.
+// NOTE: Storage implementations call it once the events are committed.
+func (d *Drone) ClearEvents() {
+	d.events = nil
+}
+
+// record appends a new domain event with the current snapshot of the Drone.
+func (d *Drone) record(e Event) {
+	now := time.Now().UTC()
+	e.ID = newEventID(now)
+	e.TenantID = d.TenantID
+	e.Serial = d.Serial
+	e.OccurredAt = now
+	e.BatteryLevel = d.BatteryCapacity
+	d.events = append(d.events, e)
+}
+
+// newRandomID returns a random hexadecimal identifier.
+func newRandomID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// newEventID returns a random identifier prefixed by the timestamp so IDs sort by occurrence.
+func newEventID(at time.Time) string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%020d-%s", at.UnixNano(), hex.EncodeToString(b))
+}
@@ -0,0 +1,47 @@
+package drone_test
+
+import (
+	"testing"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDroneEvents(t *testing.T) {
+	d, err := drone.NewDrone(drone.DefaultPolicy(), "hospital-a", "1", drone.Lightweight, 300, 30)
+	require.NoError(t, err)
+
+	om250g := drone.Medication{Name: "Omeprazol-250g", Weight: 250, Code: "OM_250", Image: "1023123asf"}
+	require.NoError(t, d.AddMedications(drone.DefaultPolicy(), om250g))
+	d.ChangeState(drone.Loaded)
+	d.ChangeState(drone.Loaded) // same state, no event
+	d.UpdateBattery(30)         // same level, no event
+	d.UpdateBattery(24)
+	d.UpdateBattery(20) // already low, no BatteryLow event
+
+	events := d.Events()
+	require.Len(t, events, 6)
+	assert.Equal(t, drone.DroneRegistered, events[0].Type)
+	assert.Equal(t, drone.MedicationLoaded, events[1].Type)
+	assert.Equal(t, &om250g, events[1].Medication)
+	assert.Equal(t, drone.StateChanged, events[2].Type)
+	assert.Equal(t, drone.Idle, events[2].PreviousState)
+	assert.Equal(t, drone.Loaded, events[2].State)
+	assert.Equal(t, drone.BatteryChanged, events[3].Type)
+	assert.Equal(t, uint8(24), events[3].BatteryLevel)
+	assert.Equal(t, drone.BatteryLow, events[4].Type)
+	assert.Equal(t, uint8(24), events[4].BatteryLevel)
+	assert.Equal(t, drone.BatteryChanged, events[5].Type)
+	assert.Equal(t, uint8(20), events[5].BatteryLevel)
+	for i, e := range events {
+		assert.Equal(t, "hospital-a", e.TenantID)
+		assert.Equal(t, "1", e.Serial)
+		if i > 0 {
+			assert.Less(t, events[i-1].ID, e.ID)
+		}
+	}
+
+	d.ClearEvents()
+	assert.Empty(t, d.Events())
+}
@@ -0,0 +1,42 @@
+package drone
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrInvalidCoordinates error occurs when a latitude or longitude is out of range.
+var ErrInvalidCoordinates = errors.New("invalid coordinates")
+
+// earthRadius is the mean radius of the Earth in meters.
+const earthRadius = 6_371_008.8
+
+// Coordinates is a WGS84 position in decimal degrees.
+type Coordinates struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Validate returns ErrInvalidCoordinates if the latitude isn't in [-90, 90]
+// or the longitude isn't in [-180, 180].
+func (c Coordinates) Validate() error {
+	if math.IsNaN(c.Latitude) || math.IsNaN(c.Longitude) ||
+		c.Latitude < -90 || c.Latitude > 90 ||
+		c.Longitude < -180 || c.Longitude > 180 {
+		return ErrInvalidCoordinates
+	}
+
+	return nil
+}
+
+// Distance returns the great-circle distance in meters between a and b (haversine formula).
+func Distance(a, b Coordinates) float64 {
+	lat1, lat2 := radians(a.Latitude), radians(b.Latitude)
+	dLat, dLon := lat2-lat1, radians(b.Longitude-a.Longitude)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
@@ -0,0 +1,38 @@
+package drone_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistance(t *testing.T) {
+	madrid := drone.Coordinates{Latitude: 40.4168, Longitude: -3.7038}
+	barcelona := drone.Coordinates{Latitude: 41.3874, Longitude: 2.1686}
+	testCases := []struct {
+		name     string
+		a, b     drone.Coordinates
+		expected float64
+	}{
+		{name: "same point", a: madrid, b: madrid, expected: 0},
+		{name: "Madrid-Barcelona", a: madrid, b: barcelona, expected: 505_000},
+		{name: "one degree of latitude", a: drone.Coordinates{}, b: drone.Coordinates{Latitude: 1}, expected: 111_195},
+		{name: "across the antimeridian", a: drone.Coordinates{Longitude: 179.5}, b: drone.Coordinates{Longitude: -179.5}, expected: 111_195},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.InDelta(t, tc.expected, drone.Distance(tc.a, tc.b), 1000)
+			assert.InDelta(t, drone.Distance(tc.a, tc.b), drone.Distance(tc.b, tc.a), 1e-6)
+		})
+	}
+}
+
+func TestCoordinatesValidate(t *testing.T) {
+	assert.NoError(t, drone.Coordinates{Latitude: -90, Longitude: 180}.Validate())
+	assert.ErrorIs(t, drone.Coordinates{Latitude: 90.1}.Validate(), drone.ErrInvalidCoordinates)
+	assert.ErrorIs(t, drone.Coordinates{Longitude: -180.1}.Validate(), drone.ErrInvalidCoordinates)
+	assert.ErrorIs(t, drone.Coordinates{Latitude: math.NaN()}.Validate(), drone.ErrInvalidCoordinates)
+}
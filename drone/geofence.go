@@ -0,0 +1,353 @@
+package drone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	// ErrGeofenceNotFound error occurs when a Geofence doesn't exist inside the tenant.
+	ErrGeofenceNotFound = errors.New("geofence not found")
+	// ErrInvalidGeofence error occurs when the polygon of a Geofence isn't a simple polygon.
+	ErrInvalidGeofence = errors.New("invalid geofence")
+	// ErrNoFlyZone error occurs when a position or a route is inside a Geofence.
+	ErrNoFlyZone = errors.New("no-fly zone")
+	// ErrNoRoute error occurs when there is no route around the Geofences to a destination.
+	ErrNoRoute = errors.New("no route around the no-fly zones")
+)
+
+// Geofence is a no-fly zone of a tenant.
+// NOTE: the Polygon is handled in the plane of the longitude and the latitude,
+// which is accurate for zones of a few km far from the poles and the antimeridian.
+type Geofence struct {
+	TenantID string
+	ID       string
+	Name     string
+	// Polygon is the open ring of vertices of the zone (the first vertex isn't repeated).
+	Polygon []Coordinates
+}
+
+// GeofenceStore persists the Geofences of the tenants.
+type GeofenceStore interface {
+	// SaveGeofence persists a Geofence.
+	SaveGeofence(ctx context.Context, g Geofence) error
+	// Geofences returns the Geofences of the tenant.
+	Geofences(ctx context.Context, tenantID string) ([]Geofence, error)
+	// DeleteGeofence removes a Geofence of the tenant.
+	// NOTE: Returns ErrGeofenceNotFound if id doesn't match inside the tenant.
+	DeleteGeofence(ctx context.Context, tenantID, id string) error
+}
+
+// NewGeofence builds a Geofence with a random ID. The polygon can be closed (the
+// GeoJSON way) or open, and must have at least 3 vertices and no self intersections.
+func NewGeofence(tenantID, name string, polygon []Coordinates) (Geofence, error) {
+	if tenantID == "" {
+		return Geofence{}, errors.New("tenant id is empty")
+	}
+
+	if n := len(polygon); n > 1 && polygon[0] == polygon[n-1] {
+		polygon = polygon[:n-1]
+	}
+
+	if len(polygon) < 3 {
+		return Geofence{}, fmt.Errorf("%w: a polygon needs at least 3 vertices", ErrInvalidGeofence)
+	}
+
+	for _, c := range polygon {
+		if err := c.Validate(); err != nil {
+			return Geofence{}, fmt.Errorf("%w: %w", ErrInvalidGeofence, err)
+		}
+	}
+
+	g := Geofence{TenantID: tenantID, ID: newRandomID(), Name: name, Polygon: append([]Coordinates(nil), polygon...)}
+	if g.area() == 0 {
+		return Geofence{}, fmt.Errorf("%w: the polygon has no area", ErrInvalidGeofence)
+	}
+
+	if !g.simple() {
+		return Geofence{}, fmt.Errorf("%w: the polygon intersects itself", ErrInvalidGeofence)
+	}
+
+	return g, nil
+}
+
+// Contains returns if the position is inside the Geofence (or on its border).
+func (g Geofence) Contains(c Coordinates) bool {
+	p := toPoint(c)
+	inside := false
+	for i := range g.Polygon {
+		a, b := g.edge(i)
+		if onSegment(a, b, p) {
+			return true
+		}
+
+		if (a.y > p.y) != (b.y > p.y) && p.x < a.x+(p.y-a.y)*(b.x-a.x)/(b.y-a.y) {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+// Crosses returns if the straight flight from a to b enters the Geofence.
+func (g Geofence) Crosses(a, b Coordinates) bool {
+	if g.Contains(a) || g.Contains(b) {
+		return true
+	}
+
+	p, q := toPoint(a), toPoint(b)
+	for i := range g.Polygon {
+		if e1, e2 := g.edge(i); intersect(p, q, e1, e2) {
+			return true
+		}
+	}
+
+	// NOTE: a flight through two vertices doesn't intersect the edges strictly.
+	return g.Contains(Coordinates{Latitude: (a.Latitude + b.Latitude) / 2, Longitude: (a.Longitude + b.Longitude) / 2})
+}
+
+// edge returns the points of the edge from the vertex i to the next one.
+func (g Geofence) edge(i int) (point, point) {
+	return toPoint(g.Polygon[i]), toPoint(g.Polygon[(i+1)%len(g.Polygon)])
+}
+
+// area returns the signed area of the Polygon, positive when counterclockwise.
+func (g Geofence) area() float64 {
+	var a float64
+	for i := range g.Polygon {
+		p, q := g.edge(i)
+		a += p.x*q.y - q.x*p.y
+	}
+
+	return a / 2
+}
+
+// simple returns if no two non adjacent edges of the Polygon intersect.
+func (g Geofence) simple() bool {
+	n := len(g.Polygon)
+	for i := 0; i < n; i++ {
+		for j := i + 2; j < n; j++ {
+			if i == 0 && j == n-1 {
+				continue
+			}
+
+			a1, a2 := g.edge(i)
+			b1, b2 := g.edge(j)
+			if intersect(a1, a2, b1, b2) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// routeClearance is the distance in degrees (about 11 m) the waypoints keep from the corners of the Geofences.
+const routeClearance = 1e-4
+
+// corners returns the convex vertices of the Geofence moved routeClearance
+// outwards, the only points a shortest route bends at.
+func (g Geofence) corners() []Coordinates {
+	orientation := math.Copysign(1, g.area())
+	n := len(g.Polygon)
+	var corners []Coordinates
+	for i := range g.Polygon {
+		prev, v, next := toPoint(g.Polygon[(i+n-1)%n]), toPoint(g.Polygon[i]), toPoint(g.Polygon[(i+1)%n])
+		if cross(prev, v, next)*orientation <= 0 {
+			continue
+		}
+
+		out := unit(v.sub(prev)).add(unit(v.sub(next)))
+		out = unit(out)
+		corners = append(corners, Coordinates{
+			Latitude:  v.y + out.y*routeClearance,
+			Longitude: v.x + out.x*routeClearance,
+		})
+	}
+
+	return corners
+}
+
+// Route returns the waypoints of the shortest route from `from` to `to` around
+// the zones, nil if the straight flight is clear. It fails with ErrNoFlyZone
+// when from or to are inside a zone and with ErrNoRoute when the zones block
+// every route.
+func Route(from, to Coordinates, zones []Geofence) ([]Coordinates, error) {
+	for _, g := range zones {
+		if g.Contains(to) {
+			return nil, fmt.Errorf("%w: destination inside %q", ErrNoFlyZone, g.Name)
+		}
+
+		if g.Contains(from) {
+			return nil, fmt.Errorf("%w: home inside %q", ErrNoFlyZone, g.Name)
+		}
+	}
+
+	if unobstructed(from, to, zones) {
+		return nil, nil
+	}
+
+	// Dijkstra over the visibility graph of from, to and the corners of the zones.
+	nodes := []Coordinates{from, to}
+	for _, g := range zones {
+		for _, c := range g.corners() {
+			if !inAny(c, zones) {
+				nodes = append(nodes, c)
+			}
+		}
+	}
+
+	dist := make([]float64, len(nodes))
+	prev := make([]int, len(nodes))
+	done := make([]bool, len(nodes))
+	for i := range nodes {
+		dist[i], prev[i] = math.Inf(1), -1
+	}
+	dist[0] = 0
+
+	for {
+		u := -1
+		for i := range nodes {
+			if !done[i] && !math.IsInf(dist[i], 1) && (u == -1 || dist[i] < dist[u]) {
+				u = i
+			}
+		}
+
+		if u == -1 {
+			return nil, ErrNoRoute
+		}
+
+		if u == 1 {
+			break
+		}
+
+		done[u] = true
+		for v := range nodes {
+			if done[v] {
+				continue
+			}
+
+			if d := dist[u] + Distance(nodes[u], nodes[v]); d < dist[v] && unobstructed(nodes[u], nodes[v], zones) {
+				dist[v], prev[v] = d, u
+			}
+		}
+	}
+
+	var waypoints []Coordinates
+	for i := prev[1]; i > 0; i = prev[i] {
+		waypoints = append([]Coordinates{nodes[i]}, waypoints...)
+	}
+
+	return waypoints, nil
+}
+
+// Route sets the Waypoints of the flight from Home to the Destination around
+// the zones (none without Home or Destination), see the Route function.
+func (d *Drone) Route(zones []Geofence) error {
+	d.Waypoints = nil
+	if d.Home == nil || d.Destination == nil {
+		return nil
+	}
+
+	waypoints, err := Route(*d.Home, *d.Destination, zones)
+	if err != nil {
+		return err
+	}
+
+	d.Waypoints = waypoints
+	return nil
+}
+
+// CheckRoute returns ErrNoFlyZone if the flight from Home through the
+// Waypoints to the Destination crosses any of the zones, as when a zone is
+// created after the Drone was routed.
+func (d *Drone) CheckRoute(zones []Geofence) error {
+	path := d.path()
+	for i := 1; i < len(path); i++ {
+		for _, g := range zones {
+			if g.Crosses(path[i-1], path[i]) {
+				return fmt.Errorf("%w: route crosses %q", ErrNoFlyZone, g.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// path returns the positions of the flight from Home to the Destination (nil if any is unknown).
+func (d *Drone) path() []Coordinates {
+	if d.Home == nil || d.Destination == nil {
+		return nil
+	}
+
+	path := append([]Coordinates{*d.Home}, d.Waypoints...)
+	return append(path, *d.Destination)
+}
+
+// unobstructed returns if the straight flight from a to b doesn't cross any zone.
+func unobstructed(a, b Coordinates, zones []Geofence) bool {
+	for _, g := range zones {
+		if g.Crosses(a, b) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// inAny returns if the position is inside any zone.
+func inAny(c Coordinates, zones []Geofence) bool {
+	for _, g := range zones {
+		if g.Contains(c) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// point is a position in the plane of the longitude (x) and the latitude (y).
+type point struct{ x, y float64 }
+
+func toPoint(c Coordinates) point {
+	return point{x: c.Longitude, y: c.Latitude}
+}
+
+func (p point) sub(q point) point { return point{x: p.x - q.x, y: p.y - q.y} }
+
+func (p point) add(q point) point { return point{x: p.x + q.x, y: p.y + q.y} }
+
+// unit returns the vector p with length 1 (or p itself if it's zero).
+func unit(p point) point {
+	l := math.Hypot(p.x, p.y)
+	if l == 0 {
+		return p
+	}
+
+	return point{x: p.x / l, y: p.y / l}
+}
+
+// cross returns the cross product of o->a and o->b, positive when o, a, b turn counterclockwise.
+func cross(o, a, b point) float64 {
+	return (a.x-o.x)*(b.y-o.y) - (a.y-o.y)*(b.x-o.x)
+}
+
+// onSegment returns if p lies on the segment from a to b.
+func onSegment(a, b, p point) bool {
+	return cross(a, b, p) == 0 &&
+		math.Min(a.x, b.x) <= p.x && p.x <= math.Max(a.x, b.x) &&
+		math.Min(a.y, b.y) <= p.y && p.y <= math.Max(a.y, b.y)
+}
+
+// intersect returns if the segments p1-p2 and q1-q2 intersect (touching included).
+func intersect(p1, p2, q1, q2 point) bool {
+	d1, d2 := cross(q1, q2, p1), cross(q1, q2, p2)
+	d3, d4 := cross(p1, p2, q1), cross(p1, p2, q2)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+
+	return onSegment(q1, q2, p1) || onSegment(q1, q2, p2) || onSegment(p1, p2, q1) || onSegment(p1, p2, q2)
+}
@@ -0,0 +1,147 @@
+package drone_test
+
+import (
+	"testing"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// square returns the Geofence of the square with the corners (lat1, lon1) and (lat2, lon2).
+func square(t *testing.T, name string, lat1, lon1, lat2, lon2 float64) drone.Geofence {
+	t.Helper()
+	g, err := drone.NewGeofence("hospital-a", name, []drone.Coordinates{
+		{Latitude: lat1, Longitude: lon1},
+		{Latitude: lat1, Longitude: lon2},
+		{Latitude: lat2, Longitude: lon2},
+		{Latitude: lat2, Longitude: lon1},
+	})
+	require.NoError(t, err)
+	return g
+}
+
+func TestNewGeofence(t *testing.T) {
+	testCases := []struct {
+		name          string
+		polygon       []drone.Coordinates
+		expectedError error
+		vertices      int
+	}{
+		{
+			name:     "OK: the closed ring is opened",
+			polygon:  []drone.Coordinates{{}, {Longitude: 1}, {Latitude: 1}, {}},
+			vertices: 3,
+		},
+		{
+			name:          "Err: less than 3 vertices",
+			polygon:       []drone.Coordinates{{}, {Longitude: 1}, {}},
+			expectedError: drone.ErrInvalidGeofence,
+		},
+		{
+			name:          "Err: invalid coordinates",
+			polygon:       []drone.Coordinates{{}, {Longitude: 200}, {Latitude: 1}},
+			expectedError: drone.ErrInvalidCoordinates,
+		},
+		{
+			name:          "Err: no area",
+			polygon:       []drone.Coordinates{{}, {Longitude: 1}, {Longitude: 2}},
+			expectedError: drone.ErrInvalidGeofence,
+		},
+		{
+			name:          "Err: self intersection",
+			polygon:       []drone.Coordinates{{}, {Latitude: 2, Longitude: 2}, {Longitude: 2}, {Latitude: 1}},
+			expectedError: drone.ErrInvalidGeofence,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g, err := drone.NewGeofence("hospital-a", "zone", tc.polygon)
+			if tc.expectedError != nil {
+				require.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEmpty(t, g.ID)
+			assert.Len(t, g.Polygon, tc.vertices)
+		})
+	}
+}
+
+func TestGeofenceCrosses(t *testing.T) {
+	g := square(t, "zone", 0, 0, 1, 1)
+
+	assert.True(t, g.Contains(drone.Coordinates{Latitude: 0.5, Longitude: 0.5}))
+	assert.True(t, g.Contains(drone.Coordinates{Latitude: 0, Longitude: 0.5}))
+	assert.False(t, g.Contains(drone.Coordinates{Latitude: 1.5, Longitude: 0.5}))
+
+	assert.True(t, g.Crosses(drone.Coordinates{Latitude: 0.5, Longitude: -1}, drone.Coordinates{Latitude: 0.5, Longitude: 2}))
+	// through two opposite vertices
+	assert.True(t, g.Crosses(drone.Coordinates{Latitude: -1, Longitude: -1}, drone.Coordinates{Latitude: 2, Longitude: 2}))
+	assert.False(t, g.Crosses(drone.Coordinates{Latitude: 2, Longitude: -1}, drone.Coordinates{Latitude: 2, Longitude: 2}))
+}
+
+func TestRoute(t *testing.T) {
+	home := drone.Coordinates{Latitude: 0.005, Longitude: 0}
+	destination := drone.Coordinates{Latitude: 0.005, Longitude: 0.03}
+	zone := square(t, "airport", 0, 0.01, 0.02, 0.02)
+
+	t.Run("OK: straight flight", func(t *testing.T) {
+		waypoints, err := drone.Route(home, drone.Coordinates{Latitude: 0.03, Longitude: 0}, []drone.Geofence{zone})
+		require.NoError(t, err)
+		assert.Nil(t, waypoints)
+	})
+
+	t.Run("OK: around the zone by the shortest side", func(t *testing.T) {
+		waypoints, err := drone.Route(home, destination, []drone.Geofence{zone})
+		require.NoError(t, err)
+		require.Len(t, waypoints, 2)
+		for _, w := range waypoints {
+			assert.Less(t, w.Latitude, 0.0)
+		}
+
+		d := drone.Drone{Home: &home, Destination: &destination, Waypoints: waypoints}
+		assert.NoError(t, d.CheckRoute([]drone.Geofence{zone}))
+	})
+
+	t.Run("Err: destination inside a zone", func(t *testing.T) {
+		_, err := drone.Route(home, drone.Coordinates{Latitude: 0.01, Longitude: 0.015}, []drone.Geofence{zone})
+		assert.ErrorIs(t, err, drone.ErrNoFlyZone)
+	})
+
+	t.Run("Err: home walled in", func(t *testing.T) {
+		walls := []drone.Geofence{
+			square(t, "north", 0.01, -0.01, 0.02, 0.02),
+			square(t, "south", -0.01, -0.01, 0, 0.02),
+			square(t, "west", -0.01, -0.01, 0.02, -0.005),
+			square(t, "east", -0.01, 0.005, 0.02, 0.02),
+		}
+		_, err := drone.Route(home, destination, walls)
+		assert.ErrorIs(t, err, drone.ErrNoRoute)
+	})
+}
+
+func TestDroneRoute(t *testing.T) {
+	home := drone.Coordinates{Latitude: 0.005, Longitude: 0}
+	destination := drone.Coordinates{Latitude: 0.005, Longitude: 0.03}
+	zone := square(t, "airport", 0, 0.01, 0.02, 0.02)
+	d := drone.Drone{Model: drone.Lightweight, BatteryCapacity: 100, State: drone.Idle, Home: &home, Destination: &destination}
+
+	// the straight flight crosses the zone created after the destination was set
+	assert.ErrorIs(t, d.CheckRoute([]drone.Geofence{zone}), drone.ErrNoFlyZone)
+	straight, err := d.CheckRange(drone.DefaultPolicy())
+	require.NoError(t, err)
+
+	require.NoError(t, d.Route([]drone.Geofence{zone}))
+	assert.NotEmpty(t, d.Waypoints)
+	assert.NoError(t, d.CheckRoute([]drone.Geofence{zone}))
+	routed, err := d.CheckRange(drone.DefaultPolicy())
+	require.NoError(t, err)
+	assert.Greater(t, routed.Distance, straight.Distance)
+
+	// a new destination must be routed again
+	require.NoError(t, d.SetDestination(drone.Coordinates{Latitude: 0.03, Longitude: 0}))
+	assert.Nil(t, d.Waypoints)
+}
@@ -0,0 +1,124 @@
+package drone
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	// ErrMissingCapability error occurs when a Medication needs a Capability the Drone lacks.
+	ErrMissingCapability = errors.New("unable to add medication: missing capability")
+	// ErrIncompatibleMedication error occurs when a Medication can't share the Drone with another one.
+	ErrIncompatibleMedication = errors.New("unable to add medication: incompatible medications")
+)
+
+// HandlingClass is a special handling a Medication needs on the flight.
+type HandlingClass string
+
+const (
+	// ColdChain medications travel refrigerated, in an InsulatedBay.
+	ColdChain HandlingClass = "cold_chain"
+	// Hazardous medications travel in HazmatCertified drones.
+	Hazardous HandlingClass = "hazardous"
+)
+
+// Capability is an equipment or certification of a Drone.
+type Capability string
+
+const (
+	// InsulatedBay keeps the ColdChain medications refrigerated.
+	InsulatedBay Capability = "insulated_bay"
+	// HazmatCertified drones can carry the Hazardous medications.
+	HazmatCertified Capability = "hazmat_certified"
+)
+
+// KnownCapabilities are every Capability a Drone can have.
+var KnownCapabilities = []Capability{InsulatedBay, HazmatCertified}
+
+// Requirements are the Capability each HandlingClass needs.
+var Requirements = map[HandlingClass]Capability{
+	ColdChain: InsulatedBay,
+	Hazardous: HazmatCertified,
+}
+
+// Valid returns if the HandlingClass is known.
+func (c HandlingClass) Valid() bool {
+	_, ok := Requirements[c]
+	return ok
+}
+
+// Valid returns if the Capability is known.
+func (c Capability) Valid() bool {
+	return slices.Contains(KnownCapabilities, c)
+}
+
+// HasCapability returns if the Drone, or its Model in the Policy, has the Capability.
+func (d *Drone) HasCapability(p Policy, c Capability) bool {
+	return slices.Contains(d.Capabilities, c) || slices.Contains(p.ModelCapabilities[d.Model], c)
+}
+
+// AllCapabilities returns the Capabilities of the Drone and its Model in the
+// Policy, in the order of KnownCapabilities.
+func (d *Drone) AllCapabilities(p Policy) []Capability {
+	var all []Capability
+	for _, c := range KnownCapabilities {
+		if d.HasCapability(p, c) {
+			all = append(all, c)
+		}
+	}
+
+	return all
+}
+
+// SetCapabilities replaces the Capabilities declared by the Drone. It returns
+// a *ValidationError for an unknown Capability, and ErrMissingCapability if a
+// loaded Medication needs a Capability the Drone would lose.
+func (d *Drone) SetCapabilities(p Policy, capabilities []Capability) error {
+	verr := new(ValidationError)
+	for _, c := range capabilities {
+		if !c.Valid() {
+			verr.Add("capabilities", CodeUnknown, fmt.Sprintf("unknown capability %q", c))
+			break
+		}
+	}
+
+	if err := verr.Err(); err != nil {
+		return err
+	}
+
+	updated := Drone{Model: d.Model, Capabilities: capabilities}
+	if err := updated.CanCarry(p, d.Medications); err != nil {
+		return err
+	}
+
+	d.Capabilities = capabilities
+	return nil
+}
+
+// CanCarry returns ErrMissingCapability if the Drone lacks a Capability the
+// medications need under the Policy, and ErrIncompatibleMedication if any of
+// them can't share the Drone with another one, loaded or not.
+func (d *Drone) CanCarry(p Policy, meds []Medication) error {
+	for i, m := range meds {
+		for _, class := range m.Handling {
+			if c := Requirements[class]; !d.HasCapability(p, c) {
+				return fmt.Errorf("%w: %s needs %s", ErrMissingCapability, m.Code, c)
+			}
+		}
+
+		// NOTE: each pair is checked once, the loaded medications were checked when added.
+		for _, other := range append(d.Medications[:len(d.Medications):len(d.Medications)], meds[:i]...) {
+			if m.IncompatibleWith(other) {
+				return fmt.Errorf("%w: %s can't travel with %s", ErrIncompatibleMedication, m.Code, other.Code)
+			}
+		}
+	}
+
+	return nil
+}
+
+// IncompatibleWith returns if any of the Medications declares the other incompatible.
+func (m Medication) IncompatibleWith(other Medication) bool {
+	return slices.Contains(m.Incompatible, other.Code) || slices.Contains(other.Incompatible, m.Code)
+}
@@ -0,0 +1,140 @@
+package drone_test
+
+import (
+	"testing"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// insulatedHeavyweights returns the DefaultPolicy with an insulated bay in every heavyweight drone.
+func insulatedHeavyweights() drone.Policy {
+	p := drone.DefaultPolicy()
+	p.ModelCapabilities = map[drone.Model][]drone.Capability{drone.Heavyweight: {drone.InsulatedBay}}
+	return p
+}
+
+func TestDroneCapabilities(t *testing.T) {
+	p := insulatedHeavyweights()
+	d, err := drone.NewDrone(p, "hospital-a", "1", drone.Heavyweight, 500, 80, drone.HazmatCertified)
+	require.NoError(t, err)
+	assert.True(t, d.HasCapability(p, drone.HazmatCertified))
+	// from the model
+	assert.True(t, d.HasCapability(p, drone.InsulatedBay))
+	assert.Equal(t, []drone.Capability{drone.InsulatedBay, drone.HazmatCertified}, d.AllCapabilities(p))
+
+	// the models have no capabilities by default
+	assert.False(t, d.HasCapability(drone.DefaultPolicy(), drone.InsulatedBay))
+	assert.Equal(t, []drone.Capability{drone.HazmatCertified}, d.AllCapabilities(drone.DefaultPolicy()))
+
+	d, err = drone.NewDrone(p, "hospital-a", "2", drone.Lightweight, 100, 80)
+	require.NoError(t, err)
+	assert.Empty(t, d.AllCapabilities(p))
+
+	_, err = drone.NewDrone(drone.DefaultPolicy(), "hospital-a", "3", drone.Lightweight, 100, 80, "teleporter")
+	var verr *drone.ValidationError
+	require.ErrorAs(t, err, &verr)
+	require.Len(t, verr.Fields, 1)
+	assert.Equal(t, "capabilities", verr.Fields[0].Field)
+	assert.Equal(t, drone.CodeUnknown, verr.Fields[0].Code)
+}
+
+func TestSetCapabilities(t *testing.T) {
+	p := insulatedHeavyweights()
+	d := drone.Drone{Model: drone.Lightweight, Capabilities: []drone.Capability{drone.HazmatCertified}}
+	require.NoError(t, d.SetCapabilities(p, []drone.Capability{drone.InsulatedBay}))
+	assert.Equal(t, []drone.Capability{drone.InsulatedBay}, d.Capabilities)
+
+	var verr *drone.ValidationError
+	require.ErrorAs(t, d.SetCapabilities(p, []drone.Capability{"teleporter"}), &verr)
+	assert.Equal(t, "capabilities", verr.Fields[0].Field)
+
+	// a loaded medication keeps the capability it needs
+	d.Medications = []drone.Medication{{Name: "Insulin", Code: "IN_50", Weight: 50, Handling: []drone.HandlingClass{drone.ColdChain}}}
+	assert.ErrorIs(t, d.SetCapabilities(p, nil), drone.ErrMissingCapability)
+	assert.Equal(t, []drone.Capability{drone.InsulatedBay}, d.Capabilities)
+
+	// unless the model has it
+	d.Model = drone.Heavyweight
+	assert.NoError(t, d.SetCapabilities(p, nil))
+	assert.Empty(t, d.Capabilities)
+}
+
+func TestAddMedicationsHandling(t *testing.T) {
+	insulin := drone.Medication{Name: "Insulin", Code: "IN_50", Weight: 50, Handling: []drone.HandlingClass{drone.ColdChain}}
+	solvent := drone.Medication{Name: "Solvent", Code: "SO_50", Weight: 50, Handling: []drone.HandlingClass{drone.Hazardous}, Incompatible: []string{"OX_50"}}
+	oxidizer := drone.Medication{Name: "Oxidizer", Code: "OX_50", Weight: 50}
+	aspirin := drone.Medication{Name: "Aspirin", Code: "AS_50", Weight: 50}
+
+	testCases := []struct {
+		name          string
+		drone         drone.Drone
+		loaded        []drone.Medication
+		medication    drone.Medication
+		expectedError error
+	}{
+		{
+			name:       "OK: without handling",
+			drone:      drone.Drone{Model: drone.Lightweight},
+			medication: aspirin,
+		},
+		{
+			name:       "OK: cold chain in the insulated bay of the model",
+			drone:      drone.Drone{Model: drone.Heavyweight},
+			loaded:     []drone.Medication{aspirin},
+			medication: insulin,
+		},
+		{
+			name:          "Err: cold chain without insulated bay",
+			drone:         drone.Drone{Model: drone.Lightweight},
+			medication:    insulin,
+			expectedError: drone.ErrMissingCapability,
+		},
+		{
+			name:       "OK: hazardous in a certified drone",
+			drone:      drone.Drone{Model: drone.Lightweight, Capabilities: []drone.Capability{drone.HazmatCertified}},
+			medication: solvent,
+		},
+		{
+			name:          "Err: hazardous in a drone not certified",
+			drone:         drone.Drone{Model: drone.Heavyweight},
+			medication:    solvent,
+			expectedError: drone.ErrMissingCapability,
+		},
+		{
+			name:          "Err: incompatible with a loaded medication",
+			drone:         drone.Drone{Model: drone.Lightweight, Capabilities: []drone.Capability{drone.HazmatCertified}},
+			loaded:        []drone.Medication{oxidizer},
+			medication:    solvent,
+			expectedError: drone.ErrIncompatibleMedication,
+		},
+		{
+			name:          "Err: a loaded medication is incompatible with it",
+			drone:         drone.Drone{Model: drone.Lightweight, Capabilities: []drone.Capability{drone.HazmatCertified}},
+			loaded:        []drone.Medication{solvent},
+			medication:    oxidizer,
+			expectedError: drone.ErrIncompatibleMedication,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := tc.drone
+			d.State, d.BatteryCapacity, d.WeightLimit = drone.Idle, 80, 500
+			for _, m := range tc.loaded {
+				require.NoError(t, d.AddMedications(insulatedHeavyweights(), m))
+			}
+
+			err := d.AddMedications(insulatedHeavyweights(), tc.medication)
+			if tc.expectedError != nil {
+				require.ErrorIs(t, err, tc.expectedError)
+				assert.Len(t, d.Medications, len(tc.loaded))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, d.Medications, len(tc.loaded)+1)
+		})
+	}
+}
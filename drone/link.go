@@ -0,0 +1,37 @@
+package drone
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+)
+
+// ErrInvalidLinkKey error occurs when a Drone links without its link key.
+var ErrInvalidLinkKey = errors.New("invalid drone link key")
+
+// IssueLinkKey generates the key the Drone authenticates its link with,
+// replacing the previous one. Only its hash is kept, so the key is returned once.
+func (d *Drone) IssueLinkKey() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	key := hex.EncodeToString(b)
+	d.LinkKeyHash = hashLinkKey(key)
+	return key
+}
+
+// CheckLinkKey returns ErrInvalidLinkKey unless key is the link key of the
+// Drone. A Drone without a link key can't link.
+func (d *Drone) CheckLinkKey(key string) error {
+	if d.LinkKeyHash == "" || subtle.ConstantTimeCompare([]byte(hashLinkKey(key)), []byte(d.LinkKeyHash)) != 1 {
+		return ErrInvalidLinkKey
+	}
+
+	return nil
+}
+
+func hashLinkKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,23 @@
+package drone_test
+
+import (
+	"testing"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkKey(t *testing.T) {
+	d := drone.Drone{Serial: "12345"}
+	assert.ErrorIs(t, d.CheckLinkKey(""), drone.ErrInvalidLinkKey)
+
+	key := d.IssueLinkKey()
+	assert.NoError(t, d.CheckLinkKey(key))
+	assert.NotContains(t, d.LinkKeyHash, key)
+	assert.ErrorIs(t, d.CheckLinkKey("another"), drone.ErrInvalidLinkKey)
+
+	// a new key revokes the previous one
+	rotated := d.IssueLinkKey()
+	assert.NoError(t, d.CheckLinkKey(rotated))
+	assert.ErrorIs(t, d.CheckLinkKey(key), drone.ErrInvalidLinkKey)
+}
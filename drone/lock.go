@@ -0,0 +1,79 @@
+package drone
+
+import (
+	"sort"
+	"sync"
+)
+
+// Locks serializes the changes of each Drone: a change holds the lock of the
+// Drone from its read to its save, so two changes never overwrite each other.
+// NOTE: it only covers the changes of this process.
+type Locks struct {
+	mu    sync.Mutex
+	locks map[lockKey]*droneLock
+}
+
+// lockKey identifies a Drone inside the partition of its tenant.
+type lockKey struct {
+	tenantID string
+	serial   string
+}
+
+// droneLock is the lock of a Drone and the number of changes holding or waiting for it.
+type droneLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// NewLocks builds the Locks of the drones.
+func NewLocks() *Locks {
+	return &Locks{locks: make(map[lockKey]*droneLock)}
+}
+
+// Lock locks the Drone of the tenant and returns the function unlocking it.
+func (l *Locks) Lock(tenantID, serial string) (unlock func()) {
+	k := lockKey{tenantID: tenantID, serial: serial}
+	l.mu.Lock()
+	dl, ok := l.locks[k]
+	if !ok {
+		dl = new(droneLock)
+		l.locks[k] = dl
+	}
+	dl.refs++
+	l.mu.Unlock()
+
+	dl.mu.Lock()
+	return func() {
+		dl.mu.Unlock()
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		// NOTE: the lock is dropped once unused, so the map doesn't grow with every drone.
+		if dl.refs--; dl.refs == 0 {
+			delete(l.locks, k)
+		}
+	}
+}
+
+// LockAll locks the drones of the tenant, in the order of their serials so
+// two changes of the same drones never wait for each other, and returns the
+// function unlocking them.
+func (l *Locks) LockAll(tenantID string, serials ...string) (unlock func()) {
+	sorted := append([]string(nil), serials...)
+	sort.Strings(sorted)
+
+	unlocks := make([]func(), 0, len(sorted))
+	for i, serial := range sorted {
+		if i > 0 && serial == sorted[i-1] {
+			continue
+		}
+
+		unlocks = append(unlocks, l.Lock(tenantID, serial))
+	}
+
+	return func() {
+		for _, unlock := range unlocks {
+			unlock()
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package drone_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocks(t *testing.T) {
+	locks := drone.NewLocks()
+
+	// every change reads the counter and writes it back incremented, none is lost
+	var (
+		wg      sync.WaitGroup
+		counter int
+	)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := locks.Lock("hospital-a", "1")
+			defer unlock()
+
+			read := counter
+			counter = read + 1
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 100, counter)
+
+	// the lock of a drone doesn't wait for the others
+	unlock := locks.Lock("hospital-a", "1")
+	locks.Lock("hospital-a", "2")()
+	locks.Lock("hospital-b", "1")()
+	unlock()
+
+	// the drones locked in any order (or twice) don't wait for each other
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			locks.LockAll("hospital-a", "1", "2", "3")()
+		}()
+		go func() {
+			defer wg.Done()
+			locks.LockAll("hospital-a", "3", "1", "1")()
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,92 @@
+package drone
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrGrounded error occurs when a Drone grounded for maintenance is loaded or dispatched.
+var ErrGrounded = errors.New("drone grounded for maintenance")
+
+// Maintenance is the service record of a Drone, kept apart from its delivery State.
+type Maintenance struct {
+	// Grounded drones are out of service (never available) until returned to service.
+	Grounded   bool
+	Reason     string
+	GroundedAt time.Time
+	// Cycles and FlightTime count the completed deliveries and their flight time.
+	Cycles     uint32
+	FlightTime time.Duration
+	// CyclesSinceService and FlightTimeSinceService restart on each service.
+	CyclesSinceService     uint32
+	FlightTimeSinceService time.Duration
+	LastServiceAt          time.Time
+	// FlightStartedAt is when the current delivery took off (zero if there is none).
+	FlightStartedAt time.Time
+}
+
+// Ground takes the Drone out of service at now, the reason is kept for the
+// operators. Grounding a grounded Drone keeps the first reason.
+func (d *Drone) Ground(reason string, now time.Time) {
+	if d.Maintenance.Grounded {
+		return
+	}
+
+	d.Maintenance.Grounded, d.Maintenance.Reason, d.Maintenance.GroundedAt = true, reason, now
+	d.record(Event{Type: DroneGrounded, State: d.State})
+}
+
+// ReturnToService records a service of the Drone at now, restarting the
+// counters since the last service and returning it to service if grounded.
+func (d *Drone) ReturnToService(now time.Time) {
+	grounded := d.Maintenance.Grounded
+	d.Maintenance.Grounded, d.Maintenance.Reason, d.Maintenance.GroundedAt = false, "", time.Time{}
+	d.Maintenance.CyclesSinceService, d.Maintenance.FlightTimeSinceService = 0, 0
+	d.Maintenance.LastServiceAt = now
+	if grounded {
+		d.record(Event{Type: DroneReturnedToService, State: d.State})
+	}
+}
+
+// MaintenanceRules ground the drones due for maintenance.
+type MaintenanceRules struct {
+	// MaxCycles grounds a Drone after these completed deliveries since its last service (0 disables it).
+	MaxCycles uint32
+	// MaxFlightTime grounds a Drone after this flight time since its last service (0 disables it).
+	MaxFlightTime time.Duration
+}
+
+// Track advances the counters of the Drone with its StateChanged events not
+// committed yet, at now: a delivery takes off when Delivering and completes
+// when Delivered. The Drone is grounded once due for maintenance.
+// NOTE: it has to be called once per save, the events are committed on save.
+func (r MaintenanceRules) Track(d *Drone, now time.Time) {
+	for _, e := range d.Events() {
+		if e.Type != StateChanged {
+			continue
+		}
+
+		switch e.State {
+		case Delivering:
+			d.Maintenance.FlightStartedAt = e.OccurredAt
+		case Delivered:
+			var flight time.Duration
+			if start := d.Maintenance.FlightStartedAt; !start.IsZero() && e.OccurredAt.After(start) {
+				flight = e.OccurredAt.Sub(start)
+			}
+
+			d.Maintenance.Cycles++
+			d.Maintenance.CyclesSinceService++
+			d.Maintenance.FlightTime += flight
+			d.Maintenance.FlightTimeSinceService += flight
+			d.Maintenance.FlightStartedAt = time.Time{}
+		}
+	}
+
+	switch {
+	case r.MaxCycles > 0 && d.Maintenance.CyclesSinceService >= r.MaxCycles:
+		d.Ground("cycle limit reached", now)
+	case r.MaxFlightTime > 0 && d.Maintenance.FlightTimeSinceService >= r.MaxFlightTime:
+		d.Ground("flight time limit reached", now)
+	}
+}
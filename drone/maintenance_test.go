@@ -0,0 +1,85 @@
+package drone_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceRules(t *testing.T) {
+	now := time.Now().UTC()
+	deliver := func(r drone.MaintenanceRules, d *drone.Drone, flight time.Duration) {
+		d.ChangeState(drone.Delivering)
+		r.Track(d, now)
+		d.ClearEvents()
+		d.Maintenance.FlightStartedAt = d.Maintenance.FlightStartedAt.Add(-flight)
+		d.ChangeState(drone.Delivered)
+		r.Track(d, now)
+		d.ClearEvents()
+		d.ChangeState(drone.Idle)
+	}
+
+	testCases := []struct {
+		name       string
+		rules      drone.MaintenanceRules
+		deliveries int
+		grounded   bool
+		reason     string
+	}{
+		{name: "OK: no rules", deliveries: 3},
+		{name: "OK: under the cycle limit", rules: drone.MaintenanceRules{MaxCycles: 3}, deliveries: 2},
+		{name: "Grounded: cycle limit", rules: drone.MaintenanceRules{MaxCycles: 3}, deliveries: 3, grounded: true, reason: "cycle limit reached"},
+		{name: "Grounded: flight time limit", rules: drone.MaintenanceRules{MaxFlightTime: time.Hour}, deliveries: 2, grounded: true, reason: "flight time limit reached"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := drone.Drone{Model: drone.Lightweight, WeightLimit: 200, BatteryCapacity: 90, State: drone.Idle}
+			for i := 0; i < tc.deliveries; i++ {
+				deliver(tc.rules, &d, 30*time.Minute)
+			}
+
+			assert.Equal(t, uint32(tc.deliveries), d.Maintenance.Cycles)
+			assert.Equal(t, uint32(tc.deliveries), d.Maintenance.CyclesSinceService)
+			assert.InDelta(t, float64(tc.deliveries)*30, d.Maintenance.FlightTime.Minutes(), 1)
+			assert.True(t, d.Maintenance.FlightStartedAt.IsZero())
+			assert.Equal(t, tc.grounded, d.Maintenance.Grounded)
+			assert.Equal(t, tc.reason, d.Maintenance.Reason)
+			assert.Equal(t, !tc.grounded, d.IsAvailable(drone.DefaultPolicy()))
+		})
+	}
+}
+
+func TestDroneGround(t *testing.T) {
+	now := time.Now().UTC()
+	home, destination := drone.Coordinates{}, drone.Coordinates{Latitude: 0.01}
+	d := drone.Drone{Model: drone.Lightweight, WeightLimit: 200, BatteryCapacity: 90, State: drone.Idle, Home: &home, Destination: &destination}
+	d.Maintenance.Cycles, d.Maintenance.CyclesSinceService = 10, 10
+
+	d.Ground("propeller damage", now)
+	d.Ground("another reason", now.Add(time.Minute))
+	assert.Equal(t, "propeller damage", d.Maintenance.Reason)
+	assert.Equal(t, now, d.Maintenance.GroundedAt)
+	assert.False(t, d.IsAvailable(drone.DefaultPolicy()))
+	assert.ErrorIs(t, d.AddMedications(drone.DefaultPolicy(), drone.Medication{Weight: 50}), drone.ErrGrounded)
+	d.State, d.Medications = drone.Loaded, []drone.Medication{{Weight: 50}}
+	assert.ErrorIs(t, d.CanExecute(drone.DefaultPolicy(), drone.Dispatch), drone.ErrGrounded)
+
+	d.ReturnToService(now.Add(time.Hour))
+	assert.False(t, d.Maintenance.Grounded)
+	assert.Empty(t, d.Maintenance.Reason)
+	assert.Equal(t, uint32(10), d.Maintenance.Cycles)
+	assert.Zero(t, d.Maintenance.CyclesSinceService)
+	assert.Equal(t, now.Add(time.Hour), d.Maintenance.LastServiceAt)
+	assert.NoError(t, d.CanExecute(drone.DefaultPolicy(), drone.Dispatch))
+
+	var types []drone.EventType
+	for _, e := range d.Events() {
+		types = append(types, e.Type)
+	}
+	require.Len(t, types, 2)
+	assert.Equal(t, []drone.EventType{drone.DroneGrounded, drone.DroneReturnedToService}, types)
+}
@@ -1,7 +1,7 @@
 package drone
 
 import (
-	"errors"
+	"fmt"
 	"regexp"
 )
 
@@ -17,22 +17,54 @@ type Medication struct {
 	Weight uint32
 	Code   string
 	Image  string
+	// Handling are the special handlings the Medication needs (none if nil).
+	Handling []HandlingClass
+	// Incompatible are the codes of the medications it can't share a drone with.
+	Incompatible []string
 }
 
 // NewMedication builds a new instance of Medication.
-func NewMedication(name string, weight uint32, code string, image string) (Medication, error) {
-	if !nameValidation.MatchString(name) {
-		return Medication{}, errors.New("name doesn't match")
+// NOTE: Returns a *ValidationError with every invalid field.
+func NewMedication(name string, weight uint32, code string, image string, handling []HandlingClass, incompatible []string) (Medication, error) {
+	verr := new(ValidationError)
+	switch {
+	case name == "":
+		verr.Add("name", CodeRequired, "name is empty")
+	case !nameValidation.MatchString(name):
+		verr.Add("name", CodeInvalidFormat, "name only allows letters, numbers, '-' and '_'")
 	}
 
-	if !codeValidation.MatchString(code) {
-		return Medication{}, errors.New("code doesn't match")
+	switch {
+	case code == "":
+		verr.Add("code", CodeRequired, "code is empty")
+	case !codeValidation.MatchString(code):
+		verr.Add("code", CodeInvalidFormat, "code only allows upper case letters, numbers and '_'")
+	}
+
+	for _, class := range handling {
+		if !class.Valid() {
+			verr.Add("handling", CodeUnknown, fmt.Sprintf("unknown handling class %q", class))
+			break
+		}
+	}
+
+	for _, c := range incompatible {
+		if !codeValidation.MatchString(c) {
+			verr.Add("incompatible", CodeInvalidFormat, fmt.Sprintf("incompatible code %q only allows upper case letters, numbers and '_'", c))
+			break
+		}
+	}
+
+	if err := verr.Err(); err != nil {
+		return Medication{}, err
 	}
 
 	return Medication{
-		Name:   name,
-		Weight: weight,
-		Code:   code,
-		Image:  image,
+		Name:         name,
+		Weight:       weight,
+		Code:         code,
+		Image:        image,
+		Handling:     handling,
+		Incompatible: incompatible,
 	}, nil
 }
@@ -12,11 +12,15 @@ func TestNewMedication(t *testing.T) {
 	testCases := []struct {
 		name        string
 		expectedErr bool
+		// invalidFields are the fields of the ValidationError.
+		invalidFields []string
 
 		medicationName   string
 		medicationWeight uint32
 		medicationCode   string
 		medicationImage  string
+		// medicationHandling are the handling classes of the medication.
+		medicationHandling []drone.HandlingClass
 
 		expected drone.Medication
 	}{
@@ -49,6 +53,7 @@ func TestNewMedication(t *testing.T) {
 		{
 			name:             "Err: 'name doesn't match'",
 			expectedErr:      true,
+			invalidFields:    []string{"name"},
 			medicationName:   "0M3PA)*7",
 			medicationWeight: 100,
 			medicationCode:   "OM_101",
@@ -57,18 +62,55 @@ func TestNewMedication(t *testing.T) {
 		{
 			name:             "Err: 'code doesn't match'",
 			expectedErr:      true,
+			invalidFields:    []string{"code"},
 			medicationName:   "Omeprazol",
 			medicationWeight: 100,
 			medicationCode:   "om_101", // code in lowercase
 			medicationImage:  "/path/to/the_image",
 		},
+		{
+			name:               "OK: Cold chain",
+			medicationName:     "Insulin",
+			medicationWeight:   50,
+			medicationCode:     "IN_50",
+			medicationHandling: []drone.HandlingClass{drone.ColdChain},
+			expected: drone.Medication{
+				Name:     "Insulin",
+				Weight:   50,
+				Code:     "IN_50",
+				Handling: []drone.HandlingClass{drone.ColdChain},
+			},
+		},
+		{
+			name:               "Err: 'unknown handling class'",
+			expectedErr:        true,
+			invalidFields:      []string{"handling"},
+			medicationName:     "Insulin",
+			medicationWeight:   50,
+			medicationCode:     "IN_50",
+			medicationHandling: []drone.HandlingClass{"frozen"},
+		},
+		{
+			name:             "Err: every invalid field",
+			expectedErr:      true,
+			invalidFields:    []string{"name", "code"},
+			medicationName:   "0M3PA)*7",
+			medicationWeight: 100,
+			medicationImage:  "/path/to/the_image",
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			newDrone, err := drone.NewMedication(tc.medicationName, tc.medicationWeight, tc.medicationCode, tc.medicationImage)
+			newDrone, err := drone.NewMedication(tc.medicationName, tc.medicationWeight, tc.medicationCode, tc.medicationImage, tc.medicationHandling, nil)
 			if tc.expectedErr {
-				require.Error(t, err)
+				var verr *drone.ValidationError
+				require.ErrorAs(t, err, &verr)
+				var fields []string
+				for _, f := range verr.Fields {
+					fields = append(fields, f.Field)
+				}
+				assert.Equal(t, tc.invalidFields, fields)
 				return
 			}
 
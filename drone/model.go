@@ -9,3 +9,18 @@ const (
 	Cruiserweight
 	Heavyweight
 )
+
+func (m Model) String() string {
+	switch m {
+	case Lightweight:
+		return "lightweight"
+	case Middleweight:
+		return "middleweight"
+	case Cruiserweight:
+		return "cruiserweight"
+	case Heavyweight:
+		return "heavyweight"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,122 @@
+package drone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Outbox exposes the domain events committed by Storage.SaveDrone that are
+// waiting to be dispatched.
+type Outbox interface {
+	// PendingEvents returns up to limit committed events not dispatched yet, oldest first.
+	PendingEvents(ctx context.Context, limit int) ([]Event, error)
+	// MarkDispatched removes the events from the pending list.
+	MarkDispatched(ctx context.Context, ids ...string) error
+}
+
+// EventHandler reacts to a dispatched domain event.
+// NOTE: events are delivered at least once, so handlers must be idempotent.
+type EventHandler func(ctx context.Context, e Event) error
+
+// defaultDispatchBatch is the amount of events fetched from the Outbox on each pass.
+const defaultDispatchBatch = 100
+
+// Dispatcher delivers the events of the Outbox to in-process subscribers.
+type Dispatcher struct {
+	outbox Outbox
+	// dispatchMu avoids concurrent passes delivering the same events twice.
+	dispatchMu sync.Mutex
+
+	mu           sync.RWMutex
+	handlersByTy map[EventType][]EventHandler
+	allHandlers  []EventHandler
+}
+
+// NewDispatcher builds a Dispatcher reading from the given Outbox.
+func NewDispatcher(outbox Outbox) *Dispatcher {
+	return &Dispatcher{
+		outbox:       outbox,
+		handlersByTy: make(map[EventType][]EventHandler),
+	}
+}
+
+// Subscribe registers a handler for the events of the given type.
+func (d *Dispatcher) Subscribe(t EventType, h EventHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlersByTy[t] = append(d.handlersByTy[t], h)
+}
+
+// SubscribeAll registers a handler for every event.
+func (d *Dispatcher) SubscribeAll(h EventHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.allHandlers = append(d.allHandlers, h)
+}
+
+// Dispatch delivers the pending events to the subscribers. An event is marked as
+// dispatched only if every subscriber handled it, otherwise it's retried on the next pass.
+func (d *Dispatcher) Dispatch(ctx context.Context) error {
+	d.dispatchMu.Lock()
+	defer d.dispatchMu.Unlock()
+	events, err := d.outbox.PendingEvents(ctx, defaultDispatchBatch)
+	if err != nil {
+		return fmt.Errorf("fetch pending events: %w", err)
+	}
+
+	var (
+		dispatched []string
+		errs       []error
+	)
+	for _, e := range events {
+		if err := d.deliver(ctx, e); err != nil {
+			errs = append(errs, fmt.Errorf("deliver event %s: %w", e.ID, err))
+			continue
+		}
+
+		dispatched = append(dispatched, e.ID)
+	}
+
+	if len(dispatched) > 0 {
+		if err := d.outbox.MarkDispatched(ctx, dispatched...); err != nil {
+			errs = append(errs, fmt.Errorf("mark events as dispatched: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Run dispatches the pending events every interval until ctx is done.
+// NOTE: dispatch errors are passed to onErr (if not nil).
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration, onErr func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := d.Dispatch(ctx); err != nil && onErr != nil {
+				onErr(err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, e Event) error {
+	d.mu.RLock()
+	handlers := append(append([]EventHandler(nil), d.handlersByTy[e.Type]...), d.allHandlers...)
+	d.mu.RUnlock()
+
+	var errs []error
+	for _, h := range handlers {
+		if err := h(ctx, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
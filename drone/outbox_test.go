@@ -0,0 +1,58 @@
+package drone_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatcher(t *testing.T) {
+	ctx := context.Background()
+	st := storage.NewInMemory()
+	d, err := drone.NewDrone(drone.DefaultPolicy(), "hospital-a", "1", drone.Lightweight, 300, 80)
+	require.NoError(t, err)
+	d.ChangeState(drone.Loading)
+	require.NoError(t, st.SaveDrone(ctx, d))
+
+	var (
+		registered []drone.Event
+		all        []drone.Event
+		failures   = 1
+	)
+	dispatcher := drone.NewDispatcher(st)
+	dispatcher.Subscribe(drone.DroneRegistered, func(_ context.Context, e drone.Event) error {
+		registered = append(registered, e)
+		return nil
+	})
+	dispatcher.SubscribeAll(func(_ context.Context, e drone.Event) error {
+		if e.Type == drone.StateChanged && failures > 0 {
+			failures--
+			return errors.New("temporary failure")
+		}
+
+		all = append(all, e)
+		return nil
+	})
+
+	// the failed event stays in the outbox
+	require.Error(t, dispatcher.Dispatch(ctx))
+	require.Len(t, registered, 1)
+	require.Len(t, all, 1)
+	pending, err := st.PendingEvents(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, drone.StateChanged, pending[0].Type)
+
+	// and it's delivered again on the next pass
+	require.NoError(t, dispatcher.Dispatch(ctx))
+	require.Len(t, all, 2)
+	assert.Equal(t, drone.StateChanged, all[1].Type)
+	pending, err = st.PendingEvents(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
@@ -0,0 +1,226 @@
+package drone
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// PlannedLoad is the part of an Order planned for a drone.
+type PlannedLoad struct {
+	// Drone is the drone before the load.
+	Drone       Drone
+	Medications []Medication
+}
+
+// Weight returns the total weight of the medications of the PlannedLoad.
+func (l PlannedLoad) Weight() uint32 {
+	var w uint32
+	for _, m := range l.Medications {
+		w += m.Weight
+	}
+	return w
+}
+
+// Plan splits an Order across drones. The medications no drone can carry are Unassigned.
+type Plan struct {
+	Loads      []PlannedLoad
+	Unassigned []Medication
+}
+
+// Complete returns if every medication of the Order has a drone.
+func (p Plan) Complete() bool {
+	return len(p.Unassigned) == 0
+}
+
+// PlanOrder splits the medications of the Order across the candidates with a
+// first-fit decreasing heuristic: the heaviest medications go first into the
+// first used drone with room, or else into the unused drone with the most free
+// weight (then the most battery), so the Order uses as few drones as possible.
+// A drone only takes the medications it can carry (see CanCarry) and, with a
+// Destination, the ones it has range for (each drone is routed once).
+func PlanOrder(candidates []Drone, o Order) Plan {
+	drones := append([]Drone(nil), candidates...)
+	sort.SliceStable(drones, func(i, j int) bool {
+		if fi, fj := freeWeight(drones[i]), freeWeight(drones[j]); fi != fj {
+			return fi > fj
+		}
+
+		if drones[i].BatteryCapacity != drones[j].BatteryCapacity {
+			return drones[i].BatteryCapacity > drones[j].BatteryCapacity
+		}
+
+		return drones[i].Serial < drones[j].Serial
+	})
+
+	o = o.routed(drones)
+	meds := append([]Medication(nil), o.Medications...)
+	sort.SliceStable(meds, func(i, j int) bool { return meds[i].Weight > meds[j].Weight })
+
+	var (
+		plan Plan
+		free []uint32 // free weight of each load of the plan
+		used = make([]bool, len(drones))
+	)
+	for _, m := range meds {
+		placed := false
+		for i, l := range plan.Loads {
+			// NOTE: the full slice expression keeps append from writing in the load.
+			if free[i] >= m.Weight && o.fits(l.Drone, append(l.Medications[:len(l.Medications):len(l.Medications)], m)) {
+				plan.Loads[i].Medications = append(plan.Loads[i].Medications, m)
+				free[i] -= m.Weight
+				placed = true
+				break
+			}
+		}
+
+		for i := 0; !placed && i < len(drones); i++ {
+			if !used[i] && freeWeight(drones[i]) >= m.Weight && o.fits(drones[i], []Medication{m}) {
+				used[i] = true
+				plan.Loads = append(plan.Loads, PlannedLoad{Drone: drones[i], Medications: []Medication{m}})
+				free = append(free, freeWeight(drones[i])-m.Weight)
+				placed = true
+			}
+		}
+
+		if !placed {
+			plan.Unassigned = append(plan.Unassigned, m)
+		}
+	}
+
+	return plan
+}
+
+// freeWeight returns the weight the drone can still carry.
+func freeWeight(d Drone) uint32 {
+	if w := d.MedicationWeight(); w < d.WeightLimit {
+		return d.WeightLimit - w
+	}
+
+	return 0
+}
+
+// Plan returns the dry-run Plan of the Order over the drones that can be
+// assigned now, without loading them.
+func (a *Assigner) Plan(ctx context.Context, o Order) (Plan, error) {
+	if len(o.Medications) == 0 {
+		return Plan{}, ErrEmptyOrder
+	}
+
+	o, err := a.airspace(ctx, o)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	o, candidates, err := a.candidates(ctx, o)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	return PlanOrder(candidates, o), nil
+}
+
+// RevertedLoad is a load of a split Order saved and then reverted.
+type RevertedLoad struct {
+	// Before, Loaded and Reverted are the drone before the load, once loaded and once reverted.
+	Before, Loaded, Reverted Drone
+}
+
+// RollbackError is returned by AssignPlan when a load fails to be saved after
+// others were, listing the loads it reverted.
+type RollbackError struct {
+	Err      error
+	Reverted []RevertedLoad
+}
+
+func (e *RollbackError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RollbackError) Unwrap() error {
+	return e.Err
+}
+
+// AssignPlan plans the Order and loads every drone of the Plan, returning the
+// Plan and the loaded drones in the order of its loads. An incomplete Plan
+// fails with ErrNoDroneAvailable and loads nothing.
+// NOTE: the Storage has no transactions, so if a save fails the loads already
+// saved are reverted, committing a MedicationUnloaded event for each of their
+// MedicationLoaded ones, and the failure is a *RollbackError.
+func (a *Assigner) AssignPlan(ctx context.Context, o Order) (Plan, []Drone, error) {
+	if len(o.Medications) == 0 {
+		return Plan{}, nil, ErrEmptyOrder
+	}
+
+	o, err := a.airspace(ctx, o)
+	if err != nil {
+		return Plan{}, nil, err
+	}
+
+	unlockOrders := a.orders.Lock(o.TenantID, "")
+	defer unlockOrders()
+
+	o, candidates, err := a.candidates(ctx, o)
+	if err != nil {
+		return Plan{}, nil, err
+	}
+
+	plan := PlanOrder(candidates, o)
+	if !plan.Complete() {
+		return plan, nil, fmt.Errorf("%w: %d medications without drone", ErrNoDroneAvailable, len(plan.Unassigned))
+	}
+
+	serials := make([]string, len(plan.Loads))
+	for i, l := range plan.Loads {
+		serials[i] = l.Drone.Serial
+	}
+
+	unlock := a.locks.LockAll(o.TenantID, serials...)
+	defer unlock()
+
+	loaded := make([]Drone, len(plan.Loads))
+	for i, l := range plan.Loads {
+		current, err := a.reread(ctx, o, l.Drone, l.Medications)
+		if err != nil {
+			return plan, nil, err
+		}
+
+		plan.Loads[i].Drone = current
+		d := current
+		d.Medications = append([]Medication(nil), current.Medications...)
+		if err := o.load(&d, l.Medications); err != nil {
+			return plan, nil, err
+		}
+		loaded[i] = d
+	}
+
+	for i, d := range loaded {
+		if err := a.storage.SaveDrone(ctx, d); err != nil {
+			rollback := &RollbackError{Err: fmt.Errorf("save drone %s: %w", d.Serial, err)}
+			for j, l := range plan.Loads[:i] {
+				reverted := revert(l.Drone, l.Medications)
+				// NOTE: best effort, the original error is the relevant one.
+				if err := a.storage.SaveDrone(ctx, reverted); err == nil {
+					rollback.Reverted = append(rollback.Reverted, RevertedLoad{Before: l.Drone, Loaded: loaded[j], Reverted: reverted})
+				}
+			}
+
+			return plan, nil, rollback
+		}
+	}
+
+	return plan, loaded, nil
+}
+
+// revert returns the drone as before the load of the medications, with a
+// MedicationUnloaded event for each of them.
+func revert(before Drone, meds []Medication) Drone {
+	d := before
+	d.events = nil
+	for _, m := range meds {
+		m := m
+		d.record(Event{Type: MedicationUnloaded, Medication: &m, State: d.State})
+	}
+
+	return d
+}
@@ -0,0 +1,264 @@
+package drone_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingSaveStorage fails to save the drone with the serial.
+type failingSaveStorage struct {
+	*storage.InMemory
+	serial string
+}
+
+func (s failingSaveStorage) SaveDrone(ctx context.Context, d drone.Drone) error {
+	if d.Serial == s.serial {
+		return errors.New("disk full")
+	}
+
+	return s.InMemory.SaveDrone(ctx, d)
+}
+
+// plannedSerials returns the serial and the medication codes of each load of the plan.
+func plannedSerials(p drone.Plan) map[string][]string {
+	loads := make(map[string][]string)
+	for _, l := range p.Loads {
+		for _, m := range l.Medications {
+			loads[l.Drone.Serial] = append(loads[l.Drone.Serial], m.Code)
+		}
+	}
+	return loads
+}
+
+func TestPlanOrder(t *testing.T) {
+	med := func(code string, weight uint32) drone.Medication {
+		return drone.Medication{Name: code, Code: code, Weight: weight}
+	}
+
+	testCases := []struct {
+		name       string
+		candidates []drone.Drone
+		order      drone.Order
+		expected   map[string][]string
+		unassigned []string
+	}{
+		{
+			name: "OK: heaviest first in the biggest drones",
+			candidates: []drone.Drone{
+				{Serial: "small", WeightLimit: 200, BatteryCapacity: 90},
+				{Serial: "big", WeightLimit: 500, BatteryCapacity: 90},
+				{Serial: "medium", WeightLimit: 400, BatteryCapacity: 90},
+			},
+			order: drone.Order{Medications: []drone.Medication{med("A", 100), med("B", 300), med("C", 250), med("D", 200)}},
+			expected: map[string][]string{
+				"big":    {"B", "D"},
+				"medium": {"C", "A"},
+			},
+		},
+		{
+			name: "OK: the loaded weight reduces the free weight",
+			candidates: []drone.Drone{
+				{Serial: "half-loaded", WeightLimit: 500, BatteryCapacity: 90, Medications: []drone.Medication{{Weight: 300}}},
+				{Serial: "empty", WeightLimit: 300, BatteryCapacity: 90},
+			},
+			order:    drone.Order{Medications: []drone.Medication{med("A", 250)}},
+			expected: map[string][]string{"empty": {"A"}},
+		},
+		{
+			name: "OK: ties go to the most battery",
+			candidates: []drone.Drone{
+				{Serial: "1", WeightLimit: 500, BatteryCapacity: 40},
+				{Serial: "2", WeightLimit: 500, BatteryCapacity: 80},
+			},
+			order:    drone.Order{Medications: []drone.Medication{med("A", 400)}},
+			expected: map[string][]string{"2": {"A"}},
+		},
+		{
+			name: "OK: the destination limits the load of each drone",
+			candidates: []drone.Drone{
+				// ~5 km from the destination: 30% empty round trip and 10% reserve, 2.5% more each 100g
+				{Serial: "1", Model: drone.Heavyweight, WeightLimit: 500, BatteryCapacity: 52, Home: &drone.Coordinates{}},
+				{Serial: "2", Model: drone.Heavyweight, WeightLimit: 500, BatteryCapacity: 52, Home: &drone.Coordinates{}},
+				{Serial: "no-home", Model: drone.Heavyweight, WeightLimit: 500, BatteryCapacity: 100},
+			},
+			order: drone.Order{
+				Destination: &drone.Coordinates{Latitude: 0.045},
+				Medications: []drone.Medication{med("A", 300), med("B", 200), med("C", 200)},
+			},
+			expected: map[string][]string{"1": {"A"}, "2": {"B", "C"}},
+		},
+		{
+			name: "Incomplete: medication heavier than any free weight",
+			candidates: []drone.Drone{
+				{Serial: "1", WeightLimit: 300, BatteryCapacity: 90},
+			},
+			order:      drone.Order{Medications: []drone.Medication{med("A", 400), med("B", 100)}},
+			expected:   map[string][]string{"1": {"B"}},
+			unassigned: []string{"A"},
+		},
+		{
+			name: "OK: the handling and the incompatibilities split the order",
+			candidates: []drone.Drone{
+				{Serial: "big", Model: drone.Lightweight, WeightLimit: 500, BatteryCapacity: 90},
+				{Serial: "insulated", Model: drone.Heavyweight, WeightLimit: 400, BatteryCapacity: 90, Capabilities: []drone.Capability{drone.InsulatedBay}},
+				{Serial: "small", Model: drone.Lightweight, WeightLimit: 200, BatteryCapacity: 90},
+			},
+			order: drone.Order{Medications: []drone.Medication{
+				{Name: "A", Code: "A", Weight: 200, Handling: []drone.HandlingClass{drone.ColdChain}},
+				{Name: "B", Code: "B", Weight: 150, Incompatible: []string{"C"}},
+				med("C", 100),
+			}},
+			expected: map[string][]string{"insulated": {"A", "B"}, "big": {"C"}},
+		},
+		{
+			name: "Incomplete: no drone has the capability",
+			candidates: []drone.Drone{
+				{Serial: "1", Model: drone.Lightweight, WeightLimit: 500, BatteryCapacity: 90},
+			},
+			order: drone.Order{Medications: []drone.Medication{
+				{Name: "A", Code: "A", Weight: 100, Handling: []drone.HandlingClass{drone.Hazardous}},
+				med("B", 100),
+			}},
+			expected:   map[string][]string{"1": {"B"}},
+			unassigned: []string{"A"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			plan := drone.PlanOrder(tc.candidates, tc.order)
+			assert.Equal(t, tc.expected, plannedSerials(plan))
+			var unassigned []string
+			for _, m := range plan.Unassigned {
+				unassigned = append(unassigned, m.Code)
+			}
+			assert.Equal(t, tc.unassigned, unassigned)
+			assert.Equal(t, len(tc.unassigned) == 0, plan.Complete())
+		})
+	}
+}
+
+func TestAssignerPlan(t *testing.T) {
+	ctx := context.Background()
+	st := storage.NewInMemory()
+	for _, d := range []drone.Drone{
+		{TenantID: "hospital-a", Serial: "1", Model: drone.Heavyweight, WeightLimit: 500, BatteryCapacity: 90, State: drone.Idle},
+		{TenantID: "hospital-a", Serial: "2", Model: drone.Heavyweight, WeightLimit: 500, BatteryCapacity: 80, State: drone.Idle},
+		{TenantID: "hospital-a", Serial: "low-battery", Model: drone.Heavyweight, WeightLimit: 500, BatteryCapacity: 30, State: drone.Idle},
+	} {
+		require.NoError(t, st.SaveDrone(ctx, d))
+	}
+
+	assigner := drone.NewAssigner(st, drone.NewLocks(), nil, drone.Policies{Default: drone.DefaultPolicy()}, nil, 10)
+	o := drone.Order{TenantID: "hospital-a", Medications: []drone.Medication{
+		{Name: "A", Code: "A", Weight: 400},
+		{Name: "B", Code: "B", Weight: 300},
+		{Name: "C", Code: "C", Weight: 100},
+	}}
+
+	// the dry run doesn't load the drones
+	plan, err := assigner.Plan(ctx, o)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"1": {"A", "C"}, "2": {"B"}}, plannedSerials(plan))
+	d, err := st.Drone(ctx, "hospital-a", "1")
+	require.NoError(t, err)
+	assert.Empty(t, d.Medications)
+
+	committed, loaded, err := assigner.AssignPlan(ctx, o)
+	require.NoError(t, err)
+	assert.Equal(t, plan, committed)
+	require.Len(t, loaded, 2)
+	for _, serial := range []string{"1", "2"} {
+		d, err := st.Drone(ctx, "hospital-a", serial)
+		require.NoError(t, err)
+		assert.NotEmpty(t, d.Medications)
+	}
+
+	// the remaining 200g can't carry 300g, nothing is loaded
+	_, _, err = assigner.AssignPlan(ctx, drone.Order{TenantID: "hospital-a", Medications: []drone.Medication{{Name: "D", Code: "D", Weight: 300}}})
+	require.ErrorIs(t, err, drone.ErrNoDroneAvailable)
+	d, err = st.Drone(ctx, "hospital-a", "2")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(300), d.MedicationWeight())
+
+	_, err = assigner.Plan(ctx, drone.Order{TenantID: "hospital-a"})
+	assert.ErrorIs(t, err, drone.ErrEmptyOrder)
+}
+
+func TestAssignerPlanAroundNoFlyZones(t *testing.T) {
+	ctx := context.Background()
+	st := storage.NewInMemory()
+	home := drone.Coordinates{Latitude: 0.005, Longitude: 0}
+	require.NoError(t, st.SaveDrone(ctx, drone.Drone{TenantID: "hospital-a", Serial: "1", Model: drone.Heavyweight, WeightLimit: 500, BatteryCapacity: 33, State: drone.Idle, Home: &home}))
+	zone := square(t, "airport", 0, 0.01, 0.02, 0.02)
+	require.NoError(t, st.SaveGeofence(ctx, zone))
+	assigner := drone.NewAssigner(st, drone.NewLocks(), st, drone.Policies{Default: drone.DefaultPolicy()}, nil, 0)
+
+	// the detour around the zone leaves range for the light medication only
+	destination := drone.Coordinates{Latitude: 0.005, Longitude: 0.03}
+	plan, err := assigner.Plan(ctx, drone.Order{TenantID: "hospital-a", Destination: &destination, Medications: []drone.Medication{
+		{Name: "A", Code: "A", Weight: 150},
+		{Name: "B", Code: "B", Weight: 50},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"1": {"B"}}, plannedSerials(plan))
+	require.Len(t, plan.Unassigned, 1)
+	assert.Equal(t, "A", plan.Unassigned[0].Code)
+
+	// the same load checked through the routed drone
+	d := plan.Loads[0].Drone
+	d.Medications = plan.Loads[0].Medications
+	require.NoError(t, d.SetDestination(destination))
+	require.NoError(t, d.Route([]drone.Geofence{zone}))
+	_, err = d.CheckRange(drone.DefaultPolicy())
+	assert.NoError(t, err)
+	d.Medications = plan.Unassigned
+	_, err = d.CheckRange(drone.DefaultPolicy())
+	assert.ErrorIs(t, err, drone.ErrOutOfRange)
+}
+
+func TestAssignerPlanRollback(t *testing.T) {
+	ctx := context.Background()
+	st := storage.NewInMemory()
+	for _, d := range []drone.Drone{
+		{TenantID: "hospital-a", Serial: "1", Model: drone.Heavyweight, WeightLimit: 500, BatteryCapacity: 90, State: drone.Idle},
+		{TenantID: "hospital-a", Serial: "2", Model: drone.Heavyweight, WeightLimit: 500, BatteryCapacity: 80, State: drone.Idle},
+	} {
+		require.NoError(t, st.SaveDrone(ctx, d))
+	}
+
+	assigner := drone.NewAssigner(failingSaveStorage{InMemory: st, serial: "2"}, drone.NewLocks(), nil, drone.Policies{Default: drone.DefaultPolicy()}, nil, 10)
+	_, loaded, err := assigner.AssignPlan(ctx, drone.Order{TenantID: "hospital-a", Medications: []drone.Medication{
+		{Name: "A", Code: "A", Weight: 400},
+		{Name: "B", Code: "B", Weight: 300},
+	}})
+	var rollback *drone.RollbackError
+	require.ErrorAs(t, err, &rollback)
+	assert.Nil(t, loaded)
+
+	// the load of the drone 1 is reverted
+	require.Len(t, rollback.Reverted, 1)
+	reverted := rollback.Reverted[0]
+	assert.Equal(t, "1", reverted.Before.Serial)
+	assert.Len(t, reverted.Loaded.Medications, 1)
+	assert.Empty(t, reverted.Reverted.Medications)
+	d, err := st.Drone(ctx, "hospital-a", "1")
+	require.NoError(t, err)
+	assert.Empty(t, d.Medications)
+
+	// each MedicationLoaded event is compensated by a MedicationUnloaded one
+	pending, err := st.PendingEvents(ctx, 10)
+	require.NoError(t, err)
+	var types []drone.EventType
+	for _, e := range pending {
+		types = append(types, e.Type)
+	}
+	assert.Equal(t, []drone.EventType{drone.MedicationLoaded, drone.MedicationUnloaded}, types)
+	assert.Equal(t, pending[0].Medication, pending[1].Medication)
+}
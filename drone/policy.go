@@ -0,0 +1,84 @@
+package drone
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultMaxWeight is the max weight limit in grams of the models without a Policy.MaxWeight.
+const DefaultMaxWeight uint32 = 500
+
+// Policy are the business rules a tenant applies to its drones.
+type Policy struct {
+	// MaxWeight is the max weight limit in grams a Drone of each Model can be
+	// registered with (DefaultMaxWeight for the missing models).
+	MaxWeight map[Model]uint32
+	// MinLoadBattery is the battery a Drone needs to be available and loaded.
+	MinLoadBattery uint8
+	// MinDispatchBattery is the battery a Drone needs to be dispatched.
+	MinDispatchBattery uint8
+	// Reserve is the battery a Drone must keep when back at Home after a delivery.
+	Reserve uint8
+	// ModelCapabilities are the Capabilities every Drone of a Model has, on
+	// top of the ones declared by the Drone.
+	ModelCapabilities map[Model][]Capability
+}
+
+// DefaultPolicy returns the Policy of the tenants without configuration.
+func DefaultPolicy() Policy {
+	return Policy{
+		MinLoadBattery:     25,
+		MinDispatchBattery: 25,
+		Reserve:            10,
+	}
+}
+
+// Validate returns an error if a battery level of the Policy isn't a
+// percentage, or a Capability of a Model isn't known.
+func (p Policy) Validate() error {
+	var errs []error
+	for m, w := range p.MaxWeight {
+		if w == 0 {
+			errs = append(errs, fmt.Errorf("max weight of %s need to be positive", m))
+		}
+	}
+
+	if p.MinLoadBattery > 100 || p.MinDispatchBattery > 100 || p.Reserve > 100 {
+		errs = append(errs, errors.New("battery levels need to be percentages"))
+	}
+
+	for m, caps := range p.ModelCapabilities {
+		for _, c := range caps {
+			if !c.Valid() {
+				errs = append(errs, fmt.Errorf("unknown capability %q of %s", c, m))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// MaxWeightOf returns the max weight limit in grams of the Model.
+func (p Policy) MaxWeightOf(m Model) uint32 {
+	if w, ok := p.MaxWeight[m]; ok {
+		return w
+	}
+
+	return DefaultMaxWeight
+}
+
+// Policies are the Policy of each tenant.
+type Policies struct {
+	// Default is the Policy of the tenants without their own.
+	Default Policy
+	Tenants map[string]Policy
+}
+
+// For returns the Policy of the tenant.
+func (p Policies) For(tenantID string) Policy {
+	if tp, ok := p.Tenants[tenantID]; ok {
+		return tp
+	}
+
+	return p.Default
+}
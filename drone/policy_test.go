@@ -0,0 +1,56 @@
+package drone_test
+
+import (
+	"testing"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicies(t *testing.T) {
+	strict := drone.DefaultPolicy()
+	strict.MaxWeight = map[drone.Model]uint32{drone.Lightweight: 200}
+	strict.MinLoadBattery, strict.MinDispatchBattery = 40, 60
+	policies := drone.Policies{Default: drone.DefaultPolicy(), Tenants: map[string]drone.Policy{"hospital-b": strict}}
+
+	assert.Equal(t, drone.DefaultPolicy(), policies.For("hospital-a"))
+	p := policies.For("hospital-b")
+	assert.Equal(t, uint32(200), p.MaxWeightOf(drone.Lightweight))
+	assert.Equal(t, drone.DefaultMaxWeight, p.MaxWeightOf(drone.Heavyweight))
+
+	_, err := drone.NewDrone(p, "hospital-b", "1", drone.Lightweight, 300, 90)
+	assert.Error(t, err)
+	_, err = drone.NewDrone(p, "hospital-b", "1", drone.Heavyweight, 300, 90)
+	assert.NoError(t, err)
+
+	// the battery to be available and to be loaded is the same
+	d := drone.Drone{Model: drone.Lightweight, WeightLimit: 200, BatteryCapacity: 40, State: drone.Idle}
+	assert.True(t, d.IsAvailable(p))
+	require.NoError(t, d.AddMedications(p, drone.Medication{Weight: 50}))
+	d.BatteryCapacity = 39
+	assert.False(t, d.IsAvailable(p))
+	assert.ErrorIs(t, d.AddMedications(p, drone.Medication{Weight: 50}), drone.ErrLowBattery)
+
+	home, destination := drone.Coordinates{}, drone.Coordinates{Latitude: 0.01}
+	d = drone.Drone{Model: drone.Lightweight, WeightLimit: 200, BatteryCapacity: 50, State: drone.Loaded,
+		Medications: []drone.Medication{{Weight: 50}}, Home: &home, Destination: &destination}
+	assert.NoError(t, d.CanExecute(drone.DefaultPolicy(), drone.Dispatch))
+	assert.ErrorIs(t, d.CanExecute(p, drone.Dispatch), drone.ErrLowBattery)
+}
+
+func TestPolicyValidate(t *testing.T) {
+	assert.NoError(t, drone.DefaultPolicy().Validate())
+
+	p := drone.DefaultPolicy()
+	p.MaxWeight = map[drone.Model]uint32{drone.Lightweight: 0}
+	assert.Error(t, p.Validate())
+
+	p = drone.DefaultPolicy()
+	p.ModelCapabilities = map[drone.Model][]drone.Capability{drone.Lightweight: {"teleporter"}}
+	assert.Error(t, p.Validate())
+
+	p = drone.DefaultPolicy()
+	p.Reserve = 101
+	assert.Error(t, p.Validate())
+}
@@ -0,0 +1,143 @@
+package drone
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNoHome error occurs when the range of a Drone without Home is checked.
+	ErrNoHome = errors.New("drone without home coordinates")
+	// ErrNoDestination error occurs when the range of a Drone without Destination is checked.
+	ErrNoDestination = errors.New("drone without destination")
+	// ErrOutOfRange error occurs when the battery of a Drone can't complete the round trip to its Destination.
+	ErrOutOfRange = errors.New("destination out of range")
+)
+
+// DrainRate is the battery percent a Model consumes per km.
+type DrainRate struct {
+	// PerKm is the drain of the Model flying empty.
+	PerKm float64
+	// PerKmPer100g is the extra drain for each 100g of payload.
+	PerKmPer100g float64
+}
+
+// Drain returns the battery percent consumed flying distance meters with the payload (in grams).
+func (r DrainRate) Drain(distance float64, payload uint32) float64 {
+	return distance / 1000 * (r.PerKm + r.PerKmPer100g*float64(payload)/100)
+}
+
+// DrainRates are the DrainRate of each Model, the heavier models drain faster.
+var DrainRates = map[Model]DrainRate{
+	Lightweight:   {PerKm: 1.5, PerKmPer100g: 0.5},
+	Middleweight:  {PerKm: 2, PerKmPer100g: 0.5},
+	Cruiserweight: {PerKm: 2.5, PerKmPer100g: 0.5},
+	Heavyweight:   {PerKm: 3, PerKmPer100g: 0.5},
+}
+
+// RangeCheck is the battery needed by a Drone for the round trip to its Destination.
+type RangeCheck struct {
+	// Distance is the one way distance in meters through the Waypoints.
+	Distance float64
+	// Required is the battery percent of the round trip: loaded to the Destination, empty back Home.
+	Required float64
+	Reserve  uint8
+	Battery  uint8
+}
+
+// Feasible returns if the battery covers the round trip keeping the reserve.
+func (c RangeCheck) Feasible() bool {
+	return c.Required+float64(c.Reserve) <= float64(c.Battery)
+}
+
+// CheckRange computes the battery needed for the round trip from Home to the
+// Destination through the Waypoints with the current Medications, returning
+// ErrOutOfRange when it isn't Feasible keeping the Reserve of the Policy.
+func (d *Drone) CheckRange(p Policy) (RangeCheck, error) {
+	if d.Home == nil {
+		return RangeCheck{}, ErrNoHome
+	}
+
+	if d.Destination == nil {
+		return RangeCheck{}, ErrNoDestination
+	}
+
+	return d.checkRange(p, d.flightDistance())
+}
+
+// checkRange computes the battery needed for the round trip of the one way
+// distance (in meters) with the current Medications, see CheckRange.
+func (d *Drone) checkRange(p Policy, distance float64) (RangeCheck, error) {
+	rate, err := d.drainRate()
+	if err != nil {
+		return RangeCheck{}, err
+	}
+
+	c := RangeCheck{
+		Distance: distance,
+		Required: rate.Drain(distance, d.MedicationWeight()) + rate.Drain(distance, 0),
+		Reserve:  p.Reserve,
+		Battery:  d.BatteryCapacity,
+	}
+	if !c.Feasible() {
+		return c, fmt.Errorf("%w: round trip of %.1f km needs %.0f%% of battery and %d%% of reserve, %d%% available",
+			ErrOutOfRange, 2*distance/1000, c.Required, c.Reserve, c.Battery)
+	}
+
+	return c, nil
+}
+
+// flightDistance returns the one way distance in meters from Home through the
+// Waypoints to the Destination (0 if any is unknown).
+func (d *Drone) flightDistance() float64 {
+	var distance float64
+	path := d.path()
+	for i := 1; i < len(path); i++ {
+		distance += Distance(path[i-1], path[i])
+	}
+
+	return distance
+}
+
+// CheckReturn computes the battery needed to fly back Home from the Position,
+// straight and with the current Medications, returning ErrOutOfRange when it
+// isn't Feasible keeping the Reserve of the Policy. A Drone without Position
+// is at Home.
+func (d *Drone) CheckReturn(p Policy) (RangeCheck, error) {
+	if d.Home == nil {
+		return RangeCheck{}, ErrNoHome
+	}
+
+	rate, err := d.drainRate()
+	if err != nil {
+		return RangeCheck{}, err
+	}
+
+	var distance float64
+	if d.Position != nil {
+		distance = Distance(*d.Position, *d.Home)
+	}
+
+	c := RangeCheck{
+		Distance: distance,
+		Required: rate.Drain(distance, d.MedicationWeight()),
+		Reserve:  p.Reserve,
+		Battery:  d.BatteryCapacity,
+	}
+	if !c.Feasible() {
+		return c, fmt.Errorf("%w: flight back home of %.1f km needs %.0f%% of battery and %d%% of reserve, %d%% available",
+			ErrOutOfRange, distance/1000, c.Required, c.Reserve, c.Battery)
+	}
+
+	return c, nil
+}
+
+// drainRate returns the DrainRate of the Model of the Drone.
+func (d *Drone) drainRate() (DrainRate, error) {
+	rate, ok := DrainRates[d.Model]
+	if !ok {
+		return DrainRate{}, fmt.Errorf("unknown drain rate of model %s", d.Model)
+	}
+
+	return rate, nil
+}
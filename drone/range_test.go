@@ -0,0 +1,57 @@
+package drone_test
+
+import (
+	"testing"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDroneCheckRange(t *testing.T) {
+	home := drone.Coordinates{Latitude: 0, Longitude: 0}
+	// ~5 km one way
+	destination := drone.Coordinates{Latitude: 0.045, Longitude: 0}
+
+	d := drone.Drone{Model: drone.Heavyweight, WeightLimit: 500, BatteryCapacity: 60, State: drone.Loaded}
+	require.NoError(t, d.SetHome(home))
+	require.NoError(t, d.SetDestination(destination))
+
+	// empty: 5 km * 3%/km * 2
+	c, err := d.CheckRange(drone.DefaultPolicy())
+	require.NoError(t, err)
+	assert.InDelta(t, 5004, c.Distance, 1)
+	assert.InDelta(t, 30, c.Required, 0.1)
+	assert.True(t, c.Feasible())
+
+	// 400g add 5 km * 2%/km to the outbound flight
+	d.Medications = []drone.Medication{{Weight: 400}}
+	c, err = d.CheckRange(drone.DefaultPolicy())
+	require.NoError(t, err)
+	assert.InDelta(t, 40, c.Required, 0.1)
+
+	// the reserve is kept over the required battery
+	d.BatteryCapacity = 49
+	c, err = d.CheckRange(drone.DefaultPolicy())
+	assert.ErrorIs(t, err, drone.ErrOutOfRange)
+	assert.False(t, c.Feasible())
+	assert.Equal(t, drone.DefaultPolicy().Reserve, c.Reserve)
+
+	// with the reserve of the policy
+	p := drone.DefaultPolicy()
+	p.Reserve = 5
+	c, err = d.CheckRange(p)
+	require.NoError(t, err)
+	assert.Equal(t, uint8(5), c.Reserve)
+}
+
+func TestDroneSetDestination(t *testing.T) {
+	d := drone.Drone{State: drone.Delivering}
+	assert.ErrorIs(t, d.SetDestination(drone.Coordinates{Latitude: 1}), drone.ErrInvalidDroneState)
+	assert.Nil(t, d.Destination)
+
+	d.State = drone.Loading
+	assert.ErrorIs(t, d.SetDestination(drone.Coordinates{Latitude: 100}), drone.ErrInvalidCoordinates)
+	require.NoError(t, d.SetDestination(drone.Coordinates{Latitude: 1}))
+	assert.Equal(t, &drone.Coordinates{Latitude: 1}, d.Destination)
+}
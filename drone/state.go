@@ -1,5 +1,13 @@
 package drone
 
+import (
+	"errors"
+	"slices"
+)
+
+// ErrInvalidTransition error occurs when a Drone moves to a State that can't follow its current one.
+var ErrInvalidTransition = errors.New("invalid drone state transition")
+
 // State defines the different state availables in a drone.
 type State int8
 
@@ -11,3 +19,43 @@ const (
 	Delivered
 	Returning
 )
+
+// Valid returns if the State is one of the defined states.
+func (s State) Valid() bool {
+	return s >= Idle && s <= Returning
+}
+
+// transitions are the States that can follow each State: the loading can be
+// canceled before the take-off, and an aborted delivery returns.
+var transitions = map[State][]State{
+	Idle:       {Loading},
+	Loading:    {Idle, Loaded},
+	Loaded:     {Idle, Loading, Delivering},
+	Delivering: {Delivered, Returning},
+	Delivered:  {Returning},
+	Returning:  {Idle},
+}
+
+// CanMoveTo returns if the State next can follow the State.
+func (s State) CanMoveTo(next State) bool {
+	return slices.Contains(transitions[s], next)
+}
+
+func (s State) String() string {
+	switch s {
+	case Idle:
+		return "idle"
+	case Loading:
+		return "loading"
+	case Loaded:
+		return "loaded"
+	case Delivering:
+		return "delivering"
+	case Delivered:
+		return "delivered"
+	case Returning:
+		return "returning"
+	default:
+		return "unknown"
+	}
+}
@@ -8,11 +8,15 @@ import (
 var ErrNotFound = errors.New("drone not found")
 
 type Storage interface {
-	// Drone returns a Drone entity by its serial number.
-	// NOTE: Returns NotFound error if serial doesn't match.
-	Drone(ctx context.Context, serial string) (Drone, error)
-	// Drone returns a list of all the Drone entities.
-	Drones(ctx context.Context) ([]Drone, error)
-	// SaveDrone persists the current state of a Drone entity.
+	// Drone returns a Drone entity of the tenant by its serial number.
+	// NOTE: Returns NotFound error if serial doesn't match inside the tenant.
+	Drone(ctx context.Context, tenantID, serial string) (Drone, error)
+	// Drones returns a list of all the Drone entities of the tenant.
+	Drones(ctx context.Context, tenantID string) ([]Drone, error)
+	// AllDrones returns a list of the Drone entities of every tenant.
+	// NOTE: Intended for background jobs, never expose it to a tenant.
+	AllDrones(ctx context.Context) ([]Drone, error)
+	// SaveDrone persists the current state of a Drone entity in the
+	// partition of its tenant.
 	SaveDrone(ctx context.Context, drone Drone) error
 }
@@ -0,0 +1,54 @@
+package drone
+
+import "strings"
+
+// Codes of the FieldErrors.
+const (
+	// CodeRequired is the code of a field that is empty.
+	CodeRequired = "required"
+	// CodeInvalidFormat is the code of a field that doesn't match its format.
+	CodeInvalidFormat = "invalid_format"
+	// CodeOutOfRange is the code of a field over or under its bounds.
+	CodeOutOfRange = "out_of_range"
+	// CodeUnknown is the code of a field that isn't one of its known values.
+	CodeUnknown = "unknown"
+)
+
+// MaxSerialLength is the max number of characters of the serial of a Drone.
+const MaxSerialLength = 100
+
+// FieldError is an invalid field of a ValidationError.
+type FieldError struct {
+	Field   string
+	Code    string
+	Message string
+}
+
+// ValidationError error occurs when a constructor gets invalid fields, it
+// lists every one of them instead of the first.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Add appends an invalid field.
+func (e *ValidationError) Add(field, code, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Err returns the ValidationError, nil without invalid fields.
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		messages = append(messages, f.Field+": "+f.Message)
+	}
+
+	return "validation failed: " + strings.Join(messages, "; ")
+}
@@ -0,0 +1,79 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/storage"
+)
+
+// ProbeTenant is the reserved tenant of the drone written by StorageRoundTrip.
+const ProbeTenant = storage.ProbeTenant
+
+// probeDrone is the drone saved and read back by StorageRoundTrip.
+// NOTE: built without NewDrone so no domain events are recorded.
+var probeDrone = drone.Drone{
+	TenantID:        ProbeTenant,
+	Serial:          "probe",
+	Model:           drone.Lightweight,
+	WeightLimit:     0,
+	BatteryCapacity: 100,
+	State:           drone.Idle,
+}
+
+// StorageRoundTrip checks the storage saving a probe drone and reading it back.
+func StorageRoundTrip(storage drone.Storage) CheckFunc {
+	return func(ctx context.Context) error {
+		if err := storage.SaveDrone(ctx, probeDrone); err != nil {
+			return fmt.Errorf("save probe: %w", err)
+		}
+
+		d, err := storage.Drone(ctx, ProbeTenant, probeDrone.Serial)
+		if err != nil {
+			return fmt.Errorf("read probe: %w", err)
+		}
+
+		if !reflect.DeepEqual(d, probeDrone) {
+			return errors.New("read probe: doesn't match the saved one")
+		}
+
+		return nil
+	}
+}
+
+// DirWritable checks that a file can be created in the directory.
+func DirWritable(dir string) CheckFunc {
+	return func(context.Context) error {
+		f, err := os.CreateTemp(dir, ".readyz-*")
+		if err != nil {
+			return err
+		}
+
+		name := f.Name()
+		if err := f.Close(); err != nil {
+			return err
+		}
+
+		return os.Remove(name)
+	}
+}
+
+// DiskFree checks that the filesystem of the directory has at least minFree bytes available.
+func DiskFree(dir string, minFree uint64) CheckFunc {
+	return func(context.Context) error {
+		free, err := diskFree(dir)
+		if err != nil {
+			return err
+		}
+
+		if free < minFree {
+			return fmt.Errorf("%d bytes free, need at least %d", free, minFree)
+		}
+
+		return nil
+	}
+}
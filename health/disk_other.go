@@ -0,0 +1,10 @@
+//go:build !(linux || darwin || freebsd)
+
+package health
+
+import "errors"
+
+// diskFree isn't supported in this OS.
+func diskFree(string) (uint64, error) {
+	return 0, errors.New("disk free space not supported")
+}
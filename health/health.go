@@ -0,0 +1,130 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrDuplicateCheck error occurs when a Check name is already in the Checker.
+var ErrDuplicateCheck = errors.New("duplicate check")
+
+// Status of a Check or of the whole Report.
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// Check is a probe of a dependency the server needs to serve requests.
+type Check interface {
+	Check(ctx context.Context) error
+}
+
+// CheckFunc adapts a function to the Check interface.
+type CheckFunc func(ctx context.Context) error
+
+func (f CheckFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Result is the outcome of a Check.
+type Result struct {
+	Name    string
+	Status  Status
+	Latency time.Duration
+	Error   string
+}
+
+// Report is the outcome of every Check of the Checker, it is down when any of them is down.
+type Report struct {
+	Status Status
+	Checks []Result
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker runs the registered checks concurrently, each one bounded by the timeout.
+type Checker struct {
+	timeout time.Duration
+
+	mu     sync.Mutex
+	checks []namedCheck
+}
+
+// New creates a Checker. A zero timeout doesn't bound the checks.
+func New(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Register adds a Check to the Checker.
+func (c *Checker) Register(name string, check Check) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, nc := range c.checks {
+		if nc.name == name {
+			return ErrDuplicateCheck
+		}
+	}
+
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+	return nil
+}
+
+// Run runs every Check and reports their results in the order they were registered.
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.Lock()
+	checks := append([]namedCheck(nil), c.checks...)
+	c.mu.Unlock()
+
+	report := Report{Status: StatusUp, Checks: make([]Result, len(checks))}
+	var wg sync.WaitGroup
+	for i, nc := range checks {
+		wg.Add(1)
+		go func(i int, nc namedCheck) {
+			defer wg.Done()
+			report.Checks[i] = c.run(ctx, nc)
+		}(i, nc)
+	}
+	wg.Wait()
+
+	for _, r := range report.Checks {
+		if r.Status == StatusDown {
+			report.Status = StatusDown
+		}
+	}
+
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, nc namedCheck) Result {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- nc.check.Check(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		// NOTE: a check ignoring the context is reported down without waiting for it.
+		err = ctx.Err()
+	}
+
+	r := Result{Name: nc.name, Status: StatusUp, Latency: time.Since(start)}
+	if err != nil {
+		r.Status, r.Error = StatusDown, err.Error()
+	}
+
+	return r
+}
@@ -0,0 +1,79 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker(t *testing.T) {
+	c := New(50 * time.Millisecond)
+	require.NoError(t, c.Register("up", CheckFunc(func(context.Context) error { return nil })))
+	require.NoError(t, c.Register("failing", CheckFunc(func(context.Context) error { return errors.New("failed") })))
+	require.NoError(t, c.Register("slow", CheckFunc(func(context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})))
+	require.ErrorIs(t, c.Register("up", CheckFunc(func(context.Context) error { return nil })), ErrDuplicateCheck)
+
+	start := time.Now()
+	report := c.Run(context.Background())
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, StatusDown, report.Status)
+	require.Len(t, report.Checks, 3)
+
+	assert.Equal(t, "up", report.Checks[0].Name)
+	assert.Equal(t, StatusUp, report.Checks[0].Status)
+	assert.Empty(t, report.Checks[0].Error)
+
+	assert.Equal(t, "failing", report.Checks[1].Name)
+	assert.Equal(t, StatusDown, report.Checks[1].Status)
+	assert.Equal(t, "failed", report.Checks[1].Error)
+
+	assert.Equal(t, "slow", report.Checks[2].Name)
+	assert.Equal(t, StatusDown, report.Checks[2].Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks[2].Error)
+	assert.GreaterOrEqual(t, report.Checks[2].Latency, 50*time.Millisecond)
+}
+
+func TestCheckerWithoutChecks(t *testing.T) {
+	report := New(time.Second).Run(context.Background())
+	assert.Equal(t, StatusUp, report.Status)
+	assert.Empty(t, report.Checks)
+}
+
+func TestStorageRoundTrip(t *testing.T) {
+	s := storage.NewInMemory()
+	check := StorageRoundTrip(s)
+	require.NoError(t, check(context.Background()))
+	require.NoError(t, check(context.Background()))
+
+	d, err := s.Drone(context.Background(), ProbeTenant, "probe")
+	require.NoError(t, err)
+	assert.Equal(t, drone.Idle, d.State)
+}
+
+func TestDirWritable(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, DirWritable(dir)(context.Background()))
+	entries, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	assert.Error(t, DirWritable(filepath.Join(dir, "missing"))(context.Background()))
+}
+
+func TestDiskFree(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, DiskFree(dir, 0)(context.Background()))
+	assert.ErrorContains(t, DiskFree(dir, math.MaxUint64)(context.Background()), "need at least")
+	assert.Error(t, DiskFree(filepath.Join(dir, "missing"), 0)(context.Background()))
+}
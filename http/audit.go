@@ -0,0 +1,33 @@
+package http
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/logging"
+)
+
+// recordAudit appends an AuditEvent describing the mutation of a drone made
+// by the authenticated Principal of ctx.
+// NOTE: the mutation is already saved, so a failed append is logged instead of
+// failing the request (the change is committed even without its AuditEvent).
+func recordAudit(ctx context.Context, store drone.AuditStore, action drone.AuditAction, before, after drone.Drone) {
+	p, _ := PrincipalFromContext(ctx)
+	e := drone.AuditEvent{
+		TenantID:  after.TenantID,
+		Serial:    after.Serial,
+		Actor:     p.Subject,
+		RequestID: middleware.GetReqID(ctx),
+		Action:    action,
+		At:        time.Now().UTC(),
+		Changes:   drone.Diff(before, after),
+	}
+
+	if err := store.AppendAuditEvent(ctx, e); err != nil {
+		logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, "record audit event failed",
+			slog.String("tenant_id", e.TenantID), slog.String("serial", e.Serial), slog.Any("action", e.Action), slog.String("error", err.Error()))
+	}
+}
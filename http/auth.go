@@ -0,0 +1,90 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// principalCtxKey is the context key for the authenticated Principal.
+type principalCtxKey struct{}
+
+// APIKey binds a secret key to the tenant it grants access to.
+type APIKey struct {
+	// Key is the secret sent by the client.
+	Key string
+	// Subject is the name of the key owner (used to identify the actor).
+	Subject string
+	// TenantID is the tenant the key grants access to.
+	TenantID string
+	// Admin keys can reach the endpoints of the operators (see RequireAdmin).
+	Admin bool
+}
+
+// Principal identifies the authenticated caller of a request.
+type Principal struct {
+	Subject  string
+	TenantID string
+	Admin    bool
+}
+
+// Authenticate returns a middleware that rejects the requests without a valid
+// API key and stores the authenticated Principal in the request context.
+// The key is read from the `X-API-Key` header or from a `Bearer` Authorization header.
+func Authenticate(keys []APIKey) func(http.Handler) http.Handler {
+	principalByKey := make(map[string]Principal, len(keys))
+	for _, k := range keys {
+		principalByKey[k.Key] = Principal{Subject: k.Subject, TenantID: k.TenantID, Admin: k.Admin}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, ok := principalByKey[apiKeyFromRequest(r)]
+			if !ok || p.TenantID == "" {
+				http.Error(w, "invalid api key", http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ContextWithPrincipal(r.Context(), p)))
+		})
+	}
+}
+
+// RequireAdmin is a middleware that rejects the requests of a Principal
+// without the admin role. It has to run after Authenticate.
+func RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p, _ := PrincipalFromContext(r.Context()); !p.Admin {
+			http.Error(w, "admin api key required", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ContextWithPrincipal returns a copy of ctx carrying the Principal.
+func ContextWithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalCtxKey{}, p)
+}
+
+// PrincipalFromContext returns the Principal stored in ctx, if any.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalCtxKey{}).(Principal)
+	return p, ok
+}
+
+// apiKeyFromRequest extracts the API key from the request headers.
+func apiKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+
+	const bearerPrefix = "Bearer "
+	auth := r.Header.Get("Authorization")
+	if len(auth) > len(bearerPrefix) && strings.EqualFold(auth[:len(bearerPrefix)], bearerPrefix) {
+		return auth[len(bearerPrefix):]
+	}
+
+	return ""
+}
@@ -0,0 +1,32 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// LimitBody rejects the request bodies larger than maxSize bytes while they are read.
+func LimitBody(maxSize int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// decodeJSON decodes the JSON body of the request into dst, returning the
+// status of the error: 413 when the body exceeds the LimitBody, 400 otherwise.
+func decodeJSON(r *http.Request, dst any) (int, error) {
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return http.StatusRequestEntityTooLarge, err
+		}
+
+		return http.StatusBadRequest, err
+	}
+
+	return http.StatusOK, nil
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// CertificateReloader serves a TLS certificate reloading it from disk when
+// the certificate or key files change, so it can be rotated without restarts.
+type CertificateReloader struct {
+	certFile, keyFile string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+// NewCertificateReloader loads the key pair, failing if it is invalid.
+func NewCertificateReloader(certFile, keyFile string) (*CertificateReloader, error) {
+	l := &CertificateReloader{certFile: certFile, keyFile: keyFile}
+	if err := l.Reload(); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Reload loads the key pair from disk.
+func (l *CertificateReloader) Reload() error {
+	modTime, err := l.lastModified()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(l.certFile, l.keyFile)
+	if err != nil {
+		return fmt.Errorf("load key pair: %w", err)
+	}
+
+	l.mu.Lock()
+	l.cert, l.modTime = &cert, modTime
+	l.mu.Unlock()
+
+	return nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate. If the files changed and
+// the new key pair is invalid, it keeps serving the previous certificate.
+func (l *CertificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if modTime, err := l.lastModified(); err == nil && l.changed(modTime) {
+		// NOTE: a half-written rotation is retried on the next handshake.
+		_ = l.Reload()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.cert, nil
+}
+
+// TLSConfig returns the server TLS configuration serving the certificate.
+func (l *CertificateReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: l.GetCertificate,
+	}
+}
+
+func (l *CertificateReloader) changed(modTime time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return !modTime.Equal(l.modTime)
+}
+
+func (l *CertificateReloader) lastModified() (time.Time, error) {
+	var last time.Time
+	for _, name := range []string{l.certFile, l.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(last) {
+			last = info.ModTime()
+		}
+	}
+
+	return last, nil
+}
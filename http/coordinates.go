@@ -0,0 +1,54 @@
+package http
+
+import (
+	"math"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// CoordinatesDTO struct is a position in decimal degrees.
+type CoordinatesDTO struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+func (c CoordinatesDTO) coordinates() drone.Coordinates {
+	return drone.Coordinates{Latitude: c.Latitude, Longitude: c.Longitude}
+}
+
+// newCoordinatesDTO omits the unknown coordinates in the responses.
+func newCoordinatesDTO(c *drone.Coordinates) *CoordinatesDTO {
+	if c == nil {
+		return nil
+	}
+
+	return &CoordinatesDTO{Latitude: c.Latitude, Longitude: c.Longitude}
+}
+
+// ETADTO struct is the estimated arrival of a drone at its destination.
+type ETADTO struct {
+	Arrival time.Time `json:"arrival"`
+	// RemainingSeconds is the flight time left when the ETA was computed.
+	RemainingSeconds int64 `json:"remaining_seconds"`
+	// RemainingDistance is the distance in meters left when the ETA was computed.
+	RemainingDistance float64   `json:"remaining_distance"`
+	ComputedAt        time.Time `json:"computed_at"`
+}
+
+// newETADTO estimates the ETA of the drone at now, omitting it for the drones
+// without a delivery in progress.
+// NOTE: the ETA isn't stored, it's always estimated from the current drone.
+func newETADTO(d drone.Drone, now time.Time) *ETADTO {
+	e, err := drone.EstimateETA(d, now)
+	if err != nil {
+		return nil
+	}
+
+	return &ETADTO{
+		Arrival:           e.Arrival,
+		RemainingSeconds:  int64(e.Remaining().Round(time.Second) / time.Second),
+		RemainingDistance: math.Round(e.Distance),
+		ComputedAt:        e.ComputedAt,
+	}
+}
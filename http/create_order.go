@@ -0,0 +1,139 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// OrderMedicationDTO struct is a medication of the manifest of POST /orders.
+type OrderMedicationDTO struct {
+	Name   string `json:"name"`
+	Weight uint32 `json:"weight"`
+	Code   string `json:"code"`
+	// Handling are the special handlings of the medication (optional), see drone.HandlingClass.
+	Handling []drone.HandlingClass `json:"handling,omitempty"`
+	// Incompatible are the codes of the medications it can't share a drone with (optional).
+	Incompatible []string `json:"incompatible,omitempty"`
+}
+
+// CreateOrderDTO struct is the value passed in the body of POST /orders.
+type CreateOrderDTO struct {
+	// Model restricts the assignment to the drones of a model (optional).
+	Model       drone.Model          `json:"model,omitempty"`
+	Medications []OrderMedicationDTO `json:"medications"`
+	// Destination restricts the assignment to the drones with range for the round trip (optional).
+	Destination *CoordinatesDTO `json:"destination,omitempty"`
+}
+
+// OrderAssignmentDTO struct is used in the response of POST /orders
+type OrderAssignmentDTO struct {
+	Serial          string          `json:"serial"`
+	Model           drone.Model     `json:"model"`
+	WeightLimit     uint32          `json:"weight_limit"`
+	BatteryCapacity uint8           `json:"battery_capacity"`
+	ConsumedWeight  uint32          `json:"consumed_weight"`
+	State           drone.State     `json:"state"`
+	Medications     []MedicationDTO `json:"medications"`
+	Destination     *CoordinatesDTO `json:"destination,omitempty"`
+	ETA             *ETADTO         `json:"eta,omitempty"`
+}
+
+// CreateOrder loads the medication manifest in the drone selected by the assignment strategy.
+func (h *OrderController) CreateOrder(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "OrderController.CreateOrder")
+	defer span.End()
+
+	o, status, err := h.orderFromRequest(r)
+	if err != nil {
+		fail(w, r, err, status)
+		return
+	}
+
+	before, d, err := h.assigner.Assign(r.Context(), o)
+	if err != nil {
+		fail(w, r, err, assignmentErrorStatus(err))
+		return
+	}
+
+	recordAudit(r.Context(), h.auditStore, drone.AuditLoad, before, d)
+
+	requestLogger(r).Info("order assigned", slog.String("serial", d.Serial), slog.Int("medications", len(o.Medications)), slog.Uint64("weight", uint64(o.Weight())))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(newOrderAssignmentDTO(d))
+}
+
+func newOrderAssignmentDTO(d drone.Drone) OrderAssignmentDTO {
+	return OrderAssignmentDTO{
+		Serial:          d.Serial,
+		Model:           d.Model,
+		WeightLimit:     d.WeightLimit,
+		BatteryCapacity: d.BatteryCapacity,
+		ConsumedWeight:  d.MedicationWeight(),
+		State:           d.State,
+		Medications:     newMedicationDTOs(d.Medications),
+		Destination:     newCoordinatesDTO(d.Destination),
+		ETA:             newETADTO(d, time.Now().UTC()),
+	}
+}
+
+// orderFromRequest decodes the Order of the body, returning the status of the error.
+func (h *OrderController) orderFromRequest(r *http.Request) (drone.Order, int, error) {
+	dto := new(CreateOrderDTO)
+	if status, err := decodeJSON(r, dto); err != nil {
+		return drone.Order{}, status, err
+	}
+
+	o := drone.Order{TenantID: tenantFromRequest(r), Model: dto.Model}
+	// the invalid fields of every medication, keyed by its index
+	verr := new(drone.ValidationError)
+	for i, m := range dto.Medications {
+		med, err := drone.NewMedication(m.Name, m.Weight, m.Code, "", m.Handling, m.Incompatible)
+		var medErr *drone.ValidationError
+		if errors.As(err, &medErr) {
+			for _, f := range medErr.Fields {
+				verr.Add(fmt.Sprintf("medications[%d].%s", i, f.Field), f.Code, f.Message)
+			}
+			continue
+		}
+
+		if err != nil {
+			return drone.Order{}, http.StatusBadRequest, err
+		}
+
+		o.Medications = append(o.Medications, med)
+	}
+
+	if err := verr.Err(); err != nil {
+		return drone.Order{}, http.StatusUnprocessableEntity, err
+	}
+
+	if dto.Destination != nil {
+		c := dto.Destination.coordinates()
+		if err := c.Validate(); err != nil {
+			return drone.Order{}, http.StatusBadRequest, err
+		}
+
+		o.Destination = &c
+	}
+
+	return o, http.StatusOK, nil
+}
+
+// assignmentErrorStatus returns the status of an error of the drone.Assigner.
+func assignmentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, drone.ErrEmptyOrder):
+		return http.StatusBadRequest
+	case errors.Is(err, drone.ErrNoDroneAvailable):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
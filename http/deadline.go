@@ -0,0 +1,15 @@
+package http
+
+import (
+	"net/http"
+	"time"
+)
+
+// clearDeadlines removes the read and write timeouts of the server from a
+// long-lived request (event streams and WebSocket links).
+func clearDeadlines(w http.ResponseWriter) {
+	rc := http.NewResponseController(w)
+	// NOTE: ErrNotSupported only happens with writers not unwrapping to the server one.
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+}
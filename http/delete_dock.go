@@ -0,0 +1,41 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// DeleteDock removes a charging dock of the tenant without drones, DELETE /docks/{id}.
+func (h *DockController) DeleteDock(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "DockController.DeleteDock")
+	defer span.End()
+
+	tenantID, id := tenantFromRequest(r), h.dockIDFromRequest(r)
+	occupancy, err := h.manager.Occupancy(r.Context(), tenantID)
+	if err != nil {
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	if n := len(occupancy[id]); n > 0 {
+		fail(w, r, fmt.Errorf("%w: %d drones docked", drone.ErrDockOccupied, n), http.StatusConflict)
+		return
+	}
+
+	if err := h.store.DeleteDock(r.Context(), tenantID, id); err != nil {
+		if errors.Is(err, drone.ErrDockNotFound) {
+			fail(w, r, err, http.StatusNotFound)
+			return
+		}
+
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	requestLogger(r).Info("dock deleted", slog.String("dock_id", id))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,29 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// DeleteGeofence removes a no-fly zone of the tenant, DELETE /geofences/{id}.
+func (h *GeofenceController) DeleteGeofence(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "GeofenceController.DeleteGeofence")
+	defer span.End()
+
+	id := h.geofenceIDFromRequest(r)
+	if err := h.store.DeleteGeofence(r.Context(), tenantFromRequest(r), id); err != nil {
+		if errors.Is(err, drone.ErrGeofenceNotFound) {
+			fail(w, r, err, http.StatusNotFound)
+			return
+		}
+
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	requestLogger(r).Info("geofence deleted", slog.String("geofence_id", id))
+	w.WriteHeader(http.StatusNoContent)
+}
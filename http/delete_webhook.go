@@ -0,0 +1,22 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/hsequeda/drone/webhook"
+)
+
+func (h *WebhookController) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if err := h.store.DeleteSubscription(r.Context(), tenantFromRequest(r), h.webhookIDFromRequest(r)); err != nil {
+		if errors.Is(err, webhook.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
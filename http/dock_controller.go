@@ -0,0 +1,23 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hsequeda/drone/drone"
+)
+
+// DockController manages the charging docks of the tenant.
+type DockController struct {
+	store   drone.DockStore
+	manager *drone.DockManager
+}
+
+func NewDockController(store drone.DockStore, manager *drone.DockManager) *DockController {
+	return &DockController{store: store, manager: manager}
+}
+
+// dockIDFromRequest extracts the dock ID from the path parameters.
+func (h *DockController) dockIDFromRequest(r *http.Request) string {
+	return chi.URLParam(r, "id")
+}
@@ -0,0 +1,337 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+	"github.com/hsequeda/drone/drone"
+)
+
+const (
+	// linkWriteWait is the time allowed to write a message to a drone.
+	linkWriteWait = 10 * time.Second
+	// linkPongWait is the time allowed to read the next pong from a drone.
+	linkPongWait = 60 * time.Second
+	// linkPingPeriod is the interval between the pings sent to a drone (must be less than linkPongWait).
+	linkPingPeriod = (linkPongWait * 9) / 10
+	// linkSendBuffer is the amount of messages queued per drone connection.
+	linkSendBuffer = 16
+)
+
+// Types of the LinkMessageDTO.
+const (
+	LinkTelemetry = "telemetry"
+	LinkCommand   = "command"
+	LinkAck       = "ack"
+	LinkError     = "error"
+)
+
+// DroneKeyHeader is the header carrying the link key of the drone (see drone.Drone.IssueLinkKey).
+const DroneKeyHeader = "X-Drone-Key"
+
+// ErrDroneNotConnected error occurs when a command is sent to a drone without an open link.
+var ErrDroneNotConnected = errors.New("drone not connected")
+
+// LinkMessageDTO struct is the message exchanged with the drones over GET /drone/{serial}/link.
+//   - telemetry (drone -> server): battery, state and/or position of the drone.
+//   - command (server -> drone): command_id and command.
+//   - ack (both): command_id acknowledged by the drone, or empty when the telemetry is applied.
+//   - error (server -> drone): the reason a message was rejected.
+type LinkMessageDTO struct {
+	Type      string          `json:"type"`
+	Battery   *uint8          `json:"battery,omitempty"`
+	State     *drone.State    `json:"state,omitempty"`
+	Position  *CoordinatesDTO `json:"position,omitempty"`
+	CommandID string          `json:"command_id,omitempty"`
+	Command   drone.Command   `json:"command,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// LinkStatus describes the connection of a drone to the service.
+type LinkStatus struct {
+	Connected   bool
+	ConnectedAt time.Time
+	LastSeenAt  time.Time
+}
+
+// droneLinkKey identifies the connection of a drone inside its tenant.
+type droneLinkKey struct {
+	tenantID string
+	serial   string
+}
+
+// droneConn is an open WebSocket connection of a drone.
+type droneConn struct {
+	conn *websocket.Conn
+	send chan LinkMessageDTO
+	done chan struct{}
+}
+
+// DroneLink keeps the WebSocket connections of the drones, applying their
+// telemetry and forwarding the commands issued through the API.
+type DroneLink struct {
+	storage     drone.Storage
+	locks       *drone.Locks
+	auditStore  drone.AuditStore
+	docks       *drone.DockManager
+	maintenance drone.MaintenanceRules
+	upgrader    websocket.Upgrader
+
+	mu       sync.Mutex
+	conns    map[droneLinkKey]*droneConn
+	statuses map[droneLinkKey]LinkStatus
+}
+
+// NewDroneLink builds a DroneLink, the telemetry docks the drones through docks (if not nil)
+// and grounds them under the maintenance rules, holding the locks of the drones.
+func NewDroneLink(storage drone.Storage, locks *drone.Locks, auditStore drone.AuditStore, docks *drone.DockManager, maintenance drone.MaintenanceRules) *DroneLink {
+	return &DroneLink{
+		storage:     storage,
+		locks:       locks,
+		auditStore:  auditStore,
+		docks:       docks,
+		maintenance: maintenance,
+		conns:       make(map[droneLinkKey]*droneConn),
+		statuses:    make(map[droneLinkKey]LinkStatus),
+	}
+}
+
+// Status returns the LinkStatus of a drone of the tenant.
+func (l *DroneLink) Status(tenantID, serial string) LinkStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.statuses[droneLinkKey{tenantID: tenantID, serial: serial}]
+}
+
+// Send forwards a command to a connected drone and returns the command ID.
+func (l *DroneLink) Send(tenantID, serial string, c drone.Command) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	dc, ok := l.conns[droneLinkKey{tenantID: tenantID, serial: serial}]
+	if !ok {
+		return "", ErrDroneNotConnected
+	}
+
+	id := newCommandID()
+	select {
+	case dc.send <- LinkMessageDTO{Type: LinkCommand, CommandID: id, Command: c}:
+		return id, nil
+	default:
+		return "", errors.New("drone link is busy")
+	}
+}
+
+// Connect upgrades the request to the WebSocket link of the drone.
+// NOTE: the drone authenticates with the API key of its tenant and its own
+// link key in the DroneKeyHeader, so a tenant key alone can't act as a drone.
+func (l *DroneLink) Connect(w http.ResponseWriter, r *http.Request) {
+	p, _ := PrincipalFromContext(r.Context())
+	k := droneLinkKey{tenantID: p.TenantID, serial: chi.URLParam(r, "serial")}
+	d, err := l.storage.Drone(r.Context(), k.tenantID, k.serial)
+	if err != nil {
+		if err == drone.ErrNotFound {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := d.CheckLinkKey(r.Header.Get(DroneKeyHeader)); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	clearDeadlines(w)
+	conn, err := l.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// NOTE: the upgrader already replied with an error.
+		return
+	}
+
+	dc := &droneConn{conn: conn, send: make(chan LinkMessageDTO, linkSendBuffer), done: make(chan struct{})}
+	l.register(k, dc)
+	defer l.unregister(k, dc)
+
+	go l.writePump(dc)
+	l.readPump(r.Context(), k, dc)
+}
+
+// register stores the connection, closing the previous connection of the drone (if any).
+func (l *DroneLink) register(k droneLinkKey, dc *droneConn) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if prev, ok := l.conns[k]; ok {
+		_ = prev.conn.Close()
+	}
+
+	now := time.Now().UTC()
+	l.conns[k] = dc
+	l.statuses[k] = LinkStatus{Connected: true, ConnectedAt: now, LastSeenAt: now}
+}
+
+func (l *DroneLink) unregister(k droneLinkKey, dc *droneConn) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	close(dc.done)
+	_ = dc.conn.Close()
+	if l.conns[k] != dc {
+		return // replaced by a newer connection
+	}
+
+	delete(l.conns, k)
+	status := l.statuses[k]
+	status.Connected = false
+	l.statuses[k] = status
+}
+
+func (l *DroneLink) touch(k droneLinkKey) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	status := l.statuses[k]
+	status.LastSeenAt = time.Now().UTC()
+	l.statuses[k] = status
+}
+
+func (l *DroneLink) readPump(ctx context.Context, k droneLinkKey, dc *droneConn) {
+	_ = dc.conn.SetReadDeadline(time.Now().Add(linkPongWait))
+	dc.conn.SetPongHandler(func(string) error {
+		l.touch(k)
+		return dc.conn.SetReadDeadline(time.Now().Add(linkPongWait))
+	})
+
+	for {
+		var msg LinkMessageDTO
+		if err := dc.conn.ReadJSON(&msg); err != nil {
+			// NOTE: a malformed message is answered, only a broken connection ends the link.
+			var (
+				syntaxErr *json.SyntaxError
+				typeErr   *json.UnmarshalTypeError
+			)
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				l.reply(dc, LinkMessageDTO{Type: LinkError, Error: err.Error()})
+				continue
+			}
+
+			return
+		}
+
+		l.touch(k)
+		switch msg.Type {
+		case LinkTelemetry:
+			if err := l.applyTelemetry(ctx, k, msg); err != nil {
+				l.reply(dc, LinkMessageDTO{Type: LinkError, Error: err.Error()})
+				continue
+			}
+
+			l.reply(dc, LinkMessageDTO{Type: LinkAck})
+		case LinkAck:
+			// NOTE: commands are fire and forget for now, the ack only refreshes LastSeenAt.
+		default:
+			l.reply(dc, LinkMessageDTO{Type: LinkError, Error: "unknown message type"})
+		}
+	}
+}
+
+func (l *DroneLink) writePump(dc *droneConn) {
+	ticker := time.NewTicker(linkPingPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case msg := <-dc.send:
+			_ = dc.conn.SetWriteDeadline(time.Now().Add(linkWriteWait))
+			if err := dc.conn.WriteJSON(msg); err != nil {
+				_ = dc.conn.Close()
+				return
+			}
+		case <-ticker.C:
+			_ = dc.conn.SetWriteDeadline(time.Now().Add(linkWriteWait))
+			if err := dc.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				_ = dc.conn.Close()
+				return
+			}
+		case <-dc.done:
+			return
+		}
+	}
+}
+
+// reply queues a message for the drone, dropping it if the connection is congested.
+func (l *DroneLink) reply(dc *droneConn, msg LinkMessageDTO) {
+	select {
+	case dc.send <- msg:
+	default:
+	}
+}
+
+// applyTelemetry updates the battery, state and position of the drone with
+// the reported values and recalculates its ETA.
+func (l *DroneLink) applyTelemetry(ctx context.Context, k droneLinkKey, msg LinkMessageDTO) error {
+	if msg.Battery != nil && *msg.Battery > 100 {
+		return errors.New("battery capacity exceed 100%")
+	}
+
+	if msg.Position != nil {
+		if err := msg.Position.coordinates().Validate(); err != nil {
+			return err
+		}
+	}
+
+	unlock := l.locks.Lock(k.tenantID, k.serial)
+	defer unlock()
+
+	d, err := l.storage.Drone(ctx, k.tenantID, k.serial)
+	if err != nil {
+		return err
+	}
+
+	before := d
+	if msg.Battery != nil {
+		d.UpdateBattery(*msg.Battery)
+	}
+
+	if msg.State != nil {
+		if err := d.ApplyReportedState(*msg.State); err != nil {
+			return err
+		}
+	}
+
+	if msg.Position != nil {
+		// NOTE: validated above.
+		_ = d.UpdatePosition(msg.Position.coordinates())
+	}
+
+	l.maintenance.Track(&d, time.Now().UTC())
+	if l.docks != nil {
+		if err := l.docks.Track(ctx, &d); err != nil {
+			return err
+		}
+	}
+
+	docked := d.DockID != before.DockID || d.ChargingSessionID != before.ChargingSessionID
+	if len(d.Events()) == 0 && msg.Position == nil && !docked {
+		return nil // nothing changed
+	}
+
+	if err := l.storage.SaveDrone(ctx, d); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, l.auditStore, drone.AuditTelemetry, before, d)
+	return nil
+}
+
+func newCommandID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -7,6 +7,6 @@ import (
 )
 
 // droneSerialFromRequest extracts the drone serial from the path parameters.
-func (h *DroneController) droneSerialFromRequest(r *http.Request) string {
+func droneSerialFromRequest(r *http.Request) string {
 	return chi.URLParam(r, "serial")
 }
@@ -0,0 +1,223 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+const (
+	// defaultStreamHistory is the amount of events kept to resume the streams.
+	defaultStreamHistory = 1024
+	// streamKeepAlive is the interval between the keep-alive comments sent to idle streams.
+	streamKeepAlive = 15 * time.Second
+	// streamClientBuffer is the amount of events buffered per client before dropping it.
+	streamClientBuffer = 64
+)
+
+// DroneEventDTO struct is the data of the events sent by GET /events and GET /drone/{serial}/events
+type DroneEventDTO struct {
+	ID            string          `json:"id"`
+	Type          drone.EventType `json:"type"`
+	Serial        string          `json:"serial"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	PreviousState drone.State     `json:"previous_state,omitempty"`
+	State         drone.State     `json:"state,omitempty"`
+	BatteryLevel  uint8           `json:"battery_level"`
+	Medication    *MedicationDTO  `json:"medication,omitempty"`
+}
+
+// streamClient is a connected Server-Sent Events client.
+type streamClient struct {
+	tenantID string
+	// serial filters the events of a single drone (empty for the whole fleet).
+	serial string
+	events chan drone.Event
+}
+
+func (c *streamClient) accepts(e drone.Event) bool {
+	return e.TenantID == c.tenantID && (c.serial == "" || e.Serial == c.serial)
+}
+
+// EventStream pushes the dispatched drone events to the Server-Sent Events clients.
+// Subscribe its Publish method to a drone.Dispatcher.
+type EventStream struct {
+	storage drone.Storage
+
+	mu      sync.Mutex
+	history []drone.Event
+	// published are the IDs of the events in history.
+	published map[string]struct{}
+	clients   map[*streamClient]struct{}
+}
+
+func NewEventStream(storage drone.Storage) *EventStream {
+	return &EventStream{
+		storage:   storage,
+		published: make(map[string]struct{}),
+		clients:   make(map[*streamClient]struct{}),
+	}
+}
+
+// Publish implements drone.EventHandler, sending the event to the connected clients.
+// NOTE: a client that can't keep up is disconnected, it can resume with Last-Event-ID.
+// The events redelivered by the outbox (when another handler failed) are sent once.
+func (s *EventStream) Publish(_ context.Context, e drone.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.published[e.ID]; ok {
+		return nil
+	}
+
+	s.published[e.ID] = struct{}{}
+	if s.history = append(s.history, e); len(s.history) > defaultStreamHistory {
+		for _, old := range s.history[:len(s.history)-defaultStreamHistory] {
+			delete(s.published, old.ID)
+		}
+
+		s.history = s.history[len(s.history)-defaultStreamHistory:]
+	}
+
+	for c := range s.clients {
+		if !c.accepts(e) {
+			continue
+		}
+
+		select {
+		case c.events <- e:
+		default:
+			s.removeClient(c)
+		}
+	}
+
+	return nil
+}
+
+// StreamEvents streams the events of the tenant fleet.
+func (s *EventStream) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	s.stream(w, r, &streamClient{tenantID: tenantFromRequest(r)})
+}
+
+// StreamDroneEvents streams the events of a drone of the tenant.
+func (s *EventStream) StreamDroneEvents(w http.ResponseWriter, r *http.Request) {
+	tenantID, droneSerial := tenantFromRequest(r), droneSerialFromRequest(r)
+	if _, err := s.storage.Drone(r.Context(), tenantID, droneSerial); err != nil {
+		if err == drone.ErrNotFound {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.stream(w, r, &streamClient{tenantID: tenantID, serial: droneSerial})
+}
+
+func (s *EventStream) stream(w http.ResponseWriter, r *http.Request, c *streamClient) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	clearDeadlines(w)
+
+	c.events = make(chan drone.Event, streamClientBuffer)
+	replay := s.addClient(c, r.Header.Get("Last-Event-ID"))
+	defer func() {
+		s.mu.Lock()
+		s.removeClient(c)
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	for _, e := range replay {
+		if err := writeStreamEvent(w, e); err != nil {
+			return
+		}
+	}
+
+	flusher.Flush()
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case e, ok := <-c.events:
+			if !ok {
+				return
+			}
+
+			if err := writeStreamEvent(w, e); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+
+		flusher.Flush()
+	}
+}
+
+// addClient registers the client and returns the events of its history after lastEventID.
+func (s *EventStream) addClient(c *streamClient, lastEventID string) []drone.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clients[c] = struct{}{}
+	if lastEventID == "" {
+		return nil
+	}
+
+	var replay []drone.Event
+	for _, e := range s.history {
+		// NOTE: event IDs sort in the order the events occurred.
+		if e.ID > lastEventID && c.accepts(e) {
+			replay = append(replay, e)
+		}
+	}
+
+	return replay
+}
+
+// removeClient unregisters the client. The caller must hold s.mu.
+func (s *EventStream) removeClient(c *streamClient) {
+	if _, ok := s.clients[c]; ok {
+		delete(s.clients, c)
+		close(c.events)
+	}
+}
+
+func writeStreamEvent(w http.ResponseWriter, e drone.Event) error {
+	dto := DroneEventDTO{
+		ID:            e.ID,
+		Type:          e.Type,
+		Serial:        e.Serial,
+		OccurredAt:    e.OccurredAt,
+		PreviousState: e.PreviousState,
+		State:         e.State,
+		BatteryLevel:  e.BatteryLevel,
+	}
+	if e.Medication != nil {
+		m := newMedicationDTO(*e.Medication)
+		dto.Medication = &m
+	}
+
+	data, err := json.Marshal(dto)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return err
+}
@@ -1,30 +1,46 @@
 package http
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/go-chi/chi/v5"
 )
 
-func (h *DroneController) saveFile(src io.Reader) (filename string, err error) {
-	if err = os.MkdirAll(h.uploadDir, os.ModePerm); err != nil {
-		return "", fmt.Errorf("create %q dir: %w", h.uploadDir, err)
+// saveFile saves the upload in the directory of the tenant inside the uploadDir.
+func (h *DroneController) saveFile(ctx context.Context, tenantID string, src io.Reader) (filename string, err error) {
+	_, span := startStep(ctx, "saveFile")
+	defer func() { endStep(span, err) }()
+	dir := filepath.Join(h.uploadDir, tenantID)
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("create %q dir: %w", dir, err)
 	}
 
 	randomName := strconv.FormatInt(time.Now().UnixNano(), 10)
-	filename = filepath.Join(h.uploadDir, randomName)
+	filename = filepath.Join(dir, randomName)
 	dst, err := os.Create(filename)
 	if err != nil {
 		return "", fmt.Errorf("create new file: %w", err)
 	}
 
 	defer dst.Close()
-	if _, err := io.Copy(dst, src); err != nil {
+	if _, err = io.Copy(dst, src); err != nil {
 		return "", fmt.Errorf("fill new file with data: %w", err)
 	}
 
 	return filename, nil
 }
+
+// GetUpload serves the uploaded files of the tenant, by their name.
+func (h *DroneController) GetUpload(w http.ResponseWriter, r *http.Request) {
+	pathPrefix := strings.TrimSuffix(chi.RouteContext(r.Context()).RoutePattern(), "/*")
+	dir := http.Dir(filepath.Join(h.uploadDir, tenantFromRequest(r)))
+	http.StripPrefix(pathPrefix, http.FileServer(dir)).ServeHTTP(w, r)
+}
@@ -0,0 +1,22 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hsequeda/drone/drone"
+)
+
+// GeofenceController manages the no-fly zones of the tenant.
+type GeofenceController struct {
+	store drone.GeofenceStore
+}
+
+func NewGeofenceController(store drone.GeofenceStore) *GeofenceController {
+	return &GeofenceController{store: store}
+}
+
+// geofenceIDFromRequest extracts the geofence ID from the path parameters.
+func (h *GeofenceController) geofenceIDFromRequest(r *http.Request) string {
+	return chi.URLParam(r, "id")
+}
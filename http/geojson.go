@@ -0,0 +1,70 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// ErrUnsupportedGeoJSON error occurs when a GeoJSON object isn't a Feature with a Polygon without holes.
+var ErrUnsupportedGeoJSON = errors.New("unsupported geojson: expected a Feature with a Polygon without holes")
+
+// GeofenceDTO struct is a no-fly zone as a GeoJSON Feature, used in the body and the responses of /geofences.
+type GeofenceDTO struct {
+	Type       string                `json:"type"`
+	ID         string                `json:"id,omitempty"`
+	Properties GeofencePropertiesDTO `json:"properties"`
+	Geometry   PolygonDTO            `json:"geometry"`
+}
+
+// GeofencePropertiesDTO struct is the properties of the GeofenceDTO.
+type GeofencePropertiesDTO struct {
+	Name string `json:"name"`
+}
+
+// PolygonDTO struct is a GeoJSON Polygon, its positions are [longitude, latitude].
+type PolygonDTO struct {
+	Type        string        `json:"type"`
+	Coordinates [][][]float64 `json:"coordinates"`
+}
+
+// GeofenceCollectionDTO struct is used in the response of GET /geofences.
+type GeofenceCollectionDTO struct {
+	Type     string        `json:"type"`
+	Features []GeofenceDTO `json:"features"`
+}
+
+// polygon returns the exterior ring of the Polygon.
+func (dto GeofenceDTO) polygon() ([]drone.Coordinates, error) {
+	if dto.Type != "Feature" || dto.Geometry.Type != "Polygon" || len(dto.Geometry.Coordinates) != 1 {
+		return nil, ErrUnsupportedGeoJSON
+	}
+
+	ring := make([]drone.Coordinates, len(dto.Geometry.Coordinates[0]))
+	for i, position := range dto.Geometry.Coordinates[0] {
+		if len(position) < 2 {
+			return nil, fmt.Errorf("%w: position %d has %d values", ErrUnsupportedGeoJSON, i, len(position))
+		}
+
+		ring[i] = drone.Coordinates{Longitude: position[0], Latitude: position[1]}
+	}
+
+	return ring, nil
+}
+
+func newGeofenceDTO(g drone.Geofence) GeofenceDTO {
+	// NOTE: GeoJSON rings are closed.
+	ring := make([][]float64, 0, len(g.Polygon)+1)
+	for i := 0; i <= len(g.Polygon); i++ {
+		c := g.Polygon[i%len(g.Polygon)]
+		ring = append(ring, []float64{c.Longitude, c.Latitude})
+	}
+
+	return GeofenceDTO{
+		Type:       "Feature",
+		ID:         g.ID,
+		Properties: GeofencePropertiesDTO{Name: g.Name},
+		Geometry:   PolygonDTO{Type: "Polygon", Coordinates: [][][]float64{ring}},
+	}
+}
@@ -18,15 +18,19 @@ type AvailableDroneDTO struct {
 }
 
 func (h *DroneController) GetAvailableDrones(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "DroneController.GetAvailableDrones")
+	defer span.End()
+
 	var availableDrones []AvailableDroneDTO
-	drones, err := h.storage.Drones(r.Context())
+	tenantID := tenantFromRequest(r)
+	drones, err := h.storage.Drones(r.Context(), tenantID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		fail(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
 	for _, d := range drones {
-		if d.IsAvailable() {
+		if d.IsAvailable(h.policies.For(tenantID)) {
 			availableDrones = append(availableDrones, AvailableDroneDTO{
 				Serial:          d.Serial,
 				Model:           d.Model,
@@ -39,7 +43,7 @@ func (h *DroneController) GetAvailableDrones(w http.ResponseWriter, r *http.Requ
 	}
 
 	if err := json.NewEncoder(w).Encode(availableDrones); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		fail(w, r, err, http.StatusInternalServerError)
 		return
 	}
 }
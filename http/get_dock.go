@@ -0,0 +1,77 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// DockDetailDTO struct is the response of GET /docks/{id}.
+type DockDetailDTO struct {
+	DockDTO
+	Drones []DockedDroneDTO `json:"drones"`
+}
+
+// DockedDroneDTO struct is a drone in a dock.
+type DockedDroneDTO struct {
+	Serial          string      `json:"serial"`
+	State           drone.State `json:"state"`
+	BatteryCapacity uint8       `json:"battery_capacity"`
+	Charging        bool        `json:"charging"`
+	// EstimatedChargedAt is when the drone charging reaches the charged level.
+	EstimatedChargedAt *time.Time `json:"estimated_charged_at,omitempty"`
+}
+
+// GetDock returns a charging dock of the tenant with its drones, GET /docks/{id}.
+func (h *DockController) GetDock(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "DockController.GetDock")
+	defer span.End()
+
+	tenantID, id := tenantFromRequest(r), h.dockIDFromRequest(r)
+	d, err := h.store.Dock(r.Context(), tenantID, id)
+	if err != nil {
+		if errors.Is(err, drone.ErrDockNotFound) {
+			fail(w, r, err, http.StatusNotFound)
+			return
+		}
+
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	occupancy, err := h.manager.Occupancy(r.Context(), tenantID)
+	if err != nil {
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	sessions, err := h.store.ChargingSessions(r.Context(), tenantID, id)
+	if err != nil {
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	active := make(map[string]drone.ChargingSession)
+	for _, s := range sessions {
+		if s.Active() {
+			active[s.ID] = s
+		}
+	}
+
+	dto := DockDetailDTO{DockDTO: newDockDTO(d, occupancy[id]), Drones: make([]DockedDroneDTO, 0, len(occupancy[id]))}
+	for _, dr := range occupancy[id] {
+		docked := DockedDroneDTO{Serial: dr.Serial, State: dr.State, BatteryCapacity: dr.BatteryCapacity, Charging: dr.Charging()}
+		if s, ok := active[dr.ChargingSessionID]; ok {
+			end := s.EstimatedEnd(d)
+			docked.EstimatedChargedAt = &end
+		}
+
+		dto.Drones = append(dto.Drones, docked)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(dto)
+}
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// ChargingSessionDTO struct is used in the response of GET /docks/{id}/sessions.
+type ChargingSessionDTO struct {
+	ID           string    `json:"id"`
+	Serial       string    `json:"serial"`
+	StartedAt    time.Time `json:"started_at"`
+	StartBattery uint8     `json:"start_battery"`
+	// EndedAt and EndBattery are omitted while the session is active, EstimatedEnd once it ends.
+	EndedAt      *time.Time `json:"ended_at,omitempty"`
+	EndBattery   *uint8     `json:"end_battery,omitempty"`
+	EstimatedEnd *time.Time `json:"estimated_end,omitempty"`
+}
+
+// GetDockSessions returns the charging sessions of a dock of the tenant, oldest
+// first, GET /docks/{id}/sessions.
+func (h *DockController) GetDockSessions(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "DockController.GetDockSessions")
+	defer span.End()
+
+	tenantID, id := tenantFromRequest(r), h.dockIDFromRequest(r)
+	d, err := h.store.Dock(r.Context(), tenantID, id)
+	if err != nil {
+		if errors.Is(err, drone.ErrDockNotFound) {
+			fail(w, r, err, http.StatusNotFound)
+			return
+		}
+
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	sessions, err := h.store.ChargingSessions(r.Context(), tenantID, id)
+	if err != nil {
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	dtos := make([]ChargingSessionDTO, len(sessions))
+	for i, s := range sessions {
+		dtos[i] = ChargingSessionDTO{ID: s.ID, Serial: s.Serial, StartedAt: s.StartedAt, StartBattery: s.StartBattery}
+		if s.Active() {
+			end := s.EstimatedEnd(d)
+			dtos[i].EstimatedEnd = &end
+			continue
+		}
+
+		endedAt, endBattery := s.EndedAt, s.EndBattery
+		dtos[i].EndedAt, dtos[i].EndBattery = &endedAt, &endBattery
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(dtos)
+}
@@ -0,0 +1,33 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// GetDocks returns the charging docks of the tenant with their occupancy, GET /docks.
+func (h *DockController) GetDocks(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "DockController.GetDocks")
+	defer span.End()
+
+	tenantID := tenantFromRequest(r)
+	docks, err := h.store.Docks(r.Context(), tenantID)
+	if err != nil {
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	occupancy, err := h.manager.Occupancy(r.Context(), tenantID)
+	if err != nil {
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	dtos := make([]DockDTO, len(docks))
+	for i, d := range docks {
+		dtos[i] = newDockDTO(d, occupancy[d.ID])
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(dtos)
+}
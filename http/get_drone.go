@@ -0,0 +1,78 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// DroneDTO struct is used in the response of GET /drone/{serial}
+type DroneDTO struct {
+	Serial          string           `json:"serial"`
+	Model           drone.Model      `json:"model"`
+	WeightLimit     uint32           `json:"weight_limit"`
+	BatteryCapacity uint8            `json:"battery_capacity"`
+	ConsumedWeight  uint32           `json:"consumed_weight"`
+	State           drone.State      `json:"state"`
+	Home            *CoordinatesDTO  `json:"home,omitempty"`
+	Destination     *CoordinatesDTO  `json:"destination,omitempty"`
+	Waypoints       []CoordinatesDTO `json:"waypoints,omitempty"`
+	Position        *CoordinatesDTO  `json:"position,omitempty"`
+	ETA             *ETADTO          `json:"eta,omitempty"`
+	DockID          string           `json:"dock_id,omitempty"`
+	Charging        bool             `json:"charging"`
+	Maintenance     MaintenanceDTO   `json:"maintenance"`
+	// Capabilities are the capabilities of the drone and its model.
+	Capabilities []drone.Capability `json:"capabilities,omitempty"`
+	Connected    bool               `json:"connected"`
+	LastSeenAt   *time.Time         `json:"last_seen_at,omitempty"`
+}
+
+func (h *DroneController) GetDrone(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "DroneController.GetDrone")
+	defer span.End()
+
+	tenantID, droneSerial := tenantFromRequest(r), droneSerialFromRequest(r)
+	d, err := h.storage.Drone(r.Context(), tenantID, droneSerial)
+	if err != nil {
+		if err == drone.ErrNotFound {
+			fail(w, r, err, http.StatusBadRequest)
+			return
+		}
+
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	status := h.link.Status(tenantID, droneSerial)
+	dto := DroneDTO{
+		Serial:          d.Serial,
+		Model:           d.Model,
+		WeightLimit:     d.WeightLimit,
+		BatteryCapacity: d.BatteryCapacity,
+		ConsumedWeight:  d.MedicationWeight(),
+		State:           d.State,
+		Home:            newCoordinatesDTO(d.Home),
+		Destination:     newCoordinatesDTO(d.Destination),
+		Position:        newCoordinatesDTO(d.Position),
+		ETA:             newETADTO(d, time.Now().UTC()),
+		DockID:          d.DockID,
+		Charging:        d.Charging(),
+		Maintenance:     newMaintenanceDTO(d.Maintenance),
+		Capabilities:    d.AllCapabilities(h.policies.For(tenantID)),
+		Connected:       status.Connected,
+	}
+	for _, c := range d.Waypoints {
+		dto.Waypoints = append(dto.Waypoints, CoordinatesDTO{Latitude: c.Latitude, Longitude: c.Longitude})
+	}
+	if !status.LastSeenAt.IsZero() {
+		dto.LastSeenAt = &status.LastSeenAt
+	}
+
+	if err := json.NewEncoder(w).Encode(dto); err != nil {
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// FieldChangeDTO struct is used in the response of GET /drone/{serial}/audit
+type FieldChangeDTO struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// AuditEventDTO struct is used in the response of GET /drone/{serial}/audit
+type AuditEventDTO struct {
+	Actor     string            `json:"actor"`
+	RequestID string            `json:"request_id"`
+	Serial    string            `json:"serial"`
+	Action    drone.AuditAction `json:"action"`
+	At        time.Time         `json:"at"`
+	Changes   []FieldChangeDTO  `json:"changes"`
+}
+
+func (h *DroneController) GetDroneAudit(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "DroneController.GetDroneAudit")
+	defer span.End()
+
+	tenantID, droneSerial := tenantFromRequest(r), droneSerialFromRequest(r)
+	if _, err := h.storage.Drone(r.Context(), tenantID, droneSerial); err != nil {
+		if err == drone.ErrNotFound {
+			fail(w, r, err, http.StatusBadRequest)
+			return
+		}
+
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	events, err := h.auditStore.AuditEvents(r.Context(), tenantID, droneSerial)
+	if err != nil {
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	eventDTOs := make([]AuditEventDTO, len(events))
+	for i, e := range events {
+		changeDTOs := make([]FieldChangeDTO, len(e.Changes))
+		for j, c := range e.Changes {
+			// NOTE: use value convertion because the fields match for now.
+			changeDTOs[j] = FieldChangeDTO(c)
+		}
+
+		eventDTOs[i] = AuditEventDTO{
+			Actor:     e.Actor,
+			RequestID: e.RequestID,
+			Serial:    e.Serial,
+			Action:    e.Action,
+			At:        e.At,
+			Changes:   changeDTOs,
+		}
+	}
+
+	if err := json.NewEncoder(w).Encode(eventDTOs); err != nil {
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// BatteryRecordDTO struct is used in the response of GET /drone/{serial}/battery/history
+type BatteryRecordDTO struct {
+	At           time.Time   `json:"at"`
+	BatteryLevel uint8       `json:"battery_level"`
+	State        drone.State `json:"state"`
+}
+
+// GetDroneBatteryHistory returns the battery records of the drone, optionally
+// filtered by the `from` and `to` (RFC 3339) query parameters.
+func (h *DroneController) GetDroneBatteryHistory(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "DroneController.GetDroneBatteryHistory")
+	defer span.End()
+
+	from, err := timeFromQuery(r, "from")
+	if err != nil {
+		fail(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	to, err := timeFromQuery(r, "to")
+	if err != nil {
+		fail(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	tenantID, droneSerial := tenantFromRequest(r), droneSerialFromRequest(r)
+	if _, err := h.storage.Drone(r.Context(), tenantID, droneSerial); err != nil {
+		if err == drone.ErrNotFound {
+			fail(w, r, err, http.StatusBadRequest)
+			return
+		}
+
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	records, err := h.batteryHistory.BatteryRecords(r.Context(), tenantID, droneSerial, from, to)
+	if err != nil {
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	recordDTOs := make([]BatteryRecordDTO, len(records))
+	for i, rec := range records {
+		recordDTOs[i] = BatteryRecordDTO{At: rec.At, BatteryLevel: rec.Battery, State: rec.State}
+	}
+
+	if err := json.NewEncoder(w).Encode(recordDTOs); err != nil {
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+}
+
+// timeFromQuery parses an (optional) RFC 3339 query parameter.
+func timeFromQuery(r *http.Request, key string) (time.Time, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %q: %w", key, err)
+	}
+
+	return t, nil
+}
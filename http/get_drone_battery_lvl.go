@@ -13,21 +13,24 @@ type DroneBatteryLevelDTO struct {
 }
 
 func (h *DroneController) GetDroneBatteryLevel(w http.ResponseWriter, r *http.Request) {
-	droneSerial := h.droneSerialFromRequest(r)
-	d, err := h.storage.Drone(r.Context(), droneSerial)
+	r, span := startSpan(r, "DroneController.GetDroneBatteryLevel")
+	defer span.End()
+
+	droneSerial := droneSerialFromRequest(r)
+	d, err := h.storage.Drone(r.Context(), tenantFromRequest(r), droneSerial)
 	if err != nil {
 		if err == drone.ErrNotFound {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			fail(w, r, err, http.StatusBadRequest)
 			return
 		}
 
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		fail(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
 	bc := DroneBatteryLevelDTO{BatteryLevel: d.BatteryCapacity}
 	if err := json.NewEncoder(w).Encode(bc); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		fail(w, r, err, http.StatusInternalServerError)
 		return
 	}
 }
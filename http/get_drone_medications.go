@@ -12,30 +12,52 @@ type MedicationDTO struct {
 	Name   string `json:"name"`
 	Weight uint32 `json:"weight"`
 	Code   string `json:"code"`
-	Image  string `json:"picture_path"`
+	// Image is the picture of a loaded medication (omitted for the ones of an order not loaded yet).
+	Image string `json:"picture_path,omitempty"`
+	// Handling are the special handlings of the medication, see drone.HandlingClass.
+	Handling []drone.HandlingClass `json:"handling,omitempty"`
+	// Incompatible are the codes of the medications it can't share a drone with.
+	Incompatible []string `json:"incompatible,omitempty"`
+}
+
+func newMedicationDTO(m drone.Medication) MedicationDTO {
+	return MedicationDTO{
+		Name:         m.Name,
+		Weight:       m.Weight,
+		Code:         m.Code,
+		Image:        m.Image,
+		Handling:     m.Handling,
+		Incompatible: m.Incompatible,
+	}
+}
+
+func newMedicationDTOs(meds []drone.Medication) []MedicationDTO {
+	dtos := make([]MedicationDTO, len(meds))
+	for i, m := range meds {
+		dtos[i] = newMedicationDTO(m)
+	}
+
+	return dtos
 }
 
 func (h *DroneController) GetDroneMedications(w http.ResponseWriter, r *http.Request) {
-	droneSerial := h.droneSerialFromRequest(r)
-	d, err := h.storage.Drone(r.Context(), droneSerial)
+	r, span := startSpan(r, "DroneController.GetDroneMedications")
+	defer span.End()
+
+	droneSerial := droneSerialFromRequest(r)
+	d, err := h.storage.Drone(r.Context(), tenantFromRequest(r), droneSerial)
 	if err != nil {
 		if err == drone.ErrNotFound {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			fail(w, r, err, http.StatusBadRequest)
 			return
 		}
 
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		fail(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
-	medDTOs := make([]MedicationDTO, len(d.Medications))
-	for i, m := range d.Medications {
-		// NOTE: use value convertion because the fields match for now.
-		medDTOs[i] = MedicationDTO(m)
-	}
-
-	if err := json.NewEncoder(w).Encode(medDTOs); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := json.NewEncoder(w).Encode(newMedicationDTOs(d.Medications)); err != nil {
+		fail(w, r, err, http.StatusInternalServerError)
 		return
 	}
 }
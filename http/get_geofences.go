@@ -0,0 +1,26 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// GetGeofences returns the no-fly zones of the tenant as a GeoJSON FeatureCollection, GET /geofences.
+func (h *GeofenceController) GetGeofences(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "GeofenceController.GetGeofences")
+	defer span.End()
+
+	zones, err := h.store.Geofences(r.Context(), tenantFromRequest(r))
+	if err != nil {
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	collection := GeofenceCollectionDTO{Type: "FeatureCollection", Features: make([]GeofenceDTO, len(zones))}
+	for i, g := range zones {
+		collection.Features[i] = newGeofenceDTO(g)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(collection)
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/hsequeda/drone/health"
+)
+
+// HealthCheckDTO struct is the result of a check in the response of GET /readyz
+type HealthCheckDTO struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// ReadinessDTO struct is used in the response of GET /readyz
+type ReadinessDTO struct {
+	Status string           `json:"status"`
+	Checks []HealthCheckDTO `json:"checks"`
+}
+
+// Livez reports that the process is up, without checking its dependencies.
+func (h *HealthController) Livez(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte("ok\n"))
+}
+
+// Readyz runs the checks of the dependencies, replying 503 when any of them is down.
+func (h *HealthController) Readyz(w http.ResponseWriter, r *http.Request) {
+	report := h.checker.Run(r.Context())
+	dto := ReadinessDTO{Status: string(report.Status), Checks: make([]HealthCheckDTO, len(report.Checks))}
+	for i, c := range report.Checks {
+		dto.Checks[i] = HealthCheckDTO{
+			Name:      c.Name,
+			Status:    string(c.Status),
+			LatencyMs: float64(c.Latency.Microseconds()) / 1000,
+			Error:     c.Error,
+		}
+	}
+
+	status := http.StatusOK
+	if report.Status == health.StatusDown {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(dto)
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// JobStatusDTO struct is used in the response of GET /admin/jobs
+type JobStatusDTO struct {
+	Name           string     `json:"name"`
+	Schedule       string     `json:"schedule"`
+	Running        bool       `json:"running"`
+	Runs           int        `json:"runs"`
+	Failures       int        `json:"failures"`
+	LastStartedAt  *time.Time `json:"last_started_at,omitempty"`
+	LastFinishedAt *time.Time `json:"last_finished_at,omitempty"`
+	LastDurationMs int64      `json:"last_duration_ms"`
+	LastError      string     `json:"last_error,omitempty"`
+	NextRunAt      *time.Time `json:"next_run_at,omitempty"`
+}
+
+func (h *JobController) GetJobs(w http.ResponseWriter, _ *http.Request) {
+	status := h.scheduler.Status()
+	jobDTOs := make([]JobStatusDTO, len(status))
+	for i, s := range status {
+		jobDTOs[i] = JobStatusDTO{
+			Name:           s.Name,
+			Schedule:       s.Schedule,
+			Running:        s.Running,
+			Runs:           s.Runs,
+			Failures:       s.Failures,
+			LastStartedAt:  timeOrNil(s.LastStartedAt),
+			LastFinishedAt: timeOrNil(s.LastFinishedAt),
+			LastDurationMs: s.LastDuration.Milliseconds(),
+			LastError:      s.LastError,
+			NextRunAt:      timeOrNil(s.NextRunAt),
+		}
+	}
+
+	if err := json.NewEncoder(w).Encode(jobDTOs); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// timeOrNil omits the zero time in the responses.
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
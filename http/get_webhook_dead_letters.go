@@ -0,0 +1,46 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// WebhookDeadLetterDTO struct is used in the response of GET /webhooks/dead-letters
+type WebhookDeadLetterDTO struct {
+	WebhookID string          `json:"webhook_id"`
+	EventID   string          `json:"event_id"`
+	EventType drone.EventType `json:"event_type"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"last_error"`
+	At        time.Time       `json:"at"`
+}
+
+func (h *WebhookController) GetWebhookDeadLetters(w http.ResponseWriter, r *http.Request) {
+	dls, err := h.store.DeadLetters(r.Context(), tenantFromRequest(r))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	dlDTOs := make([]WebhookDeadLetterDTO, len(dls))
+	for i, dl := range dls {
+		dlDTOs[i] = WebhookDeadLetterDTO{
+			WebhookID: dl.SubscriptionID,
+			EventID:   dl.EventID,
+			EventType: dl.EventType,
+			Payload:   dl.Payload,
+			Attempts:  dl.Attempts,
+			LastError: dl.LastError,
+			At:        dl.At,
+		}
+	}
+
+	if err := json.NewEncoder(w).Encode(dlDTOs); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
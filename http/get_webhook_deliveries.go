@@ -0,0 +1,46 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// WebhookDeliveryDTO struct is used in the response of GET /webhooks/{id}/deliveries
+type WebhookDeliveryDTO struct {
+	EventID    string          `json:"event_id"`
+	EventType  drone.EventType `json:"event_type"`
+	Attempt    int             `json:"attempt"`
+	StatusCode int             `json:"status_code,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Succeeded  bool            `json:"succeeded"`
+	At         time.Time       `json:"at"`
+}
+
+func (h *WebhookController) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	deliveries, err := h.store.Deliveries(r.Context(), tenantFromRequest(r), h.webhookIDFromRequest(r))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	deliveryDTOs := make([]WebhookDeliveryDTO, len(deliveries))
+	for i, d := range deliveries {
+		deliveryDTOs[i] = WebhookDeliveryDTO{
+			EventID:    d.EventID,
+			EventType:  d.EventType,
+			Attempt:    d.Attempt,
+			StatusCode: d.StatusCode,
+			Error:      d.Error,
+			Succeeded:  d.Succeeded,
+			At:         d.At,
+		}
+	}
+
+	if err := json.NewEncoder(w).Encode(deliveryDTOs); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
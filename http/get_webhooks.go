@@ -0,0 +1,29 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func (h *WebhookController) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.store.Subscriptions(r.Context(), tenantFromRequest(r))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	webhookDTOs := make([]WebhookDTO, len(subs))
+	for i, s := range subs {
+		webhookDTOs[i] = WebhookDTO{
+			ID:        s.ID,
+			URL:       s.URL,
+			Events:    s.EventTypes,
+			CreatedAt: s.CreatedAt,
+		}
+	}
+
+	if err := json.NewEncoder(w).Encode(webhookDTOs); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
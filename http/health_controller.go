@@ -0,0 +1,12 @@
+package http
+
+import "github.com/hsequeda/drone/health"
+
+// HealthController exposes the liveness and readiness of the server.
+type HealthController struct {
+	checker *health.Checker
+}
+
+func NewHealthController(checker *health.Checker) *HealthController {
+	return &HealthController{checker: checker}
+}
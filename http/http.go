@@ -5,15 +5,27 @@ import (
 )
 
 type DroneController struct {
-	storage       drone.Storage
-	maxUploadSize int64
-	uploadDir     string
+	storage        drone.Storage
+	locks          *drone.Locks
+	auditStore     drone.AuditStore
+	geofences      drone.GeofenceStore
+	policies       drone.Policies
+	link           *DroneLink
+	batteryHistory drone.BatteryHistory
+	maxUploadSize  int64
+	uploadDir      string
 }
 
-func NewHttpServer(storage drone.Storage, maxUploadSize int64, uploadDir string) *DroneController {
+func NewHttpServer(storage drone.Storage, locks *drone.Locks, auditStore drone.AuditStore, geofences drone.GeofenceStore, policies drone.Policies, link *DroneLink, batteryHistory drone.BatteryHistory, maxUploadSize int64, uploadDir string) *DroneController {
 	return &DroneController{
-		storage:       storage,
-		maxUploadSize: maxUploadSize,
-		uploadDir:     uploadDir,
+		storage:        storage,
+		locks:          locks,
+		auditStore:     auditStore,
+		geofences:      geofences,
+		policies:       policies,
+		link:           link,
+		batteryHistory: batteryHistory,
+		maxUploadSize:  maxUploadSize,
+		uploadDir:      uploadDir,
 	}
 }
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// DroneLinkKeyDTO struct is used in the response of POST /drone/{serial}/link-key.
+// NOTE: the key is only returned when issued.
+type DroneLinkKeyDTO struct {
+	LinkKey string `json:"link_key"`
+}
+
+// IssueDroneLinkKey issues the key a drone sends in the DroneKeyHeader of its
+// link, revoking the previous one. POST /drone/{serial}/link-key.
+func (h *DroneController) IssueDroneLinkKey(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "DroneController.IssueDroneLinkKey")
+	defer span.End()
+
+	unlock := h.locks.Lock(tenantFromRequest(r), droneSerialFromRequest(r))
+	defer unlock()
+
+	d, err := h.storage.Drone(r.Context(), tenantFromRequest(r), droneSerialFromRequest(r))
+	if err != nil {
+		if err == drone.ErrNotFound {
+			fail(w, r, err, http.StatusBadRequest)
+			return
+		}
+
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	before := d
+	key := d.IssueLinkKey()
+	if err := h.storage.SaveDrone(r.Context(), d); err != nil {
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	recordAudit(r.Context(), h.auditStore, drone.AuditLinkKey, before, d)
+
+	requestLogger(r).Info("drone link key issued")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(DroneLinkKeyDTO{LinkKey: key})
+}
@@ -0,0 +1,12 @@
+package http
+
+import "github.com/hsequeda/drone/scheduler"
+
+// JobController exposes the status of the periodic jobs of the server.
+type JobController struct {
+	scheduler *scheduler.Scheduler
+}
+
+func NewJobController(scheduler *scheduler.Scheduler) *JobController {
+	return &JobController{scheduler: scheduler}
+}
@@ -2,8 +2,10 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 
 	"github.com/hsequeda/drone/drone"
@@ -22,18 +24,28 @@ type LoadMedicationDTO struct {
 	Name   string `json:"name"`
 	Weight uint32 `json:"weight"`
 	Code   string `json:"code"`
+	// Handling are the special handlings of the medication (optional), see drone.HandlingClass.
+	Handling []drone.HandlingClass `json:"handling,omitempty"`
+	// Incompatible are the codes of the medications it can't share a drone with (optional).
+	Incompatible []string `json:"incompatible,omitempty"`
 }
 
 func (h *DroneController) LoadDrone(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "DroneController.LoadDrone")
+	defer span.End()
+
 	r.Body = http.MaxBytesReader(w, r.Body, h.maxUploadSize)
-	if err := r.ParseMultipartForm(h.maxUploadSize); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	_, parseSpan := startStep(r.Context(), "parse multipart form")
+	err := r.ParseMultipartForm(h.maxUploadSize)
+	endStep(parseSpan, err)
+	if err != nil {
+		fail(w, r, err, http.StatusBadRequest)
 		return
 	}
 
 	file, _, err := r.FormFile(formPicture)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		fail(w, r, err, http.StatusBadRequest)
 		return
 	}
 
@@ -42,58 +54,67 @@ func (h *DroneController) LoadDrone(w http.ResponseWriter, r *http.Request) {
 	contentTypeBuff := make([]byte, 512)
 	_, err = file.Read(contentTypeBuff)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("read content-type buffer: %v", err), http.StatusBadRequest)
+		fail(w, r, fmt.Errorf("read content-type buffer: %w", err), http.StatusBadRequest)
 		return
 	}
 	filetype := http.DetectContentType(contentTypeBuff)
 	if filetype != "image/jpeg" && filetype != "image/png" {
-		http.Error(w, "the provided file format is not allowed.", http.StatusBadRequest)
+		fail(w, r, errors.New("the provided file format is not allowed."), http.StatusBadRequest)
 		return
 	}
 
 	if _, err = file.Seek(0, io.SeekStart); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		fail(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
-	filename, err := h.saveFile(file)
+	filename, err := h.saveFile(r.Context(), tenantFromRequest(r), file)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		fail(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
 	encryptedDto := r.PostFormValue(formData)
 	var dto LoadMedicationDTO
 	if err = json.Unmarshal([]byte(encryptedDto), &dto); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		fail(w, r, err, http.StatusBadRequest)
 		return
 	}
 
-	meds, err := drone.NewMedication(dto.Name, dto.Weight, dto.Code, filename)
+	meds, err := drone.NewMedication(dto.Name, dto.Weight, dto.Code, filename, dto.Handling, dto.Incompatible)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		fail(w, r, err, validationStatus(err, http.StatusBadRequest))
+		return
 	}
 
-	droneSerial := h.droneSerialFromRequest(r)
-	d, err := h.storage.Drone(r.Context(), droneSerial)
+	unlock := h.locks.Lock(tenantFromRequest(r), droneSerialFromRequest(r))
+	defer unlock()
+
+	droneSerial := droneSerialFromRequest(r)
+	d, err := h.storage.Drone(r.Context(), tenantFromRequest(r), droneSerial)
 	if err != nil {
 		if err == drone.ErrNotFound {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			fail(w, r, err, http.StatusBadRequest)
 			return
 		}
 
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		fail(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
-	if err := d.AddMedications(meds); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	before := d
+	if err := d.AddMedications(h.policies.For(d.TenantID), meds); err != nil {
+		fail(w, r, err, http.StatusBadRequest)
 		return
 	}
 
 	if err := h.storage.SaveDrone(r.Context(), d); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		fail(w, r, err, http.StatusInternalServerError)
 		return
 	}
+
+	recordAudit(r.Context(), h.auditStore, drone.AuditLoad, before, d)
+
+	requestLogger(r).Info("medication loaded", slog.String("code", meds.Code), slog.Any("state", d.State))
 	_ = json.NewEncoder(w).Encode("success")
 }
@@ -0,0 +1,29 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/hsequeda/drone/logging"
+)
+
+// requestLogger returns the logger of the request with the tenant and drone serial (if any).
+func requestLogger(r *http.Request) *slog.Logger {
+	logger := logging.FromContext(r.Context()).With(slog.String("tenant_id", tenantFromRequest(r)))
+	if serial := droneSerialFromRequest(r); serial != "" {
+		logger = logger.With(slog.String("serial", serial))
+	}
+
+	return logger
+}
+
+// fail logs the cause of a failed request and writes it as the response.
+func fail(w http.ResponseWriter, r *http.Request, err error, status int) {
+	level := slog.LevelWarn
+	if status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+
+	requestLogger(r).LogAttrs(r.Context(), level, "request failed", slog.Int("status", status), slog.String("error", err.Error()))
+	writeError(w, err, status)
+}
@@ -0,0 +1,15 @@
+package http
+
+import (
+	"github.com/hsequeda/drone/drone"
+)
+
+// OrderController assigns the medication orders to the drones of the tenant.
+type OrderController struct {
+	assigner   *drone.Assigner
+	auditStore drone.AuditStore
+}
+
+func NewOrderController(assigner *drone.Assigner, auditStore drone.AuditStore) *OrderController {
+	return &OrderController{assigner: assigner, auditStore: auditStore}
+}
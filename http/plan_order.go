@@ -0,0 +1,115 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// PlannedLoadDTO struct is the part of the order planned for a drone.
+type PlannedLoadDTO struct {
+	Serial          string          `json:"serial"`
+	Model           drone.Model     `json:"model"`
+	WeightLimit     uint32          `json:"weight_limit"`
+	BatteryCapacity uint8           `json:"battery_capacity"`
+	ConsumedWeight  uint32          `json:"consumed_weight"`
+	PlannedWeight   uint32          `json:"planned_weight"`
+	Medications     []MedicationDTO `json:"medications"`
+	// ETA is the estimated arrival of the loaded drone (only in POST /orders/split).
+	ETA *ETADTO `json:"eta,omitempty"`
+}
+
+// OrderPlanDTO struct is used in the response of POST /orders/plan and POST /orders/split
+type OrderPlanDTO struct {
+	Complete   bool             `json:"complete"`
+	Drones     int              `json:"drones"`
+	Loads      []PlannedLoadDTO `json:"loads"`
+	Unassigned []MedicationDTO  `json:"unassigned"`
+}
+
+// PlanOrder returns the dry-run plan splitting the order across the drones, without loading them.
+func (h *OrderController) PlanOrder(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "OrderController.PlanOrder")
+	defer span.End()
+
+	o, status, err := h.orderFromRequest(r)
+	if err != nil {
+		fail(w, r, err, status)
+		return
+	}
+
+	plan, err := h.assigner.Plan(r.Context(), o)
+	if err != nil {
+		fail(w, r, err, assignmentErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(newOrderPlanDTO(plan, nil))
+}
+
+// SplitOrder splits the order across the drones as planned by PlanOrder and loads them.
+// NOTE: the plan is made again over the current drones, so it can differ from a previous dry run.
+func (h *OrderController) SplitOrder(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "OrderController.SplitOrder")
+	defer span.End()
+
+	o, status, err := h.orderFromRequest(r)
+	if err != nil {
+		fail(w, r, err, status)
+		return
+	}
+
+	plan, loaded, err := h.assigner.AssignPlan(r.Context(), o)
+	if err != nil {
+		var rollback *drone.RollbackError
+		if errors.As(err, &rollback) {
+			for _, l := range rollback.Reverted {
+				recordAudit(r.Context(), h.auditStore, drone.AuditLoad, l.Before, l.Loaded)
+				recordAudit(r.Context(), h.auditStore, drone.AuditRevert, l.Loaded, l.Reverted)
+			}
+		}
+
+		fail(w, r, err, assignmentErrorStatus(err))
+		return
+	}
+
+	for i, d := range loaded {
+		recordAudit(r.Context(), h.auditStore, drone.AuditLoad, plan.Loads[i].Drone, d)
+	}
+
+	requestLogger(r).Info("order split", slog.Int("drones", len(plan.Loads)), slog.Int("medications", len(o.Medications)), slog.Uint64("weight", uint64(o.Weight())))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(newOrderPlanDTO(plan, loaded))
+}
+
+// newOrderPlanDTO returns the DTO of the plan, with the ETA of the loaded drones (if any).
+func newOrderPlanDTO(plan drone.Plan, loaded []drone.Drone) OrderPlanDTO {
+	dto := OrderPlanDTO{
+		Complete:   plan.Complete(),
+		Drones:     len(plan.Loads),
+		Loads:      make([]PlannedLoadDTO, len(plan.Loads)),
+		Unassigned: newMedicationDTOs(plan.Unassigned),
+	}
+	for i, l := range plan.Loads {
+		dto.Loads[i] = PlannedLoadDTO{
+			Serial:          l.Drone.Serial,
+			Model:           l.Drone.Model,
+			WeightLimit:     l.Drone.WeightLimit,
+			BatteryCapacity: l.Drone.BatteryCapacity,
+			ConsumedWeight:  l.Drone.MedicationWeight(),
+			PlannedWeight:   l.Weight(),
+			Medications:     newMedicationDTOs(l.Medications),
+		}
+		if i < len(loaded) {
+			dto.Loads[i].ETA = newETADTO(loaded[i], time.Now().UTC())
+		}
+	}
+
+	return dto
+}
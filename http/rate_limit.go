@@ -0,0 +1,102 @@
+package http
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// rateLimitIdle is the time after which the bucket of a silent client is dropped.
+const rateLimitIdle = 10 * time.Minute
+
+// RateLimiter limits the requests of each client with a token bucket of
+// rps tokens per second and burst capacity.
+type RateLimiter struct {
+	limit rate.Limit
+	burst int
+	key   func(*http.Request) string
+
+	mu        sync.Mutex
+	clients   map[string]*rateClient
+	lastSweep time.Time
+}
+
+type rateClient struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewRateLimiter creates a RateLimiter identifying the clients by the key function.
+func NewRateLimiter(rps float64, burst int, key func(*http.Request) string) *RateLimiter {
+	return &RateLimiter{
+		limit:     rate.Limit(rps),
+		burst:     burst,
+		key:       key,
+		clients:   make(map[string]*rateClient),
+		lastSweep: time.Now(),
+	}
+}
+
+// Middleware rejects with 429 the requests of the clients over their limit.
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.allow(l.key(r), time.Now()) {
+			retryAfter := int(math.Ceil(1 / float64(l.limit)))
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (l *RateLimiter) allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > rateLimitIdle {
+		for k, c := range l.clients {
+			if now.Sub(c.lastSeen) > rateLimitIdle {
+				delete(l.clients, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	c, ok := l.clients[key]
+	if !ok {
+		c = &rateClient{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[key] = c
+	}
+	c.lastSeen = now
+
+	return c.limiter.AllowN(now, 1)
+}
+
+// ClientIP identifies the client by the IP address of the connection.
+// NOTE: set chi's RealIP before when the server is behind a trusted proxy.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// PrincipalKey identifies the client by its authenticated API key, falling
+// back to its IP address.
+func PrincipalKey(r *http.Request) string {
+	p, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		return "ip:" + ClientIP(r)
+	}
+
+	return "key:" + p.TenantID + "/" + p.Subject
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// RegisterDockDTO struct is the value passed in the body of POST /docks.
+type RegisterDockDTO struct {
+	Name     string          `json:"name"`
+	Location *CoordinatesDTO `json:"location"`
+	Capacity int             `json:"capacity"`
+	// ChargeRate is the battery percent a drone charges per minute.
+	ChargeRate float64 `json:"charge_rate"`
+}
+
+// DockDTO struct is used in the responses of /docks.
+type DockDTO struct {
+	ID         string         `json:"id"`
+	Name       string         `json:"name"`
+	Location   CoordinatesDTO `json:"location"`
+	Capacity   int            `json:"capacity"`
+	ChargeRate float64        `json:"charge_rate"`
+	// Docked is the number of drones in the dock, Charging the ones charging.
+	Docked   int `json:"docked"`
+	Charging int `json:"charging"`
+}
+
+func newDockDTO(d drone.Dock, docked []drone.Drone) DockDTO {
+	dto := DockDTO{
+		ID:         d.ID,
+		Name:       d.Name,
+		Location:   CoordinatesDTO{Latitude: d.Location.Latitude, Longitude: d.Location.Longitude},
+		Capacity:   d.Capacity,
+		ChargeRate: d.ChargeRate,
+		Docked:     len(docked),
+	}
+	for _, dr := range docked {
+		if dr.Charging() {
+			dto.Charging++
+		}
+	}
+
+	return dto
+}
+
+// RegisterDock creates a charging dock, POST /docks.
+func (h *DockController) RegisterDock(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "DockController.RegisterDock")
+	defer span.End()
+
+	dto := new(RegisterDockDTO)
+	if status, err := decodeJSON(r, dto); err != nil {
+		fail(w, r, err, status)
+		return
+	}
+
+	if dto.Location == nil {
+		fail(w, r, errors.New("location is required"), http.StatusBadRequest)
+		return
+	}
+
+	d, err := drone.NewDock(tenantFromRequest(r), dto.Name, dto.Location.coordinates(), dto.Capacity, dto.ChargeRate)
+	if err != nil {
+		fail(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.store.SaveDock(r.Context(), d); err != nil {
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	requestLogger(r).Info("dock registered", slog.String("dock_id", d.ID), slog.Int("capacity", d.Capacity))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(newDockDTO(d, nil))
+}
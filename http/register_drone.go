@@ -2,6 +2,7 @@ package http
 
 import (
 	"encoding/json"
+	"log/slog"
 	"net/http"
 
 	"github.com/hsequeda/drone/drone"
@@ -13,25 +14,56 @@ type RegisterDroneDTO struct {
 	Model       drone.Model `json:"model"`
 	WeightLimit uint32      `json:"weight_limit"`
 	Battery     uint8       `json:"battery"`
+	// Capabilities are the capabilities of the drone (optional) on top of the
+	// ones of its model, see drone.Capability.
+	Capabilities []drone.Capability `json:"capabilities,omitempty"`
+	// Home is the base of the drone (optional), required to dispatch it.
+	Home *CoordinatesDTO `json:"home,omitempty"`
 }
 
 func (h *DroneController) RegisterADrone(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "DroneController.RegisterADrone")
+	defer span.End()
+
 	dto := new(RegisterDroneDTO)
-	if err := json.NewDecoder(r.Body).Decode(dto); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if status, err := decodeJSON(r, dto); err != nil {
+		fail(w, r, err, status)
 		return
 	}
 
-	d, err := drone.NewDrone(dto.Serial, dto.Model, dto.WeightLimit, dto.Battery)
+	tenantID := tenantFromRequest(r)
+	d, err := drone.NewDrone(h.policies.For(tenantID), tenantID, dto.Serial, dto.Model, dto.WeightLimit, dto.Battery, dto.Capabilities...)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		fail(w, r, err, validationStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	if dto.Home != nil {
+		if err := d.SetHome(dto.Home.coordinates()); err != nil {
+			fail(w, r, err, http.StatusBadRequest)
+			return
+		}
+	}
+
+	unlock := h.locks.Lock(tenantID, d.Serial)
+	defer unlock()
+
+	// NOTE: a registration never replaces a drone, its state, loads and keys would be lost.
+	if _, err := h.storage.Drone(r.Context(), tenantID, d.Serial); err == nil {
+		fail(w, r, drone.ErrAlreadyRegistered, http.StatusConflict)
+		return
+	} else if err != drone.ErrNotFound {
+		fail(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
 	if err := h.storage.SaveDrone(r.Context(), d); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		fail(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
+	recordAudit(r.Context(), h.auditStore, drone.AuditRegister, drone.Drone{}, d)
+
+	requestLogger(r).Info("drone registered", slog.String("serial", d.Serial))
 	_ = json.NewEncoder(w).Encode("success")
 }
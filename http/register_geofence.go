@@ -0,0 +1,45 @@
+package http
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// RegisterGeofence creates a no-fly zone from a GeoJSON Feature, POST /geofences.
+// NOTE: the drones already routed keep their route, the dispatch rejects the
+// routes crossing the new zone.
+func (h *GeofenceController) RegisterGeofence(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "GeofenceController.RegisterGeofence")
+	defer span.End()
+
+	dto := new(GeofenceDTO)
+	if status, err := decodeJSON(r, dto); err != nil {
+		fail(w, r, err, status)
+		return
+	}
+
+	polygon, err := dto.polygon()
+	if err != nil {
+		fail(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	g, err := drone.NewGeofence(tenantFromRequest(r), dto.Properties.Name, polygon)
+	if err != nil {
+		fail(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.store.SaveGeofence(r.Context(), g); err != nil {
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	requestLogger(r).Info("geofence registered", slog.String("geofence_id", g.ID), slog.Int("vertices", len(g.Polygon)))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(newGeofenceDTO(g))
+}
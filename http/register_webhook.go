@@ -0,0 +1,55 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/webhook"
+)
+
+// RegisterWebhookDTO struct is the value passed in the body of POST /webhooks.
+type RegisterWebhookDTO struct {
+	URL    string            `json:"url"`
+	Secret string            `json:"secret"`
+	Events []drone.EventType `json:"events"`
+}
+
+// WebhookDTO struct is used in the responses of /webhooks.
+// NOTE: the secret is only returned when the webhook is registered.
+type WebhookDTO struct {
+	ID        string            `json:"id"`
+	URL       string            `json:"url"`
+	Secret    string            `json:"secret,omitempty"`
+	Events    []drone.EventType `json:"events"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+func (h *WebhookController) RegisterWebhook(w http.ResponseWriter, r *http.Request) {
+	dto := new(RegisterWebhookDTO)
+	if status, err := decodeJSON(r, dto); err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	s, err := webhook.NewSubscription(h.egress, tenantFromRequest(r), dto.URL, dto.Secret, dto.Events)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.store.SaveSubscription(r.Context(), s); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(WebhookDTO{
+		ID:        s.ID,
+		URL:       s.URL,
+		Secret:    s.Secret,
+		Events:    s.EventTypes,
+		CreatedAt: s.CreatedAt,
+	})
+}
@@ -0,0 +1,77 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// DroneCommandDTO struct is the value passed in the body of POST /drone/{serial}/commands.
+type DroneCommandDTO struct {
+	Command drone.Command `json:"command"`
+}
+
+// SentDroneCommandDTO struct is used in the response of POST /drone/{serial}/commands
+type SentDroneCommandDTO struct {
+	ID      string        `json:"id"`
+	Command drone.Command `json:"command"`
+}
+
+func (h *DroneController) SendDroneCommand(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "DroneController.SendDroneCommand")
+	defer span.End()
+
+	dto := new(DroneCommandDTO)
+	if status, err := decodeJSON(r, dto); err != nil {
+		fail(w, r, err, status)
+		return
+	}
+
+	tenantID, droneSerial := tenantFromRequest(r), droneSerialFromRequest(r)
+	d, err := h.storage.Drone(r.Context(), tenantID, droneSerial)
+	if err != nil {
+		if err == drone.ErrNotFound {
+			fail(w, r, err, http.StatusBadRequest)
+			return
+		}
+
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	if err := d.CanExecute(h.policies.For(tenantID), dto.Command); err != nil {
+		fail(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if dto.Command == drone.Dispatch {
+		zones, err := h.geofences.Geofences(r.Context(), tenantID)
+		if err != nil {
+			fail(w, r, err, http.StatusInternalServerError)
+			return
+		}
+
+		if err := d.CheckRoute(zones); err != nil {
+			fail(w, r, err, http.StatusBadRequest)
+			return
+		}
+	}
+
+	id, err := h.link.Send(tenantID, droneSerial, dto.Command)
+	if err != nil {
+		if errors.Is(err, ErrDroneNotConnected) {
+			fail(w, r, err, http.StatusConflict)
+			return
+		}
+
+		fail(w, r, err, http.StatusServiceUnavailable)
+		return
+	}
+
+	requestLogger(r).Info("command sent", slog.String("command", string(dto.Command)), slog.String("id", id))
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(SentDroneCommandDTO{ID: id, Command: dto.Command})
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// SetDroneCapabilitiesDTO struct is the value passed in the body of PUT /drone/{serial}/capabilities.
+type SetDroneCapabilitiesDTO struct {
+	// Capabilities replace the capabilities declared by the drone, see drone.Capability.
+	Capabilities []drone.Capability `json:"capabilities"`
+}
+
+// DroneCapabilitiesDTO struct is used in the response of PUT /drone/{serial}/capabilities.
+type DroneCapabilitiesDTO struct {
+	// Capabilities are the capabilities of the drone and its model.
+	Capabilities []drone.Capability `json:"capabilities"`
+}
+
+// SetDroneCapabilities replaces the capabilities declared by a drone, PUT /drone/{serial}/capabilities.
+func (h *DroneController) SetDroneCapabilities(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "DroneController.SetDroneCapabilities")
+	defer span.End()
+
+	dto := new(SetDroneCapabilitiesDTO)
+	if status, err := decodeJSON(r, dto); err != nil {
+		fail(w, r, err, status)
+		return
+	}
+
+	unlock := h.locks.Lock(tenantFromRequest(r), droneSerialFromRequest(r))
+	defer unlock()
+
+	d, err := h.storage.Drone(r.Context(), tenantFromRequest(r), droneSerialFromRequest(r))
+	if err != nil {
+		if err == drone.ErrNotFound {
+			fail(w, r, err, http.StatusBadRequest)
+			return
+		}
+
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	before := d
+	p := h.policies.For(d.TenantID)
+	if err := d.SetCapabilities(p, dto.Capabilities); err != nil {
+		fail(w, r, err, validationStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	if err := h.storage.SaveDrone(r.Context(), d); err != nil {
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	recordAudit(r.Context(), h.auditStore, drone.AuditCapabilities, before, d)
+
+	requestLogger(r).Info("drone capabilities set", slog.Any("capabilities", d.Capabilities))
+	_ = json.NewEncoder(w).Encode(DroneCapabilitiesDTO{Capabilities: d.AllCapabilities(p)})
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// SetDroneHome moves the base of the drone, PUT /drone/{serial}/home.
+func (h *DroneController) SetDroneHome(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "DroneController.SetDroneHome")
+	defer span.End()
+
+	h.setLocation(w, r, "home", (*drone.Drone).SetHome)
+}
+
+// SetDroneDestination sets the destination of the delivery, PUT /drone/{serial}/destination.
+func (h *DroneController) SetDroneDestination(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "DroneController.SetDroneDestination")
+	defer span.End()
+
+	h.setLocation(w, r, "destination", (*drone.Drone).SetDestination)
+}
+
+func (h *DroneController) setLocation(w http.ResponseWriter, r *http.Request, name string, set func(*drone.Drone, drone.Coordinates) error) {
+	dto := new(CoordinatesDTO)
+	if status, err := decodeJSON(r, dto); err != nil {
+		fail(w, r, err, status)
+		return
+	}
+
+	unlock := h.locks.Lock(tenantFromRequest(r), droneSerialFromRequest(r))
+	defer unlock()
+
+	d, err := h.storage.Drone(r.Context(), tenantFromRequest(r), droneSerialFromRequest(r))
+	if err != nil {
+		if err == drone.ErrNotFound {
+			fail(w, r, err, http.StatusBadRequest)
+			return
+		}
+
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	before := d
+	if err := set(&d, dto.coordinates()); err != nil {
+		fail(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	zones, err := h.geofences.Geofences(r.Context(), d.TenantID)
+	if err != nil {
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	if err := d.Route(zones); err != nil {
+		fail(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.storage.SaveDrone(r.Context(), d); err != nil {
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	recordAudit(r.Context(), h.auditStore, drone.AuditLocation, before, d)
+
+	requestLogger(r).Info("drone "+name+" set", slog.Float64("latitude", dto.Latitude), slog.Float64("longitude", dto.Longitude))
+	_ = json.NewEncoder(w).Encode("success")
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// SetDroneMaintenanceDTO struct is the value passed in the body of PUT /drone/{serial}/maintenance.
+type SetDroneMaintenanceDTO struct {
+	// Grounded takes the drone out of service (true) or records its service and returns it to service (false).
+	Grounded *bool  `json:"grounded"`
+	Reason   string `json:"reason"`
+}
+
+// MaintenanceDTO struct is the service record of a drone.
+type MaintenanceDTO struct {
+	Grounded                bool       `json:"grounded"`
+	Reason                  string     `json:"reason,omitempty"`
+	GroundedAt              *time.Time `json:"grounded_at,omitempty"`
+	Cycles                  uint32     `json:"cycles"`
+	FlightHours             float64    `json:"flight_hours"`
+	CyclesSinceService      uint32     `json:"cycles_since_service"`
+	FlightHoursSinceService float64    `json:"flight_hours_since_service"`
+	LastServiceAt           *time.Time `json:"last_service_at,omitempty"`
+}
+
+func newMaintenanceDTO(m drone.Maintenance) MaintenanceDTO {
+	dto := MaintenanceDTO{
+		Grounded:                m.Grounded,
+		Reason:                  m.Reason,
+		Cycles:                  m.Cycles,
+		FlightHours:             m.FlightTime.Hours(),
+		CyclesSinceService:      m.CyclesSinceService,
+		FlightHoursSinceService: m.FlightTimeSinceService.Hours(),
+	}
+	if !m.GroundedAt.IsZero() {
+		dto.GroundedAt = &m.GroundedAt
+	}
+	if !m.LastServiceAt.IsZero() {
+		dto.LastServiceAt = &m.LastServiceAt
+	}
+
+	return dto
+}
+
+// SetDroneMaintenance grounds a drone or returns it to service, PUT /drone/{serial}/maintenance.
+func (h *DroneController) SetDroneMaintenance(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "DroneController.SetDroneMaintenance")
+	defer span.End()
+
+	dto := new(SetDroneMaintenanceDTO)
+	if status, err := decodeJSON(r, dto); err != nil {
+		fail(w, r, err, status)
+		return
+	}
+
+	if dto.Grounded == nil {
+		fail(w, r, errors.New("grounded is required"), http.StatusBadRequest)
+		return
+	}
+
+	if *dto.Grounded && dto.Reason == "" {
+		fail(w, r, errors.New("a reason is required to ground a drone"), http.StatusBadRequest)
+		return
+	}
+
+	unlock := h.locks.Lock(tenantFromRequest(r), droneSerialFromRequest(r))
+	defer unlock()
+
+	d, err := h.storage.Drone(r.Context(), tenantFromRequest(r), droneSerialFromRequest(r))
+	if err != nil {
+		if err == drone.ErrNotFound {
+			fail(w, r, err, http.StatusBadRequest)
+			return
+		}
+
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	before := d
+	if *dto.Grounded {
+		d.Ground(dto.Reason, time.Now().UTC())
+	} else {
+		d.ReturnToService(time.Now().UTC())
+	}
+
+	if err := h.storage.SaveDrone(r.Context(), d); err != nil {
+		fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	recordAudit(r.Context(), h.auditStore, drone.AuditMaintenance, before, d)
+
+	requestLogger(r).Info("drone maintenance set", slog.Bool("grounded", d.Maintenance.Grounded), slog.String("reason", d.Maintenance.Reason))
+	_ = json.NewEncoder(w).Encode(newMaintenanceDTO(d.Maintenance))
+}
@@ -0,0 +1,12 @@
+package http
+
+import (
+	"net/http"
+)
+
+// tenantFromRequest extracts the authenticated tenant from the request context.
+// NOTE: The route must be protected by the Authenticate middleware.
+func tenantFromRequest(r *http.Request) string {
+	p, _ := PrincipalFromContext(r.Context())
+	return p.TenantID
+}
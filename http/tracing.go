@@ -0,0 +1,40 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/hsequeda/drone/http")
+
+// startSpan starts the span of a handler and replaces the request context with
+// the span context, so the storage spans are its children.
+func startSpan(r *http.Request, name string) (*http.Request, trace.Span) {
+	ctx, span := tracer.Start(r.Context(), name)
+	if serial := chi.URLParam(r, "serial"); serial != "" {
+		span.SetAttributes(attribute.String("drone.serial", serial))
+	}
+
+	return r.WithContext(ctx), span
+}
+
+// startStep starts the span of a step inside a handler.
+func startStep(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name)
+}
+
+// endStep ends the span of a step, recording the error (if any).
+func endStep(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
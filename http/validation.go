@@ -0,0 +1,56 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// ValidationErrorDTO is the body of a 422 response, with the invalid fields keyed by name.
+type ValidationErrorDTO struct {
+	Error  string                   `json:"error"`
+	Fields map[string]FieldErrorDTO `json:"fields"`
+}
+
+type FieldErrorDTO struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func newValidationErrorDTO(verr *drone.ValidationError) ValidationErrorDTO {
+	dto := ValidationErrorDTO{Error: "validation failed", Fields: make(map[string]FieldErrorDTO, len(verr.Fields))}
+	for _, f := range verr.Fields {
+		// NOTE: the first error of a field is the one reported.
+		if _, ok := dto.Fields[f.Field]; !ok {
+			dto.Fields[f.Field] = FieldErrorDTO{Code: f.Code, Message: f.Message}
+		}
+	}
+
+	return dto
+}
+
+// validationStatus returns 422 for a *drone.ValidationError, else the status.
+func validationStatus(err error, status int) int {
+	var verr *drone.ValidationError
+	if errors.As(err, &verr) {
+		return http.StatusUnprocessableEntity
+	}
+
+	return status
+}
+
+// writeError writes the error as the response, as a ValidationErrorDTO for a
+// *drone.ValidationError and as plain text otherwise.
+func writeError(w http.ResponseWriter, err error, status int) {
+	var verr *drone.ValidationError
+	if !errors.As(err, &verr) {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(newValidationErrorDTO(verr))
+}
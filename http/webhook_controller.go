@@ -0,0 +1,22 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hsequeda/drone/webhook"
+)
+
+type WebhookController struct {
+	store  webhook.Store
+	egress webhook.EgressPolicy
+}
+
+func NewWebhookController(store webhook.Store, egress webhook.EgressPolicy) *WebhookController {
+	return &WebhookController{store: store, egress: egress}
+}
+
+// webhookIDFromRequest extracts the subscription ID from the path parameters.
+func (h *WebhookController) webhookIDFromRequest(r *http.Request) string {
+	return chi.URLParam(r, "id")
+}
@@ -0,0 +1,101 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/logging"
+	"github.com/hsequeda/drone/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// records decodes the JSON records written to buf.
+func records(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var recs []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var rec map[string]any
+		require.NoError(t, dec.Decode(&rec))
+		recs = append(recs, rec)
+	}
+
+	return recs
+}
+
+func TestParseLevel(t *testing.T) {
+	for s, expected := range map[string]slog.Level{
+		"":      slog.LevelInfo,
+		"debug": slog.LevelDebug,
+		"INFO":  slog.LevelInfo,
+		"warn":  slog.LevelWarn,
+		"error": slog.LevelError,
+	} {
+		level, err := logging.ParseLevel(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, expected, level, s)
+	}
+
+	_, err := logging.ParseLevel("verbose")
+	assert.Error(t, err)
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, slog.Default(), logging.FromContext(context.Background()))
+
+	logger := logging.New(&bytes.Buffer{}, slog.LevelInfo)
+	assert.Equal(t, logger, logging.FromContext(logging.NewContext(context.Background(), logger)))
+}
+
+func TestRequestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID, logging.RequestLogger(logging.New(&buf, slog.LevelInfo)))
+	r.Get("/drone/{serial}", func(w http.ResponseWriter, r *http.Request) {
+		logging.FromContext(r.Context()).Info("handling")
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/drone/1", nil))
+
+	recs := records(t, &buf)
+	require.Len(t, recs, 2)
+	assert.Equal(t, "handling", recs[0]["msg"])
+	assert.NotEmpty(t, recs[0]["request_id"])
+	assert.Equal(t, recs[0]["request_id"], recs[1]["request_id"])
+	assert.Equal(t, "request served", recs[1]["msg"])
+	assert.Equal(t, "ERROR", recs[1]["level"])
+	assert.Equal(t, "/drone/{serial}", recs[1]["route"])
+	assert.Equal(t, float64(http.StatusInternalServerError), recs[1]["status"])
+}
+
+func TestInstrumentStorage(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := logging.NewContext(context.Background(), logging.New(&buf, slog.LevelDebug))
+	st := logging.InstrumentStorage(storage.NewInMemory(), "in_memory")
+
+	require.NoError(t, st.SaveDrone(ctx, drone.Drone{TenantID: "hospital-a", Serial: "1", State: drone.Idle}))
+	_, err := st.Drone(ctx, "hospital-a", "2")
+	require.ErrorIs(t, err, drone.ErrNotFound)
+
+	recs := records(t, &buf)
+	require.Len(t, recs, 2)
+	assert.Equal(t, "DEBUG", recs[0]["level"])
+	assert.Equal(t, "save_drone", recs[0]["operation"])
+	assert.Equal(t, "in_memory", recs[0]["backend"])
+	assert.Equal(t, "1", recs[0]["serial"])
+	assert.Nil(t, recs[0]["error"])
+
+	assert.Equal(t, "DEBUG", recs[1]["level"])
+	assert.Equal(t, "drone", recs[1]["operation"])
+	assert.Equal(t, drone.ErrNotFound.Error(), recs[1]["error"])
+}
@@ -0,0 +1,191 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/storage"
+	"github.com/hsequeda/drone/webhook"
+)
+
+var _ storage.Backend = (*Storage)(nil)
+
+// Storage is a storage.Backend logging its operations with the logger of the context:
+// the failed ones as errors and the rest at debug level.
+type Storage struct {
+	next    storage.Backend
+	backend string
+}
+
+// InstrumentStorage wraps the storage.Backend, backend is logged in every record.
+func InstrumentStorage(next storage.Backend, backend string) *Storage {
+	return &Storage{next: next, backend: backend}
+}
+
+// log logs an operation started at start.
+func (s *Storage) log(ctx context.Context, operation string, start time.Time, err error, attrs ...slog.Attr) {
+	level := slog.LevelDebug
+	attrs = append(attrs,
+		slog.String("backend", s.backend),
+		slog.String("operation", operation),
+		slog.Duration("duration", time.Since(start)),
+	)
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		if !storage.IsNotFound(err) {
+			level = slog.LevelError
+		}
+	}
+
+	FromContext(ctx).LogAttrs(ctx, level, "storage operation", attrs...)
+}
+
+func (s *Storage) Drone(ctx context.Context, tenantID, serial string) (d drone.Drone, err error) {
+	defer func(start time.Time) {
+		s.log(ctx, "drone", start, err, slog.String("tenant_id", tenantID), slog.String("serial", serial))
+	}(time.Now())
+	return s.next.Drone(ctx, tenantID, serial)
+}
+
+func (s *Storage) Drones(ctx context.Context, tenantID string) (drones []drone.Drone, err error) {
+	defer func(start time.Time) { s.log(ctx, "drones", start, err, slog.String("tenant_id", tenantID)) }(time.Now())
+	return s.next.Drones(ctx, tenantID)
+}
+
+func (s *Storage) AllDrones(ctx context.Context) (drones []drone.Drone, err error) {
+	defer func(start time.Time) { s.log(ctx, "all_drones", start, err) }(time.Now())
+	return s.next.AllDrones(ctx)
+}
+
+func (s *Storage) SaveDrone(ctx context.Context, d drone.Drone) (err error) {
+	defer func(start time.Time) {
+		s.log(ctx, "save_drone", start, err, slog.String("tenant_id", d.TenantID), slog.String("serial", d.Serial))
+	}(time.Now())
+	return s.next.SaveDrone(ctx, d)
+}
+
+func (s *Storage) PendingEvents(ctx context.Context, limit int) (events []drone.Event, err error) {
+	defer func(start time.Time) { s.log(ctx, "pending_events", start, err) }(time.Now())
+	return s.next.PendingEvents(ctx, limit)
+}
+
+func (s *Storage) MarkDispatched(ctx context.Context, ids ...string) (err error) {
+	defer func(start time.Time) { s.log(ctx, "mark_dispatched", start, err) }(time.Now())
+	return s.next.MarkDispatched(ctx, ids...)
+}
+
+func (s *Storage) SaveSubscription(ctx context.Context, sub webhook.Subscription) (err error) {
+	defer func(start time.Time) { s.log(ctx, "save_subscription", start, err) }(time.Now())
+	return s.next.SaveSubscription(ctx, sub)
+}
+
+func (s *Storage) Subscriptions(ctx context.Context, tenantID string) (subs []webhook.Subscription, err error) {
+	defer func(start time.Time) { s.log(ctx, "subscriptions", start, err, slog.String("tenant_id", tenantID)) }(time.Now())
+	return s.next.Subscriptions(ctx, tenantID)
+}
+
+func (s *Storage) DeleteSubscription(ctx context.Context, tenantID, id string) (err error) {
+	defer func(start time.Time) {
+		s.log(ctx, "delete_subscription", start, err, slog.String("tenant_id", tenantID))
+	}(time.Now())
+	return s.next.DeleteSubscription(ctx, tenantID, id)
+}
+
+func (s *Storage) AppendDelivery(ctx context.Context, d webhook.Delivery) (err error) {
+	defer func(start time.Time) { s.log(ctx, "append_delivery", start, err) }(time.Now())
+	return s.next.AppendDelivery(ctx, d)
+}
+
+func (s *Storage) Deliveries(ctx context.Context, tenantID, subscriptionID string) (deliveries []webhook.Delivery, err error) {
+	defer func(start time.Time) { s.log(ctx, "deliveries", start, err, slog.String("tenant_id", tenantID)) }(time.Now())
+	return s.next.Deliveries(ctx, tenantID, subscriptionID)
+}
+
+func (s *Storage) AppendDeadLetter(ctx context.Context, dl webhook.DeadLetter) (err error) {
+	defer func(start time.Time) { s.log(ctx, "append_dead_letter", start, err) }(time.Now())
+	return s.next.AppendDeadLetter(ctx, dl)
+}
+
+func (s *Storage) DeadLetters(ctx context.Context, tenantID string) (dls []webhook.DeadLetter, err error) {
+	defer func(start time.Time) { s.log(ctx, "dead_letters", start, err, slog.String("tenant_id", tenantID)) }(time.Now())
+	return s.next.DeadLetters(ctx, tenantID)
+}
+
+func (s *Storage) SavePendingDelivery(ctx context.Context, p webhook.PendingDelivery) (err error) {
+	defer func(start time.Time) {
+		s.log(ctx, "save_pending_delivery", start, err, slog.String("tenant_id", p.TenantID))
+	}(time.Now())
+	return s.next.SavePendingDelivery(ctx, p)
+}
+
+func (s *Storage) PendingDeliveries(ctx context.Context) (pending []webhook.PendingDelivery, err error) {
+	defer func(start time.Time) { s.log(ctx, "pending_deliveries", start, err) }(time.Now())
+	return s.next.PendingDeliveries(ctx)
+}
+
+func (s *Storage) DeletePendingDelivery(ctx context.Context, id string) (err error) {
+	defer func(start time.Time) { s.log(ctx, "delete_pending_delivery", start, err) }(time.Now())
+	return s.next.DeletePendingDelivery(ctx, id)
+}
+
+func (s *Storage) SaveGeofence(ctx context.Context, g drone.Geofence) (err error) {
+	defer func(start time.Time) {
+		s.log(ctx, "save_geofence", start, err, slog.String("tenant_id", g.TenantID), slog.String("geofence_id", g.ID))
+	}(time.Now())
+	return s.next.SaveGeofence(ctx, g)
+}
+
+func (s *Storage) Geofences(ctx context.Context, tenantID string) (zones []drone.Geofence, err error) {
+	defer func(start time.Time) { s.log(ctx, "geofences", start, err, slog.String("tenant_id", tenantID)) }(time.Now())
+	return s.next.Geofences(ctx, tenantID)
+}
+
+func (s *Storage) DeleteGeofence(ctx context.Context, tenantID, id string) (err error) {
+	defer func(start time.Time) {
+		s.log(ctx, "delete_geofence", start, err, slog.String("tenant_id", tenantID), slog.String("geofence_id", id))
+	}(time.Now())
+	return s.next.DeleteGeofence(ctx, tenantID, id)
+}
+
+func (s *Storage) SaveDock(ctx context.Context, d drone.Dock) (err error) {
+	defer func(start time.Time) {
+		s.log(ctx, "save_dock", start, err, slog.String("tenant_id", d.TenantID), slog.String("dock_id", d.ID))
+	}(time.Now())
+	return s.next.SaveDock(ctx, d)
+}
+
+func (s *Storage) Dock(ctx context.Context, tenantID, id string) (d drone.Dock, err error) {
+	defer func(start time.Time) {
+		s.log(ctx, "dock", start, err, slog.String("tenant_id", tenantID), slog.String("dock_id", id))
+	}(time.Now())
+	return s.next.Dock(ctx, tenantID, id)
+}
+
+func (s *Storage) Docks(ctx context.Context, tenantID string) (docks []drone.Dock, err error) {
+	defer func(start time.Time) { s.log(ctx, "docks", start, err, slog.String("tenant_id", tenantID)) }(time.Now())
+	return s.next.Docks(ctx, tenantID)
+}
+
+func (s *Storage) DeleteDock(ctx context.Context, tenantID, id string) (err error) {
+	defer func(start time.Time) {
+		s.log(ctx, "delete_dock", start, err, slog.String("tenant_id", tenantID), slog.String("dock_id", id))
+	}(time.Now())
+	return s.next.DeleteDock(ctx, tenantID, id)
+}
+
+func (s *Storage) SaveChargingSession(ctx context.Context, cs drone.ChargingSession) (err error) {
+	defer func(start time.Time) {
+		s.log(ctx, "save_charging_session", start, err,
+			slog.String("tenant_id", cs.TenantID), slog.String("dock_id", cs.DockID), slog.String("serial", cs.Serial))
+	}(time.Now())
+	return s.next.SaveChargingSession(ctx, cs)
+}
+
+func (s *Storage) ChargingSessions(ctx context.Context, tenantID, dockID string) (sessions []drone.ChargingSession, err error) {
+	defer func(start time.Time) {
+		s.log(ctx, "charging_sessions", start, err, slog.String("tenant_id", tenantID), slog.String("dock_id", dockID))
+	}(time.Now())
+	return s.next.ChargingSessions(ctx, tenantID, dockID)
+}
@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// batteryBuckets are the upper bounds of the battery level distribution.
+var batteryBuckets = []float64{10, 20, 25, 30, 40, 50, 60, 70, 80, 90, 100}
+
+// FleetCollector computes the fleet metrics from the drone.Storage on each scrape.
+type FleetCollector struct {
+	storage drone.Storage
+	timeout time.Duration
+
+	drones       *prometheus.Desc
+	dronesModel  *prometheus.Desc
+	battery      *prometheus.Desc
+	loadedWeight *prometheus.Desc
+}
+
+// NewFleetCollector builds a FleetCollector, timeout bounds the storage query.
+func NewFleetCollector(storage drone.Storage, timeout time.Duration) *FleetCollector {
+	return &FleetCollector{
+		storage: storage,
+		timeout: timeout,
+		drones: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "fleet", "drones"),
+			"Number of drones by state.",
+			[]string{"tenant", "state"}, nil,
+		),
+		dronesModel: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "fleet", "drones_by_model"),
+			"Number of drones by model.",
+			[]string{"tenant", "model"}, nil,
+		),
+		battery: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "fleet", "battery_level_percent"),
+			"Distribution of the battery level of the drones.",
+			[]string{"tenant"}, nil,
+		),
+		loadedWeight: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "fleet", "loaded_weight_grams"),
+			"Weight of the medications loaded in the drones.",
+			[]string{"tenant"}, nil,
+		),
+	}
+}
+
+func (c *FleetCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.drones
+	ch <- c.dronesModel
+	ch <- c.battery
+	ch <- c.loadedWeight
+}
+
+// tenantFleet aggregates the drones of a tenant.
+type tenantFleet struct {
+	byState      map[drone.State]int
+	byModel      map[drone.Model]int
+	battery      map[float64]uint64
+	batteryCount uint64
+	batterySum   float64
+	loadedWeight uint64
+}
+
+func (c *FleetCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	drones, err := c.storage.AllDrones(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.drones, err)
+		return
+	}
+
+	fleets := make(map[string]*tenantFleet)
+	for _, d := range drones {
+		f, ok := fleets[d.TenantID]
+		if !ok {
+			f = &tenantFleet{
+				byState: make(map[drone.State]int),
+				byModel: make(map[drone.Model]int),
+				battery: make(map[float64]uint64, len(batteryBuckets)),
+			}
+			for _, b := range batteryBuckets {
+				f.battery[b] = 0
+			}
+			fleets[d.TenantID] = f
+		}
+
+		f.byState[d.State]++
+		f.byModel[d.Model]++
+		f.loadedWeight += uint64(d.MedicationWeight())
+		f.batteryCount++
+		f.batterySum += float64(d.BatteryCapacity)
+		for _, b := range batteryBuckets {
+			if float64(d.BatteryCapacity) <= b {
+				f.battery[b]++
+			}
+		}
+	}
+
+	for tenant, f := range fleets {
+		for s := drone.Idle; s <= drone.Returning; s++ {
+			ch <- prometheus.MustNewConstMetric(c.drones, prometheus.GaugeValue, float64(f.byState[s]), tenant, s.String())
+		}
+
+		for m := drone.Lightweight; m <= drone.Heavyweight; m++ {
+			ch <- prometheus.MustNewConstMetric(c.dronesModel, prometheus.GaugeValue, float64(f.byModel[m]), tenant, m.String())
+		}
+
+		ch <- prometheus.MustNewConstHistogram(c.battery, f.batteryCount, f.batterySum, f.battery, tenant)
+		ch <- prometheus.MustNewConstMetric(c.loadedWeight, prometheus.GaugeValue, float64(f.loadedWeight), tenant)
+	}
+}
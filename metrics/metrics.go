@@ -0,0 +1,75 @@
+// Package metrics exposes the Prometheus metrics of the service.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "drone"
+
+// Metrics holds the collectors of the HTTP requests and storage operations.
+type Metrics struct {
+	httpDuration    *prometheus.HistogramVec
+	storageDuration *prometheus.HistogramVec
+}
+
+// New builds the Metrics and registers them in reg.
+func New(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of the HTTP requests by route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		storageDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "storage",
+			Name:      "operation_duration_seconds",
+			Help:      "Duration of the storage operations by backend.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"backend", "operation", "result"}),
+	}
+
+	reg.MustRegister(m.httpDuration, m.storageDuration)
+	return m
+}
+
+// Middleware observes the duration and status of the requests, labelled by
+// their chi route pattern (unmatched requests are labelled as "unmatched").
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		start := time.Now()
+		next.ServeHTTP(ww, r)
+
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		m.httpDuration.WithLabelValues(r.Method, route, strconv.Itoa(status)).Observe(time.Since(start).Seconds())
+	})
+}
+
+// observe records the duration of a storage operation started at start.
+func (m *Metrics) observe(backend, operation string, start time.Time, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+
+	m.storageDuration.WithLabelValues(backend, operation, result).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,132 @@
+package metrics_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/metrics"
+	"github.com/hsequeda/drone/storage"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := metrics.New(reg)
+	r := chi.NewRouter()
+	r.Use(m.Middleware)
+	r.Get("/drone/{serial}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	r.Get("/drones", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("[]"))
+	})
+
+	for _, path := range []string{"/drone/1", "/drone/2", "/drones", "/unknown"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	count, err := testutil.GatherAndCount(reg, "drone_http_request_duration_seconds")
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	samples := make(map[string]uint64)
+	for _, mf := range families {
+		for _, metric := range mf.GetMetric() {
+			var labels []string
+			for _, l := range metric.GetLabel() {
+				labels = append(labels, l.GetValue())
+			}
+			samples[strings.Join(labels, " ")] = metric.GetHistogram().GetSampleCount()
+		}
+	}
+
+	assert.Equal(t, map[string]uint64{
+		"GET /drone/{serial} 404": 2,
+		"GET /drones 200":         1,
+		"GET unmatched 404":       1,
+	}, samples)
+}
+
+func TestInstrumentStorage(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := metrics.New(reg)
+	st := m.InstrumentStorage(storage.NewInMemory(), "in_memory")
+	ctx := context.Background()
+
+	require.NoError(t, st.SaveDrone(ctx, drone.Drone{TenantID: "hospital-a", Serial: "1", State: drone.Idle}))
+	_, err := st.Drone(ctx, "hospital-a", "1")
+	require.NoError(t, err)
+	_, err = st.Drone(ctx, "hospital-a", "2")
+	require.ErrorIs(t, err, drone.ErrNotFound)
+
+	history := m.InstrumentBatteryHistory(storage.NewBatteryHistoryFile(t.TempDir(), storage.BatteryHistoryOptions{}), "file")
+	require.NoError(t, history.AppendBatteryRecords(ctx, drone.BatteryRecord{At: time.Now(), TenantID: "hospital-a", Serial: "1"}))
+
+	count, err := testutil.GatherAndCount(reg, "drone_storage_operation_duration_seconds")
+	require.NoError(t, err)
+	// save_drone ok, drone ok, drone error and append_battery_records ok.
+	assert.Equal(t, 4, count)
+}
+
+func TestFleetCollector(t *testing.T) {
+	st := storage.NewInMemory()
+	ctx := context.Background()
+	for _, d := range []drone.Drone{
+		{TenantID: "hospital-a", Serial: "1", Model: drone.Lightweight, BatteryCapacity: 15, State: drone.Idle},
+		{TenantID: "hospital-a", Serial: "2", Model: drone.Lightweight, BatteryCapacity: 80, State: drone.Loaded, Medications: []drone.Medication{
+			{Name: "Omeprazol", Weight: 120, Code: "OM"},
+			{Name: "Aspirin", Weight: 30, Code: "AS"},
+		}},
+	} {
+		require.NoError(t, st.SaveDrone(ctx, d))
+	}
+
+	c := metrics.NewFleetCollector(st, time.Second)
+	expected := `
+# HELP drone_fleet_battery_level_percent Distribution of the battery level of the drones.
+# TYPE drone_fleet_battery_level_percent histogram
+drone_fleet_battery_level_percent_bucket{tenant="hospital-a",le="10"} 0
+drone_fleet_battery_level_percent_bucket{tenant="hospital-a",le="20"} 1
+drone_fleet_battery_level_percent_bucket{tenant="hospital-a",le="25"} 1
+drone_fleet_battery_level_percent_bucket{tenant="hospital-a",le="30"} 1
+drone_fleet_battery_level_percent_bucket{tenant="hospital-a",le="40"} 1
+drone_fleet_battery_level_percent_bucket{tenant="hospital-a",le="50"} 1
+drone_fleet_battery_level_percent_bucket{tenant="hospital-a",le="60"} 1
+drone_fleet_battery_level_percent_bucket{tenant="hospital-a",le="70"} 1
+drone_fleet_battery_level_percent_bucket{tenant="hospital-a",le="80"} 2
+drone_fleet_battery_level_percent_bucket{tenant="hospital-a",le="90"} 2
+drone_fleet_battery_level_percent_bucket{tenant="hospital-a",le="100"} 2
+drone_fleet_battery_level_percent_bucket{tenant="hospital-a",le="+Inf"} 2
+drone_fleet_battery_level_percent_sum{tenant="hospital-a"} 95
+drone_fleet_battery_level_percent_count{tenant="hospital-a"} 2
+# HELP drone_fleet_drones Number of drones by state.
+# TYPE drone_fleet_drones gauge
+drone_fleet_drones{state="delivered",tenant="hospital-a"} 0
+drone_fleet_drones{state="delivering",tenant="hospital-a"} 0
+drone_fleet_drones{state="idle",tenant="hospital-a"} 1
+drone_fleet_drones{state="loaded",tenant="hospital-a"} 1
+drone_fleet_drones{state="loading",tenant="hospital-a"} 0
+drone_fleet_drones{state="returning",tenant="hospital-a"} 0
+# HELP drone_fleet_drones_by_model Number of drones by model.
+# TYPE drone_fleet_drones_by_model gauge
+drone_fleet_drones_by_model{model="cruiserweight",tenant="hospital-a"} 0
+drone_fleet_drones_by_model{model="heavyweight",tenant="hospital-a"} 0
+drone_fleet_drones_by_model{model="lightweight",tenant="hospital-a"} 2
+drone_fleet_drones_by_model{model="middleweight",tenant="hospital-a"} 0
+# HELP drone_fleet_loaded_weight_grams Weight of the medications loaded in the drones.
+# TYPE drone_fleet_loaded_weight_grams gauge
+drone_fleet_loaded_weight_grams{tenant="hospital-a"} 150
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+}
@@ -0,0 +1,197 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/storage"
+	"github.com/hsequeda/drone/webhook"
+)
+
+var (
+	_ storage.Backend      = (*Storage)(nil)
+	_ drone.AuditStore     = (*AuditStore)(nil)
+	_ drone.BatteryHistory = (*BatteryHistory)(nil)
+)
+
+// Storage is a storage.Backend observing the duration of its operations.
+type Storage struct {
+	next    storage.Backend
+	backend string
+	m       *Metrics
+}
+
+// InstrumentStorage wraps the storage.Backend, backend labels its operations.
+func (m *Metrics) InstrumentStorage(next storage.Backend, backend string) *Storage {
+	return &Storage{next: next, backend: backend, m: m}
+}
+
+func (s *Storage) Drone(ctx context.Context, tenantID, serial string) (d drone.Drone, err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "drone", start, err) }(time.Now())
+	return s.next.Drone(ctx, tenantID, serial)
+}
+
+func (s *Storage) Drones(ctx context.Context, tenantID string) (drones []drone.Drone, err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "drones", start, err) }(time.Now())
+	return s.next.Drones(ctx, tenantID)
+}
+
+func (s *Storage) AllDrones(ctx context.Context) (drones []drone.Drone, err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "all_drones", start, err) }(time.Now())
+	return s.next.AllDrones(ctx)
+}
+
+func (s *Storage) SaveDrone(ctx context.Context, d drone.Drone) (err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "save_drone", start, err) }(time.Now())
+	return s.next.SaveDrone(ctx, d)
+}
+
+func (s *Storage) PendingEvents(ctx context.Context, limit int) (events []drone.Event, err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "pending_events", start, err) }(time.Now())
+	return s.next.PendingEvents(ctx, limit)
+}
+
+func (s *Storage) MarkDispatched(ctx context.Context, ids ...string) (err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "mark_dispatched", start, err) }(time.Now())
+	return s.next.MarkDispatched(ctx, ids...)
+}
+
+func (s *Storage) SaveSubscription(ctx context.Context, sub webhook.Subscription) (err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "save_subscription", start, err) }(time.Now())
+	return s.next.SaveSubscription(ctx, sub)
+}
+
+func (s *Storage) Subscriptions(ctx context.Context, tenantID string) (subs []webhook.Subscription, err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "subscriptions", start, err) }(time.Now())
+	return s.next.Subscriptions(ctx, tenantID)
+}
+
+func (s *Storage) DeleteSubscription(ctx context.Context, tenantID, id string) (err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "delete_subscription", start, err) }(time.Now())
+	return s.next.DeleteSubscription(ctx, tenantID, id)
+}
+
+func (s *Storage) AppendDelivery(ctx context.Context, d webhook.Delivery) (err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "append_delivery", start, err) }(time.Now())
+	return s.next.AppendDelivery(ctx, d)
+}
+
+func (s *Storage) Deliveries(ctx context.Context, tenantID, subscriptionID string) (deliveries []webhook.Delivery, err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "deliveries", start, err) }(time.Now())
+	return s.next.Deliveries(ctx, tenantID, subscriptionID)
+}
+
+func (s *Storage) AppendDeadLetter(ctx context.Context, dl webhook.DeadLetter) (err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "append_dead_letter", start, err) }(time.Now())
+	return s.next.AppendDeadLetter(ctx, dl)
+}
+
+func (s *Storage) DeadLetters(ctx context.Context, tenantID string) (dls []webhook.DeadLetter, err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "dead_letters", start, err) }(time.Now())
+	return s.next.DeadLetters(ctx, tenantID)
+}
+
+func (s *Storage) SavePendingDelivery(ctx context.Context, p webhook.PendingDelivery) (err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "save_pending_delivery", start, err) }(time.Now())
+	return s.next.SavePendingDelivery(ctx, p)
+}
+
+func (s *Storage) PendingDeliveries(ctx context.Context) (pending []webhook.PendingDelivery, err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "pending_deliveries", start, err) }(time.Now())
+	return s.next.PendingDeliveries(ctx)
+}
+
+func (s *Storage) DeletePendingDelivery(ctx context.Context, id string) (err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "delete_pending_delivery", start, err) }(time.Now())
+	return s.next.DeletePendingDelivery(ctx, id)
+}
+
+func (s *Storage) SaveGeofence(ctx context.Context, g drone.Geofence) (err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "save_geofence", start, err) }(time.Now())
+	return s.next.SaveGeofence(ctx, g)
+}
+
+func (s *Storage) Geofences(ctx context.Context, tenantID string) (zones []drone.Geofence, err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "geofences", start, err) }(time.Now())
+	return s.next.Geofences(ctx, tenantID)
+}
+
+func (s *Storage) DeleteGeofence(ctx context.Context, tenantID, id string) (err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "delete_geofence", start, err) }(time.Now())
+	return s.next.DeleteGeofence(ctx, tenantID, id)
+}
+
+func (s *Storage) SaveDock(ctx context.Context, d drone.Dock) (err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "save_dock", start, err) }(time.Now())
+	return s.next.SaveDock(ctx, d)
+}
+
+func (s *Storage) Dock(ctx context.Context, tenantID, id string) (d drone.Dock, err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "dock", start, err) }(time.Now())
+	return s.next.Dock(ctx, tenantID, id)
+}
+
+func (s *Storage) Docks(ctx context.Context, tenantID string) (docks []drone.Dock, err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "docks", start, err) }(time.Now())
+	return s.next.Docks(ctx, tenantID)
+}
+
+func (s *Storage) DeleteDock(ctx context.Context, tenantID, id string) (err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "delete_dock", start, err) }(time.Now())
+	return s.next.DeleteDock(ctx, tenantID, id)
+}
+
+func (s *Storage) SaveChargingSession(ctx context.Context, cs drone.ChargingSession) (err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "save_charging_session", start, err) }(time.Now())
+	return s.next.SaveChargingSession(ctx, cs)
+}
+
+func (s *Storage) ChargingSessions(ctx context.Context, tenantID, dockID string) (sessions []drone.ChargingSession, err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "charging_sessions", start, err) }(time.Now())
+	return s.next.ChargingSessions(ctx, tenantID, dockID)
+}
+
+// AuditStore is a drone.AuditStore observing the duration of its operations.
+type AuditStore struct {
+	next    drone.AuditStore
+	backend string
+	m       *Metrics
+}
+
+// InstrumentAuditStore wraps the drone.AuditStore, backend labels its operations.
+func (m *Metrics) InstrumentAuditStore(next drone.AuditStore, backend string) *AuditStore {
+	return &AuditStore{next: next, backend: backend, m: m}
+}
+
+func (s *AuditStore) AppendAuditEvent(ctx context.Context, e drone.AuditEvent) (err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "append_audit_event", start, err) }(time.Now())
+	return s.next.AppendAuditEvent(ctx, e)
+}
+
+func (s *AuditStore) AuditEvents(ctx context.Context, tenantID, serial string) (events []drone.AuditEvent, err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "audit_events", start, err) }(time.Now())
+	return s.next.AuditEvents(ctx, tenantID, serial)
+}
+
+// BatteryHistory is a drone.BatteryHistory observing the duration of its operations.
+type BatteryHistory struct {
+	next    drone.BatteryHistory
+	backend string
+	m       *Metrics
+}
+
+// InstrumentBatteryHistory wraps the drone.BatteryHistory, backend labels its operations.
+func (m *Metrics) InstrumentBatteryHistory(next drone.BatteryHistory, backend string) *BatteryHistory {
+	return &BatteryHistory{next: next, backend: backend, m: m}
+}
+
+func (s *BatteryHistory) AppendBatteryRecords(ctx context.Context, records ...drone.BatteryRecord) (err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "append_battery_records", start, err) }(time.Now())
+	return s.next.AppendBatteryRecords(ctx, records...)
+}
+
+func (s *BatteryHistory) BatteryRecords(ctx context.Context, tenantID, serial string, from, to time.Time) (records []drone.BatteryRecord, err error) {
+	defer func(start time.Time) { s.m.observe(s.backend, "battery_records", start, err) }(time.Now())
+	return s.next.BatteryRecords(ctx, tenantID, serial, from, to)
+}
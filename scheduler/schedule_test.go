@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	// 2023-01-10 is a Tuesday.
+	from := time.Date(2023, time.January, 10, 12, 7, 30, 0, time.UTC)
+	for _, tc := range []struct {
+		name string
+		expr string
+		next time.Time
+	}{
+		{name: "Interval", expr: "@every 10s", next: from.Add(10 * time.Second)},
+		{name: "EveryMinute", expr: "* * * * *", next: time.Date(2023, time.January, 10, 12, 8, 0, 0, time.UTC)},
+		{name: "Step", expr: "*/15 * * * *", next: time.Date(2023, time.January, 10, 12, 15, 0, 0, time.UTC)},
+		{name: "Daily", expr: "30 2 * * *", next: time.Date(2023, time.January, 11, 2, 30, 0, 0, time.UTC)},
+		{name: "List", expr: "0 9,18 * * *", next: time.Date(2023, time.January, 10, 18, 0, 0, 0, time.UTC)},
+		{name: "Range", expr: "0 0 * * 6-7", next: time.Date(2023, time.January, 14, 0, 0, 0, 0, time.UTC)},
+		{name: "DayOfMonth", expr: "0 0 1 * *", next: time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "DayOfMonthOrWeek", expr: "0 0 1 * 3", next: time.Date(2023, time.January, 11, 0, 0, 0, 0, time.UTC)},
+		{name: "Month", expr: "0 0 29 2 *", next: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "Never", expr: "0 0 30 2 *", next: time.Time{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := ParseSchedule(tc.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tc.next, s.Next(from))
+			assert.Equal(t, tc.expr, s.String())
+		})
+	}
+}
+
+func TestParseScheduleInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"@every",
+		"@every -1s",
+		"@every soon",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+	} {
+		_, err := ParseSchedule(expr)
+		assert.Error(t, err, expr)
+	}
+}
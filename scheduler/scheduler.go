@@ -0,0 +1,205 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrDuplicateJob error occurs when a Job name is already in the Scheduler.
+var ErrDuplicateJob = errors.New("duplicate job")
+
+// Func is the work of a Job, the context is cancelled when the Scheduler stops
+// before the run finishes.
+type Func func(ctx context.Context) error
+
+// JobStatus is the state of a Job.
+type JobStatus struct {
+	Name           string
+	Schedule       string
+	Running        bool
+	Runs           int
+	Failures       int
+	LastStartedAt  time.Time
+	LastFinishedAt time.Time
+	LastDuration   time.Duration
+	LastError      string
+	NextRunAt      time.Time
+}
+
+// job is a Func registered in the Scheduler.
+type job struct {
+	schedule Schedule
+	run      Func
+
+	mu     sync.Mutex
+	status JobStatus
+}
+
+// Scheduler runs the periodic jobs of the application, a job never overlaps
+// with its previous run.
+type Scheduler struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	jobs    []*job
+	started bool
+	stop    context.CancelFunc
+	runCtx  context.Context
+	runStop context.CancelFunc
+	running sync.WaitGroup
+	onErr   func(name string, err error)
+}
+
+// New builds a Scheduler, onErr (optional) receives the errors of the jobs.
+func New(onErr func(name string, err error)) *Scheduler {
+	if onErr == nil {
+		onErr = func(string, error) {}
+	}
+
+	return &Scheduler{now: time.Now, onErr: onErr}
+}
+
+// Add registers a Job, it has to be called before Start.
+func (s *Scheduler) Add(name string, schedule Schedule, run Func) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return fmt.Errorf("add job %q: scheduler already started", name)
+	}
+
+	for _, j := range s.jobs {
+		if j.status.Name == name {
+			return fmt.Errorf("add job %q: %w", name, ErrDuplicateJob)
+		}
+	}
+
+	s.jobs = append(s.jobs, &job{
+		schedule: schedule,
+		run:      run,
+		status:   JobStatus{Name: name, Schedule: schedule.String()},
+	})
+	return nil
+}
+
+// Start schedules the jobs until ctx is done or Stop is called. The runs in
+// progress are not cancelled by ctx, see Stop.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+
+	s.started = true
+	ctx, s.stop = context.WithCancel(ctx)
+	s.runCtx, s.runStop = context.WithCancel(context.Background())
+	now := s.now()
+	for _, j := range s.jobs {
+		j.mu.Lock()
+		j.status.NextRunAt = j.schedule.Next(now)
+		j.mu.Unlock()
+		s.running.Add(1)
+		go s.loop(ctx, j)
+	}
+}
+
+// Stop stops scheduling new runs and waits the runs in progress, cancelling
+// them when ctx is done first.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.started {
+		s.mu.Unlock()
+		return nil
+	}
+
+	s.stop()
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+
+	defer s.runStop()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("stop scheduler: %w", ctx.Err())
+	}
+}
+
+// Status returns the status of the jobs in the order they were added.
+func (s *Scheduler) Status() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := make([]JobStatus, len(s.jobs))
+	for i, j := range s.jobs {
+		j.mu.Lock()
+		status[i] = j.status
+		j.mu.Unlock()
+	}
+
+	return status
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	defer s.running.Done()
+	j.mu.Lock()
+	next := j.status.NextRunAt
+	j.mu.Unlock()
+	for {
+		if next.IsZero() {
+			return
+		}
+
+		timer := time.NewTimer(next.Sub(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			j.mu.Lock()
+			j.status.NextRunAt = time.Time{}
+			j.mu.Unlock()
+			return
+		case <-timer.C:
+			s.execute(j)
+		}
+
+		next = j.schedule.Next(s.now())
+		j.mu.Lock()
+		j.status.NextRunAt = next
+		j.mu.Unlock()
+	}
+}
+
+func (s *Scheduler) execute(j *job) {
+	start := s.now()
+	j.mu.Lock()
+	j.status.Running = true
+	j.status.LastStartedAt = start
+	j.mu.Unlock()
+
+	err := j.run(s.runCtx)
+
+	end := s.now()
+	j.mu.Lock()
+	j.status.Running = false
+	j.status.Runs++
+	j.status.LastFinishedAt = end
+	j.status.LastDuration = end.Sub(start)
+	j.status.LastError = ""
+	if err != nil {
+		j.status.Failures++
+		j.status.LastError = err.Error()
+	}
+	name := j.status.Name
+	j.mu.Unlock()
+
+	if err != nil {
+		s.onErr(name, err)
+	}
+}
@@ -0,0 +1,99 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler(t *testing.T) {
+	errFailed := errors.New("failed")
+	runs := make(chan int, 10)
+	var failed []string
+	s := New(func(name string, err error) {
+		assert.ErrorIs(t, err, errFailed)
+		failed = append(failed, name)
+	})
+
+	n := 0
+	require.NoError(t, s.Add("counter", Every(10*time.Millisecond), func(ctx context.Context) error {
+		n++
+		runs <- n
+		if n == 2 {
+			return errFailed
+		}
+
+		return nil
+	}))
+	require.ErrorIs(t, s.Add("counter", Every(time.Second), nil), ErrDuplicateJob)
+
+	s.Start(context.Background())
+	require.Error(t, s.Add("late", Every(time.Second), nil))
+	for i := 1; i <= 3; i++ {
+		assert.Equal(t, i, <-runs)
+	}
+
+	require.NoError(t, s.Stop(context.Background()))
+	status := s.Status()
+	require.Len(t, status, 1)
+	assert.Equal(t, "counter", status[0].Name)
+	assert.Equal(t, "@every 10ms", status[0].Schedule)
+	assert.False(t, status[0].Running)
+	assert.GreaterOrEqual(t, status[0].Runs, 3)
+	assert.Equal(t, 1, status[0].Failures)
+	assert.True(t, status[0].NextRunAt.IsZero())
+	assert.Equal(t, []string{"counter"}, failed)
+}
+
+func TestSchedulerStopWaitsRunningJobs(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s := New(nil)
+	require.NoError(t, s.Add("slow", Every(time.Millisecond), func(ctx context.Context) error {
+		close(started)
+		select {
+		case <-release:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+	<-started
+	// cancelling the start context only stops the scheduling.
+	cancel()
+	assert.True(t, s.Status()[0].Running)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+	require.NoError(t, s.Stop(context.Background()))
+	assert.Equal(t, 1, s.Status()[0].Runs)
+	assert.Empty(t, s.Status()[0].LastError)
+}
+
+func TestSchedulerStopCancelsRunningJobs(t *testing.T) {
+	started := make(chan struct{})
+	s := New(nil)
+	require.NoError(t, s.Add("stuck", Every(time.Millisecond), func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	s.Start(context.Background())
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, s.Stop(ctx), context.DeadlineExceeded)
+	assert.Eventually(t, func() bool {
+		return s.Status()[0].LastError == context.Canceled.Error()
+	}, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// FileAudit is an AuditStore that appends the events as JSON Lines to a file.
+type FileAudit struct {
+	mu   sync.Mutex
+	path string
+}
+
+var _ drone.AuditStore = (*FileAudit)(nil)
+
+// NewFileAudit initialize an Audit Storage backed by the file in path.
+// NOTE: the file (and its directory) is created on the first append.
+func NewFileAudit(path string) *FileAudit {
+	return &FileAudit{path: path}
+}
+
+// AppendAuditEvent implements drone.AuditStore
+func (s *FileAudit) AppendAuditEvent(_ context.Context, e drone.AuditEvent) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode audit event: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err = os.MkdirAll(filepath.Dir(s.path), os.ModePerm); err != nil {
+		return fmt.Errorf("create audit dir: %w", err)
+	}
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return fmt.Errorf("open audit file: %w", err)
+	}
+
+	defer f.Close()
+	torn, err := tornLine(f)
+	if err != nil {
+		return err
+	}
+
+	if torn {
+		// NOTE: the event starts a new line, so it isn't lost with the torn one.
+		b = append([]byte{'\n'}, b...)
+	}
+
+	if _, err = f.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("append audit event: %w", err)
+	}
+
+	return nil
+}
+
+// tornLine returns if the file ends with a line without line break, as left
+// by an append interrupted by a crash.
+func tornLine(f *os.File) (bool, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return false, fmt.Errorf("stat audit file: %w", err)
+	}
+
+	if info.Size() == 0 {
+		return false, nil
+	}
+
+	last := make([]byte, 1)
+	if _, err = f.ReadAt(last, info.Size()-1); err != nil {
+		return false, fmt.Errorf("read audit file: %w", err)
+	}
+
+	return last[0] != '\n', nil
+}
+
+// AuditEvents implements drone.AuditStore
+// NOTE: the torn and undecodable lines are logged and skipped, so a broken
+// event doesn't hide the rest.
+func (s *FileAudit) AuditEvents(ctx context.Context, tenantID, serial string) ([]drone.AuditEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	events := make([]drone.AuditEvent, 0)
+	f, err := os.Open(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return events, nil
+		}
+
+		return nil, fmt.Errorf("open audit file: %w", err)
+	}
+
+	defer f.Close()
+	reader := bufio.NewReader(f)
+	for n := 1; ; n++ {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("read audit file: %w", err)
+			}
+
+			if len(line) > 0 {
+				slog.WarnContext(ctx, "skipped torn audit event", slog.String("path", s.path), slog.Int("line", n))
+			}
+
+			return events, nil
+		}
+
+		var e drone.AuditEvent
+		if err = json.Unmarshal(line, &e); err != nil {
+			slog.WarnContext(ctx, "skipped undecodable audit event", slog.String("path", s.path), slog.Int("line", n), slog.Any("error", err))
+			continue
+		}
+
+		if e.TenantID == tenantID && e.Serial == serial {
+			events = append(events, e)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileAudit(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "audit", "events.jsonl")
+	s := NewFileAudit(path)
+
+	got, err := s.AuditEvents(context.Background(), "hospital-a", "1")
+	require.NoError(t, err)
+	assert.Empty(t, got) // the file doesn't exist yet
+
+	events := testAuditEvents()
+	for _, e := range events {
+		require.NoError(t, s.AppendAuditEvent(context.Background(), e))
+	}
+
+	// a new instance reads the events persisted by the previous one
+	got, err = NewFileAudit(path).AuditEvents(context.Background(), "hospital-a", "1")
+	require.NoError(t, err)
+	assert.Equal(t, []drone.AuditEvent{events[0], events[2]}, got)
+
+	got, err = s.AuditEvents(context.Background(), "hospital-b", "1")
+	require.NoError(t, err)
+	assert.Equal(t, []drone.AuditEvent{events[1]}, got)
+}
+
+func TestFileAuditBrokenLines(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	s := NewFileAudit(path)
+	events := testAuditEvents()
+
+	// an event bigger than the usual line buffers
+	big := events[0]
+	big.Changes = []drone.FieldChange{{Field: "Medications", Before: json.RawMessage("null"), After: json.RawMessage(`"` + strings.Repeat("a", 2*1024*1024) + `"`)}}
+	require.NoError(t, s.AppendAuditEvent(context.Background(), big))
+
+	// an undecodable line, then an append torn by a crash
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString("not json\n{\"TenantID\":\"hospital-a\"")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	got, err := s.AuditEvents(context.Background(), "hospital-a", "1")
+	require.NoError(t, err)
+	assert.Equal(t, []drone.AuditEvent{big}, got)
+
+	// the next event starts a new line after the torn one
+	require.NoError(t, s.AppendAuditEvent(context.Background(), events[2]))
+	got, err = s.AuditEvents(context.Background(), "hospital-a", "1")
+	require.NoError(t, err)
+	assert.Equal(t, []drone.AuditEvent{big, events[2]}, got)
+}
@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// InMemoryAudit represents an 'In-Memory' AuditStore for the service.
+type InMemoryAudit struct {
+	mu            sync.RWMutex
+	eventsByDrone map[droneKey][]drone.AuditEvent
+}
+
+var _ drone.AuditStore = (*InMemoryAudit)(nil)
+
+// NewInMemoryAudit initialize the Audit Storage.
+func NewInMemoryAudit() *InMemoryAudit {
+	return &InMemoryAudit{eventsByDrone: make(map[droneKey][]drone.AuditEvent)}
+}
+
+// AppendAuditEvent implements drone.AuditStore
+func (s *InMemoryAudit) AppendAuditEvent(_ context.Context, e drone.AuditEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := droneKey{tenantID: e.TenantID, serial: e.Serial}
+	s.eventsByDrone[k] = append(s.eventsByDrone[k], e)
+	return nil
+}
+
+// AuditEvents implements drone.AuditStore
+func (s *InMemoryAudit) AuditEvents(_ context.Context, tenantID, serial string) ([]drone.AuditEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	events := s.eventsByDrone[droneKey{tenantID: tenantID, serial: serial}]
+	// NOTE: return a copy, the stored events are append-only.
+	return append(make([]drone.AuditEvent, 0, len(events)), events...), nil
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAuditEvents returns a list of events of two drones of different tenants.
+func testAuditEvents() []drone.AuditEvent {
+	at := time.Date(2023, time.January, 10, 12, 0, 0, 0, time.UTC)
+	return []drone.AuditEvent{
+		{
+			TenantID:  "hospital-a",
+			Serial:    "1",
+			Actor:     "operator",
+			RequestID: "req-1",
+			Action:    drone.AuditRegister,
+			At:        at,
+			Changes:   []drone.FieldChange{{Field: "State", Before: json.RawMessage("0"), After: json.RawMessage("1")}},
+		},
+		{
+			TenantID:  "hospital-b",
+			Serial:    "1",
+			Actor:     "operator",
+			RequestID: "req-2",
+			Action:    drone.AuditRegister,
+			At:        at.Add(time.Minute),
+		},
+		{
+			TenantID:  "hospital-a",
+			Serial:    "1",
+			Actor:     "operator",
+			RequestID: "req-3",
+			Action:    drone.AuditLoad,
+			At:        at.Add(2 * time.Minute),
+			Changes:   []drone.FieldChange{{Field: "Medications", Before: json.RawMessage("null"), After: json.RawMessage(`[{"Name":"A"}]`)}},
+		},
+	}
+}
+
+func TestInMemoryAudit(t *testing.T) {
+	t.Parallel()
+	s := NewInMemoryAudit()
+	events := testAuditEvents()
+	for _, e := range events {
+		require.NoError(t, s.AppendAuditEvent(context.Background(), e))
+	}
+
+	got, err := s.AuditEvents(context.Background(), "hospital-a", "1")
+	require.NoError(t, err)
+	assert.Equal(t, []drone.AuditEvent{events[0], events[2]}, got)
+
+	got, err = s.AuditEvents(context.Background(), "hospital-a", "qwerty")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
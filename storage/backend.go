@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"errors"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/webhook"
+)
+
+// ProbeTenant is the reserved tenant of the health probes, its drones are
+// left out of AllDrones so they never reach the fleet metrics or the audits.
+const ProbeTenant = "_health"
+
+// Backend is the main storage of the service, implemented by InMemory and
+// JSON and wrapped by the logging, tracing and metrics decorators.
+type Backend interface {
+	drone.Storage
+	drone.Outbox
+	drone.GeofenceStore
+	drone.DockStore
+	webhook.Store
+}
+
+var (
+	_ Backend = (*InMemory)(nil)
+	_ Backend = (*JSON)(nil)
+)
+
+// IsNotFound returns if err is the not found error of a Backend lookup, an
+// expected answer rather than a failure of the storage.
+func IsNotFound(err error) bool {
+	return errors.Is(err, drone.ErrNotFound) || errors.Is(err, drone.ErrGeofenceNotFound) ||
+		errors.Is(err, drone.ErrDockNotFound) || errors.Is(err, webhook.ErrNotFound)
+}
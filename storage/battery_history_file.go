@@ -0,0 +1,258 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+const (
+	// batteryFilePrefix and batteryFileSuffix wrap the creation time of each history file.
+	batteryFilePrefix = "battery-"
+	batteryFileSuffix = ".jsonl"
+	// batteryFileTimeLayout is the layout of the creation time in the history file names.
+	batteryFileTimeLayout = "20060102T150405.000000000Z"
+)
+
+// BatteryHistoryOptions defines the rotation and retention of a BatteryHistoryFile.
+type BatteryHistoryOptions struct {
+	// MaxFileSize is the size (in bytes) after which a new file is started. Zero disables it.
+	MaxFileSize int64
+	// MaxFileAge is the age after which a new file is started. Zero disables it.
+	MaxFileAge time.Duration
+	// Retention is the age after which a file is deleted. Zero keeps the files forever.
+	Retention time.Duration
+}
+
+// BatteryHistoryFile is a BatteryHistory that appends the records as JSON Lines
+// to rotated files inside a directory.
+type BatteryHistoryFile struct {
+	dir  string
+	opts BatteryHistoryOptions
+	now  func() time.Time
+
+	mu sync.Mutex
+}
+
+var _ drone.BatteryHistory = (*BatteryHistoryFile)(nil)
+
+// NewBatteryHistoryFile initialize a BatteryHistory backed by the files of dir.
+// NOTE: the directory is created on the first append.
+func NewBatteryHistoryFile(dir string, opts BatteryHistoryOptions) *BatteryHistoryFile {
+	return &BatteryHistoryFile{dir: dir, opts: opts, now: time.Now}
+}
+
+// AppendBatteryRecords implements drone.BatteryHistory
+func (h *BatteryHistoryFile) AppendBatteryRecords(_ context.Context, records ...drone.BatteryRecord) error {
+	var buf []byte
+	for _, r := range records {
+		b, err := json.Marshal(r)
+		if err != nil {
+			return fmt.Errorf("encode battery record: %w", err)
+		}
+
+		buf = append(append(buf, b...), '\n')
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err := os.MkdirAll(h.dir, os.ModePerm); err != nil {
+		return fmt.Errorf("create battery history dir: %w", err)
+	}
+
+	if err := h.removeExpired(); err != nil {
+		return err
+	}
+
+	path, err := h.currentFile()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open battery history file: %w", err)
+	}
+
+	defer f.Close()
+	if _, err = f.Write(buf); err != nil {
+		return fmt.Errorf("append battery records: %w", err)
+	}
+
+	return nil
+}
+
+// BatteryRecords implements drone.BatteryHistory
+func (h *BatteryHistoryFile) BatteryRecords(_ context.Context, tenantID, serial string, from, to time.Time) ([]drone.BatteryRecord, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	files, err := h.files()
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]drone.BatteryRecord, 0)
+	for i, f := range files {
+		// skip the files created after the range and the ones closed before it.
+		if !to.IsZero() && !f.createdAt.Before(to) {
+			break
+		}
+
+		if !from.IsZero() && i+1 < len(files) && files[i+1].createdAt.Before(from) {
+			continue
+		}
+
+		if err := readBatteryFile(f.path, func(r drone.BatteryRecord) {
+			if r.TenantID != tenantID || r.Serial != serial {
+				return
+			}
+
+			if (!from.IsZero() && r.At.Before(from)) || (!to.IsZero() && !r.At.Before(to)) {
+				return
+			}
+
+			records = append(records, r)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool { return records[i].At.Before(records[j].At) })
+	return records, nil
+}
+
+// batteryFile is a history file and the time it was created.
+type batteryFile struct {
+	path      string
+	createdAt time.Time
+}
+
+// files returns the history files sorted by creation time.
+func (h *BatteryHistoryFile) files() ([]batteryFile, error) {
+	entries, err := os.ReadDir(h.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("read battery history dir: %w", err)
+	}
+
+	var files []batteryFile
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, batteryFilePrefix) || !strings.HasSuffix(name, batteryFileSuffix) {
+			continue
+		}
+
+		createdAt, err := time.Parse(batteryFileTimeLayout, strings.TrimSuffix(strings.TrimPrefix(name, batteryFilePrefix), batteryFileSuffix))
+		if err != nil {
+			continue // not a history file
+		}
+
+		files = append(files, batteryFile{path: filepath.Join(h.dir, name), createdAt: createdAt})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].createdAt.Before(files[j].createdAt) })
+	return files, nil
+}
+
+// currentFile returns the file to append to, rotating the last one if it's too big or too old.
+func (h *BatteryHistoryFile) currentFile() (string, error) {
+	files, err := h.files()
+	if err != nil {
+		return "", err
+	}
+
+	now := h.now().UTC()
+	if len(files) > 0 {
+		last := files[len(files)-1]
+		rotate := h.opts.MaxFileAge > 0 && now.Sub(last.createdAt) >= h.opts.MaxFileAge
+		if h.opts.MaxFileSize > 0 {
+			info, err := os.Stat(last.path)
+			if err != nil {
+				return "", fmt.Errorf("stat battery history file: %w", err)
+			}
+
+			rotate = rotate || info.Size() >= h.opts.MaxFileSize
+		}
+
+		if !rotate {
+			return last.path, nil
+		}
+	}
+
+	return filepath.Join(h.dir, batteryFilePrefix+now.Format(batteryFileTimeLayout)+batteryFileSuffix), nil
+}
+
+// removeExpired deletes the files whose records are all older than the retention.
+func (h *BatteryHistoryFile) removeExpired() error {
+	if h.opts.Retention <= 0 {
+		return nil
+	}
+
+	files, err := h.files()
+	if err != nil {
+		return err
+	}
+
+	limit := h.now().UTC().Add(-h.opts.Retention)
+	// NOTE: a file holds the records until the next file is created, so the
+	// last one is never expired.
+	for i := 0; i+1 < len(files); i++ {
+		if files[i+1].createdAt.After(limit) {
+			break
+		}
+
+		if err := os.Remove(files[i].path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove expired battery history file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// readBatteryFile calls fn with every record of the file.
+// NOTE: a trailing line without a line break is being written by another
+// process, so it's ignored.
+func readBatteryFile(path string, fn func(drone.BatteryRecord)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil // removed by the retention
+		}
+
+		return fmt.Errorf("open battery history file: %w", err)
+	}
+
+	defer f.Close()
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return fmt.Errorf("read battery history file: %w", err)
+		}
+
+		var r drone.BatteryRecord
+		if err = json.Unmarshal(line, &r); err != nil {
+			return fmt.Errorf("decode battery record: %w", err)
+		}
+
+		fn(r)
+	}
+}
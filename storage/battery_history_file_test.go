@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatteryHistoryFile(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	dir := t.TempDir()
+	now := time.Date(2023, time.January, 10, 12, 0, 0, 0, time.UTC)
+	h := NewBatteryHistoryFile(dir, BatteryHistoryOptions{MaxFileAge: time.Hour})
+	h.now = func() time.Time { return now }
+
+	var expected []drone.BatteryRecord
+	for i := 0; i < 4; i++ {
+		records := []drone.BatteryRecord{
+			{At: now, TenantID: "hospital-a", Serial: "1", Battery: uint8(90 - i*10), State: drone.Delivering},
+			{At: now, TenantID: "hospital-a", Serial: "2", Battery: 50, State: drone.Idle},
+			{At: now, TenantID: "hospital-b", Serial: "1", Battery: 40, State: drone.Idle},
+		}
+		require.NoError(t, h.AppendBatteryRecords(ctx, records...))
+		expected = append(expected, records[0])
+		now = now.Add(30 * time.Minute)
+	}
+
+	// a new file is started every hour
+	files, err := h.files()
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	got, err := h.BatteryRecords(ctx, "hospital-a", "1", time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, expected, got)
+
+	got, err = h.BatteryRecords(ctx, "hospital-a", "1", expected[1].At, expected[3].At)
+	require.NoError(t, err)
+	assert.Equal(t, expected[1:3], got)
+
+	got, err = h.BatteryRecords(ctx, "hospital-a", "qwerty", time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestBatteryHistoryFileRotationBySize(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	now := time.Date(2023, time.January, 10, 12, 0, 0, 0, time.UTC)
+	h := NewBatteryHistoryFile(t.TempDir(), BatteryHistoryOptions{MaxFileSize: 1})
+	h.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, h.AppendBatteryRecords(ctx, drone.BatteryRecord{At: now, TenantID: "hospital-a", Serial: "1", Battery: 80}))
+		now = now.Add(time.Second)
+	}
+
+	files, err := h.files()
+	require.NoError(t, err)
+	assert.Len(t, files, 3)
+}
+
+func TestBatteryHistoryFileRetention(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	dir := t.TempDir()
+	now := time.Date(2023, time.January, 10, 12, 0, 0, 0, time.UTC)
+	h := NewBatteryHistoryFile(dir, BatteryHistoryOptions{MaxFileAge: 24 * time.Hour, Retention: 48 * time.Hour})
+	h.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, h.AppendBatteryRecords(ctx, drone.BatteryRecord{At: now, TenantID: "hospital-a", Serial: "1", Battery: 80}))
+		now = now.Add(24 * time.Hour)
+	}
+
+	// only the files with records of the last 48 hours are kept
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+
+	got, err := h.BatteryRecords(ctx, "hospital-a", "1", time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, got, 3)
+}
@@ -5,24 +5,61 @@ import (
 	"sync"
 
 	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/webhook"
 )
 
+// droneKey identifies a Drone inside the partition of its tenant.
+type droneKey struct {
+	tenantID string
+	serial   string
+}
+
 // InMemory represents an 'In-Memory' storage for the service.
 type InMemory struct {
-	droneBySerial sync.Map
+	droneByKey sync.Map
+
+	// mu serializes SaveDrone so the drone and its events are committed together.
+	mu         sync.Mutex
+	outboxByID map[string]drone.Event
+
+	webhookMu             sync.RWMutex
+	subscriptionsByTenant map[string][]webhook.Subscription
+	deliveriesBySub       map[string][]webhook.Delivery
+	deadLettersByTenant   map[string][]webhook.DeadLetter
+	pendingDeliveries     map[string]webhook.PendingDelivery
+
+	geofenceMu        sync.RWMutex
+	geofencesByTenant map[string][]drone.Geofence
+
+	dockMu         sync.RWMutex
+	docksByTenant  map[string][]drone.Dock
+	sessionsByDock map[string][]drone.ChargingSession
 }
 
-var _ drone.Storage = (*InMemory)(nil)
+var (
+	_ drone.Storage = (*InMemory)(nil)
+	_ drone.Outbox  = (*InMemory)(nil)
+)
 
 // NewInMemory initialize the Drone Storage.
 func NewInMemory() *InMemory {
-	return &InMemory{droneBySerial: sync.Map{}}
+	return &InMemory{
+		droneByKey:            sync.Map{},
+		outboxByID:            make(map[string]drone.Event),
+		subscriptionsByTenant: make(map[string][]webhook.Subscription),
+		deliveriesBySub:       make(map[string][]webhook.Delivery),
+		deadLettersByTenant:   make(map[string][]webhook.DeadLetter),
+		pendingDeliveries:     make(map[string]webhook.PendingDelivery),
+		geofencesByTenant:     make(map[string][]drone.Geofence),
+		docksByTenant:         make(map[string][]drone.Dock),
+		sessionsByDock:        make(map[string][]drone.ChargingSession),
+	}
 }
 
-// Drone returns a Drone entity by its serial number.
-// NOTE: Returns NotFound error if serial doesn't match.
-func (s *InMemory) Drone(_ context.Context, serial string) (drone.Drone, error) {
-	d, ok := s.droneBySerial.Load(serial)
+// Drone returns a Drone entity of the tenant by its serial number.
+// NOTE: Returns NotFound error if serial doesn't match inside the tenant.
+func (s *InMemory) Drone(_ context.Context, tenantID, serial string) (drone.Drone, error) {
+	d, ok := s.droneByKey.Load(droneKey{tenantID: tenantID, serial: serial})
 	if !ok {
 		return drone.Drone{}, drone.ErrNotFound
 	}
@@ -30,19 +67,66 @@ func (s *InMemory) Drone(_ context.Context, serial string) (drone.Drone, error)
 	return d.(drone.Drone), nil
 }
 
-// Drone returns a list of all the Drone entities.
-func (s *InMemory) Drones(_ context.Context) ([]drone.Drone, error) {
+// Drones returns a list of all the Drone entities of the tenant.
+func (s *InMemory) Drones(_ context.Context, tenantID string) ([]drone.Drone, error) {
 	droneArr := make([]drone.Drone, 0)
-	s.droneBySerial.Range(func(_, d any) bool {
-		droneArr = append(droneArr, d.(drone.Drone))
+	s.droneByKey.Range(func(k, d any) bool {
+		if k.(droneKey).tenantID == tenantID {
+			droneArr = append(droneArr, d.(drone.Drone))
+		}
 		return true
 	})
 
 	return droneArr, nil
 }
 
-// SaveDrone persists the current state of a Drone entity.
-func (s *InMemory) SaveDrone(_ context.Context, drone drone.Drone) error {
-	s.droneBySerial.Store(drone.Serial, drone)
+// AllDrones returns a list of the Drone entities of every tenant but ProbeTenant.
+func (s *InMemory) AllDrones(_ context.Context) ([]drone.Drone, error) {
+	droneArr := make([]drone.Drone, 0)
+	s.droneByKey.Range(func(k, d any) bool {
+		if k.(droneKey).tenantID != ProbeTenant {
+			droneArr = append(droneArr, d.(drone.Drone))
+		}
+
+		return true
+	})
+
+	return droneArr, nil
+}
+
+// SaveDrone persists the current state of a Drone entity and commits its
+// pending events to the outbox.
+func (s *InMemory) SaveDrone(_ context.Context, d drone.Drone) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range d.Events() {
+		s.outboxByID[e.ID] = e
+	}
+
+	d.ClearEvents()
+	s.droneByKey.Store(droneKey{tenantID: d.TenantID, serial: d.Serial}, d)
+	return nil
+}
+
+// PendingEvents implements drone.Outbox
+func (s *InMemory) PendingEvents(_ context.Context, limit int) ([]drone.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	events := make([]drone.Event, 0, len(s.outboxByID))
+	for _, e := range s.outboxByID {
+		events = append(events, e)
+	}
+
+	return sortEvents(events, limit), nil
+}
+
+// MarkDispatched implements drone.Outbox
+func (s *InMemory) MarkDispatched(_ context.Context, ids ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		delete(s.outboxByID, id)
+	}
+
 	return nil
 }
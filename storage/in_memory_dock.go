@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+var _ drone.DockStore = (*InMemory)(nil)
+
+// SaveDock implements drone.DockStore
+func (s *InMemory) SaveDock(_ context.Context, d drone.Dock) error {
+	s.dockMu.Lock()
+	defer s.dockMu.Unlock()
+	docks := s.docksByTenant[d.TenantID]
+	for i := range docks {
+		if docks[i].ID == d.ID {
+			docks[i] = d
+			return nil
+		}
+	}
+
+	s.docksByTenant[d.TenantID] = append(docks, d)
+	return nil
+}
+
+// Dock implements drone.DockStore
+func (s *InMemory) Dock(_ context.Context, tenantID, id string) (drone.Dock, error) {
+	s.dockMu.RLock()
+	defer s.dockMu.RUnlock()
+	for _, d := range s.docksByTenant[tenantID] {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+
+	return drone.Dock{}, drone.ErrDockNotFound
+}
+
+// Docks implements drone.DockStore
+func (s *InMemory) Docks(_ context.Context, tenantID string) ([]drone.Dock, error) {
+	s.dockMu.RLock()
+	defer s.dockMu.RUnlock()
+	docks := s.docksByTenant[tenantID]
+	return append(make([]drone.Dock, 0, len(docks)), docks...), nil
+}
+
+// DeleteDock implements drone.DockStore
+func (s *InMemory) DeleteDock(_ context.Context, tenantID, id string) error {
+	s.dockMu.Lock()
+	defer s.dockMu.Unlock()
+	docks := s.docksByTenant[tenantID]
+	for i := range docks {
+		if docks[i].ID == id {
+			s.docksByTenant[tenantID] = append(docks[:i:i], docks[i+1:]...)
+			return nil
+		}
+	}
+
+	return drone.ErrDockNotFound
+}
+
+// SaveChargingSession implements drone.DockStore
+func (s *InMemory) SaveChargingSession(_ context.Context, cs drone.ChargingSession) error {
+	s.dockMu.Lock()
+	defer s.dockMu.Unlock()
+	k := cs.TenantID + "/" + cs.DockID
+	sessions := s.sessionsByDock[k]
+	for i := range sessions {
+		if sessions[i].ID == cs.ID {
+			sessions[i] = cs
+			return nil
+		}
+	}
+
+	s.sessionsByDock[k] = append(sessions, cs)
+	return nil
+}
+
+// ChargingSessions implements drone.DockStore
+func (s *InMemory) ChargingSessions(_ context.Context, tenantID, dockID string) ([]drone.ChargingSession, error) {
+	s.dockMu.RLock()
+	defer s.dockMu.RUnlock()
+	sessions := s.sessionsByDock[tenantID+"/"+dockID]
+	return append(make([]drone.ChargingSession, 0, len(sessions)), sessions...), nil
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryDocks(t *testing.T) {
+	t.Parallel()
+	testDockStore(t, NewInMemory())
+}
+
+// testDockStore checks the behaviour shared by every drone.DockStore.
+func testDockStore(t *testing.T, s drone.DockStore) {
+	t.Helper()
+	ctx := context.Background()
+	dock, err := drone.NewDock("hospital-dock", "Roof", drone.Coordinates{Latitude: 40.42, Longitude: -3.7}, 2, 1.5)
+	require.NoError(t, err)
+	require.NoError(t, s.SaveDock(ctx, dock))
+
+	got, err := s.Dock(ctx, dock.TenantID, dock.ID)
+	require.NoError(t, err)
+	assert.Equal(t, dock, got)
+	docks, err := s.Docks(ctx, dock.TenantID)
+	require.NoError(t, err)
+	assert.Equal(t, []drone.Dock{dock}, docks)
+	_, err = s.Dock(ctx, "another-tenant", dock.ID)
+	assert.ErrorIs(t, err, drone.ErrDockNotFound)
+
+	at := time.Date(2023, time.January, 10, 12, 0, 0, 0, time.UTC)
+	session := drone.ChargingSession{TenantID: dock.TenantID, ID: "s1", DockID: dock.ID, Serial: "1", StartedAt: at, StartBattery: 20}
+	require.NoError(t, s.SaveChargingSession(ctx, session))
+	session.EndedAt, session.EndBattery = at.Add(time.Hour), 95
+	require.NoError(t, s.SaveChargingSession(ctx, session))
+	later := drone.ChargingSession{TenantID: dock.TenantID, ID: "s2", DockID: dock.ID, Serial: "2", StartedAt: at.Add(time.Minute), StartBattery: 50}
+	require.NoError(t, s.SaveChargingSession(ctx, later))
+	sessions, err := s.ChargingSessions(ctx, dock.TenantID, dock.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []drone.ChargingSession{session, later}, sessions)
+
+	assert.ErrorIs(t, s.DeleteDock(ctx, "another-tenant", dock.ID), drone.ErrDockNotFound)
+	require.NoError(t, s.DeleteDock(ctx, dock.TenantID, dock.ID))
+	docks, err = s.Docks(ctx, dock.TenantID)
+	require.NoError(t, err)
+	assert.Empty(t, docks)
+}
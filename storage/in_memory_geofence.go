@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+var _ drone.GeofenceStore = (*InMemory)(nil)
+
+// SaveGeofence implements drone.GeofenceStore
+func (s *InMemory) SaveGeofence(_ context.Context, g drone.Geofence) error {
+	s.geofenceMu.Lock()
+	defer s.geofenceMu.Unlock()
+	zones := s.geofencesByTenant[g.TenantID]
+	for i := range zones {
+		if zones[i].ID == g.ID {
+			zones[i] = g
+			return nil
+		}
+	}
+
+	s.geofencesByTenant[g.TenantID] = append(zones, g)
+	return nil
+}
+
+// Geofences implements drone.GeofenceStore
+func (s *InMemory) Geofences(_ context.Context, tenantID string) ([]drone.Geofence, error) {
+	s.geofenceMu.RLock()
+	defer s.geofenceMu.RUnlock()
+	zones := s.geofencesByTenant[tenantID]
+	return append(make([]drone.Geofence, 0, len(zones)), zones...), nil
+}
+
+// DeleteGeofence implements drone.GeofenceStore
+func (s *InMemory) DeleteGeofence(_ context.Context, tenantID, id string) error {
+	s.geofenceMu.Lock()
+	defer s.geofenceMu.Unlock()
+	zones := s.geofencesByTenant[tenantID]
+	for i := range zones {
+		if zones[i].ID == id {
+			s.geofencesByTenant[tenantID] = append(zones[:i:i], zones[i+1:]...)
+			return nil
+		}
+	}
+
+	return drone.ErrGeofenceNotFound
+}
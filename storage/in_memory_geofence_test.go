@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryGeofences(t *testing.T) {
+	t.Parallel()
+	testGeofenceStore(t, NewInMemory())
+}
+
+// testGeofenceStore checks the behaviour shared by every drone.GeofenceStore.
+func testGeofenceStore(t *testing.T, s drone.GeofenceStore) {
+	t.Helper()
+	ctx := context.Background()
+	g, err := drone.NewGeofence("hospital-geo", "Airport", []drone.Coordinates{
+		{Latitude: 40.47, Longitude: -3.58},
+		{Latitude: 40.47, Longitude: -3.54},
+		{Latitude: 40.51, Longitude: -3.54},
+		{Latitude: 40.47, Longitude: -3.58},
+	})
+	require.NoError(t, err)
+	require.NoError(t, s.SaveGeofence(ctx, g))
+
+	zones, err := s.Geofences(ctx, g.TenantID)
+	require.NoError(t, err)
+	assert.Equal(t, []drone.Geofence{g}, zones)
+	zones, err = s.Geofences(ctx, "another-tenant")
+	require.NoError(t, err)
+	assert.Empty(t, zones)
+
+	assert.ErrorIs(t, s.DeleteGeofence(ctx, "another-tenant", g.ID), drone.ErrGeofenceNotFound)
+	require.NoError(t, s.DeleteGeofence(ctx, g.TenantID, g.ID))
+	zones, err = s.Geofences(ctx, g.TenantID)
+	require.NoError(t, err)
+	assert.Empty(t, zones)
+}
@@ -17,6 +17,8 @@ var (
 )
 
 var (
+	// savedDroneTenant is the tenant of the test Drones.
+	savedDroneTenant = "hospital-a"
 	// savedDroneSerial is the serial of a test Drone.
 	savedDroneSerial = "45"
 	// savedDroneWithMedicationSerial is the serial of a test Drone.
@@ -27,6 +29,7 @@ func TestInMemorySaveDrone(t *testing.T) {
 	t.Parallel()
 	s := initializeTestInMemory(t)
 	d := drone.Drone{
+		TenantID:        savedDroneTenant,
 		Serial:          "1",
 		Model:           drone.Lightweight,
 		WeightLimit:     300,
@@ -36,14 +39,14 @@ func TestInMemorySaveDrone(t *testing.T) {
 
 	err := s.SaveDrone(context.Background(), d)
 	require.NoError(t, err)
-	savedDrone, _ := s.droneBySerial.Load(d.Serial)
+	savedDrone, _ := s.droneByKey.Load(droneKey{tenantID: d.TenantID, serial: d.Serial})
 	assert.Equal(t, d, savedDrone)
 }
 
 func TestInMemoryAddMedicationDrone(t *testing.T) {
 	t.Parallel()
 	s := initializeTestInMemory(t)
-	val, _ := s.droneBySerial.Load(savedDroneWithMedicationSerial)
+	val, _ := s.droneByKey.Load(droneKey{tenantID: savedDroneTenant, serial: savedDroneWithMedicationSerial})
 	d := val.(drone.Drone)
 	// add medication
 	newMedication := drone.Medication{
@@ -56,7 +59,7 @@ func TestInMemoryAddMedicationDrone(t *testing.T) {
 	// initialize Drone
 	err := s.SaveDrone(context.Background(), d)
 	require.NoError(t, err)
-	sd, _ := s.droneBySerial.Load(d.Serial)
+	sd, _ := s.droneByKey.Load(droneKey{tenantID: d.TenantID, serial: d.Serial})
 	savedDrone := sd.(drone.Drone)
 	assert.Equal(t, d, savedDrone)
 	assert.Len(t, savedDrone.Medications, len(d.Medications))
@@ -68,24 +71,33 @@ func TestInMemoryGetDrone(t *testing.T) {
 	testCases := []struct {
 		name     string
 		notFound bool
+		tenantID string
 		serial   string
 	}{
 		{
-			name:   "OK: existent drone",
-			serial: savedDroneSerial,
+			name:     "OK: existent drone",
+			tenantID: savedDroneTenant,
+			serial:   savedDroneSerial,
 		},
 		{
 			name:     "Err: Not Found",
+			tenantID: savedDroneTenant,
 			serial:   "qwerty",
 			notFound: true,
 		},
+		{
+			name:     "Err: Not Found in another tenant",
+			tenantID: "hospital-b",
+			serial:   savedDroneSerial,
+			notFound: true,
+		},
 	}
 
 	for _, v := range testCases {
 		tc := v
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			_, err := s.Drone(context.Background(), tc.serial)
+			_, err := s.Drone(context.Background(), tc.tenantID, tc.serial)
 			if tc.notFound {
 				require.Error(t, err)
 				assert.ErrorIs(t, err, drone.ErrNotFound)
@@ -100,9 +112,58 @@ func TestInMemoryGetDrone(t *testing.T) {
 func TestInMemoryGetDrones(t *testing.T) {
 	t.Parallel()
 	s := initializeTestInMemory(t)
-	drones, err := s.Drones(context.Background())
+	drones, err := s.Drones(context.Background(), savedDroneTenant)
 	require.NoError(t, err)
 	assert.GreaterOrEqual(t, len(drones), 2) // compares with preset number of drones (could be more)
+	for _, d := range drones {
+		assert.Equal(t, savedDroneTenant, d.TenantID)
+	}
+}
+
+func TestInMemoryGetAllDrones(t *testing.T) {
+	t.Parallel()
+	s := initializeTestInMemory(t)
+	err := s.SaveDrone(context.Background(), drone.Drone{TenantID: ProbeTenant, Serial: "probe", State: drone.Idle})
+	require.NoError(t, err)
+
+	drones, err := s.AllDrones(context.Background())
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, len(drones), 3) // compares with preset number of drones of every tenant (could be more)
+	for _, d := range drones {
+		assert.NotEqual(t, ProbeTenant, d.TenantID) // the health probe isn't part of the fleet
+	}
+}
+
+func TestInMemoryOutbox(t *testing.T) {
+	t.Parallel()
+	s := NewInMemory()
+	d, err := drone.NewDrone(drone.DefaultPolicy(), savedDroneTenant, "70", drone.Lightweight, 300, 80)
+	require.NoError(t, err)
+	d.ChangeState(drone.Loading)
+	events := d.Events()
+
+	err = s.SaveDrone(context.Background(), d)
+	require.NoError(t, err)
+	saved, err := s.Drone(context.Background(), savedDroneTenant, "70")
+	require.NoError(t, err)
+	assert.Empty(t, saved.Events()) // the events are committed to the outbox
+
+	// saving the same aggregate twice doesn't duplicate the events
+	err = s.SaveDrone(context.Background(), d)
+	require.NoError(t, err)
+	pending, err := s.PendingEvents(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, events, pending)
+
+	pending, err = s.PendingEvents(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, events[:1], pending)
+
+	err = s.MarkDispatched(context.Background(), events[0].ID)
+	require.NoError(t, err)
+	pending, err = s.PendingEvents(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, events[1:], pending)
 }
 
 func initializeTestInMemory(t *testing.T) *InMemory {
@@ -111,7 +172,8 @@ func initializeTestInMemory(t *testing.T) *InMemory {
 		// setup storage
 		testInMemory = NewInMemory()
 		// add preset data for test
-		testInMemory.droneBySerial.Store(savedDroneSerial, drone.Drone{
+		testInMemory.droneByKey.Store(droneKey{tenantID: savedDroneTenant, serial: savedDroneSerial}, drone.Drone{
+			TenantID:        savedDroneTenant,
 			Serial:          savedDroneSerial,
 			Model:           drone.Lightweight,
 			WeightLimit:     300,
@@ -119,7 +181,8 @@ func initializeTestInMemory(t *testing.T) *InMemory {
 			State:           drone.Idle,
 		})
 
-		testInMemory.droneBySerial.Store(savedDroneWithMedicationSerial, drone.Drone{
+		testInMemory.droneByKey.Store(droneKey{tenantID: savedDroneTenant, serial: savedDroneWithMedicationSerial}, drone.Drone{
+			TenantID:        savedDroneTenant,
 			Serial:          savedDroneWithMedicationSerial,
 			Model:           drone.Heavyweight,
 			WeightLimit:     400,
@@ -134,6 +197,15 @@ func initializeTestInMemory(t *testing.T) *InMemory {
 				},
 			},
 		})
+
+		testInMemory.droneByKey.Store(droneKey{tenantID: "hospital-b", serial: "60"}, drone.Drone{
+			TenantID:        "hospital-b",
+			Serial:          "60",
+			Model:           drone.Middleweight,
+			WeightLimit:     200,
+			BatteryCapacity: 60,
+			State:           drone.Idle,
+		})
 	})
 
 	return testInMemory
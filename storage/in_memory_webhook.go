@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/hsequeda/drone/webhook"
+)
+
+var _ webhook.Store = (*InMemory)(nil)
+
+// SaveSubscription implements webhook.Store
+func (s *InMemory) SaveSubscription(_ context.Context, sub webhook.Subscription) error {
+	s.webhookMu.Lock()
+	defer s.webhookMu.Unlock()
+	subs := s.subscriptionsByTenant[sub.TenantID]
+	for i := range subs {
+		if subs[i].ID == sub.ID {
+			subs[i] = sub
+			return nil
+		}
+	}
+
+	s.subscriptionsByTenant[sub.TenantID] = append(subs, sub)
+	return nil
+}
+
+// Subscriptions implements webhook.Store
+func (s *InMemory) Subscriptions(_ context.Context, tenantID string) ([]webhook.Subscription, error) {
+	s.webhookMu.RLock()
+	defer s.webhookMu.RUnlock()
+	subs := s.subscriptionsByTenant[tenantID]
+	return append(make([]webhook.Subscription, 0, len(subs)), subs...), nil
+}
+
+// DeleteSubscription implements webhook.Store
+func (s *InMemory) DeleteSubscription(_ context.Context, tenantID, id string) error {
+	s.webhookMu.Lock()
+	defer s.webhookMu.Unlock()
+	subs := s.subscriptionsByTenant[tenantID]
+	for i := range subs {
+		if subs[i].ID == id {
+			s.subscriptionsByTenant[tenantID] = append(subs[:i:i], subs[i+1:]...)
+			return nil
+		}
+	}
+
+	return webhook.ErrNotFound
+}
+
+// AppendDelivery implements webhook.Store
+func (s *InMemory) AppendDelivery(_ context.Context, d webhook.Delivery) error {
+	s.webhookMu.Lock()
+	defer s.webhookMu.Unlock()
+	k := d.TenantID + "/" + d.SubscriptionID
+	s.deliveriesBySub[k] = append(s.deliveriesBySub[k], d)
+	return nil
+}
+
+// Deliveries implements webhook.Store
+func (s *InMemory) Deliveries(_ context.Context, tenantID, subscriptionID string) ([]webhook.Delivery, error) {
+	s.webhookMu.RLock()
+	defer s.webhookMu.RUnlock()
+	deliveries := s.deliveriesBySub[tenantID+"/"+subscriptionID]
+	return append(make([]webhook.Delivery, 0, len(deliveries)), deliveries...), nil
+}
+
+// AppendDeadLetter implements webhook.Store
+func (s *InMemory) AppendDeadLetter(_ context.Context, dl webhook.DeadLetter) error {
+	s.webhookMu.Lock()
+	defer s.webhookMu.Unlock()
+	s.deadLettersByTenant[dl.TenantID] = append(s.deadLettersByTenant[dl.TenantID], dl)
+	return nil
+}
+
+// DeadLetters implements webhook.Store
+func (s *InMemory) DeadLetters(_ context.Context, tenantID string) ([]webhook.DeadLetter, error) {
+	s.webhookMu.RLock()
+	defer s.webhookMu.RUnlock()
+	dls := s.deadLettersByTenant[tenantID]
+	return append(make([]webhook.DeadLetter, 0, len(dls)), dls...), nil
+}
+
+// SavePendingDelivery implements webhook.Store
+func (s *InMemory) SavePendingDelivery(_ context.Context, p webhook.PendingDelivery) error {
+	s.webhookMu.Lock()
+	defer s.webhookMu.Unlock()
+	s.pendingDeliveries[p.ID] = p
+	return nil
+}
+
+// PendingDeliveries implements webhook.Store
+func (s *InMemory) PendingDeliveries(_ context.Context) ([]webhook.PendingDelivery, error) {
+	s.webhookMu.RLock()
+	defer s.webhookMu.RUnlock()
+	pending := make([]webhook.PendingDelivery, 0, len(s.pendingDeliveries))
+	for _, p := range s.pendingDeliveries {
+		pending = append(pending, p)
+	}
+
+	return pending, nil
+}
+
+// DeletePendingDelivery implements webhook.Store
+func (s *InMemory) DeletePendingDelivery(_ context.Context, id string) error {
+	s.webhookMu.Lock()
+	defer s.webhookMu.Unlock()
+	delete(s.pendingDeliveries, id)
+	return nil
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryWebhooks(t *testing.T) {
+	t.Parallel()
+	testWebhookStore(t, NewInMemory())
+}
+
+// testWebhookStore checks the behaviour shared by every webhook.Store.
+func testWebhookStore(t *testing.T, s webhook.Store) {
+	t.Helper()
+	ctx := context.Background()
+	at := time.Date(2023, time.January, 10, 12, 0, 0, 0, time.UTC)
+	sub := webhook.Subscription{
+		ID:         "sub-1",
+		TenantID:   "hospital-wh",
+		URL:        "http://hospital.local/hook",
+		Secret:     "s3cr3t",
+		EventTypes: []drone.EventType{drone.BatteryLow},
+		CreatedAt:  at,
+	}
+	require.NoError(t, s.SaveSubscription(ctx, sub))
+
+	subs, err := s.Subscriptions(ctx, sub.TenantID)
+	require.NoError(t, err)
+	assert.Equal(t, []webhook.Subscription{sub}, subs)
+	subs, err = s.Subscriptions(ctx, "another-tenant")
+	require.NoError(t, err)
+	assert.Empty(t, subs)
+
+	deliveries := []webhook.Delivery{
+		{SubscriptionID: sub.ID, TenantID: sub.TenantID, EventID: "e1", EventType: drone.BatteryLow, Attempt: 1, StatusCode: 500, Error: "unexpected status 500", At: at},
+		{SubscriptionID: sub.ID, TenantID: sub.TenantID, EventID: "e1", EventType: drone.BatteryLow, Attempt: 2, StatusCode: 200, Succeeded: true, At: at.Add(time.Second)},
+	}
+	for _, d := range deliveries {
+		require.NoError(t, s.AppendDelivery(ctx, d))
+	}
+
+	got, err := s.Deliveries(ctx, sub.TenantID, sub.ID)
+	require.NoError(t, err)
+	assert.Equal(t, deliveries, got)
+
+	dl := webhook.DeadLetter{SubscriptionID: sub.ID, TenantID: sub.TenantID, EventID: "e2", EventType: drone.BatteryLow, Payload: []byte(`{}`), Attempts: 5, LastError: "timeout", At: at}
+	require.NoError(t, s.AppendDeadLetter(ctx, dl))
+	dls, err := s.DeadLetters(ctx, sub.TenantID)
+	require.NoError(t, err)
+	assert.Equal(t, []webhook.DeadLetter{dl}, dls)
+
+	assert.ErrorIs(t, s.DeleteSubscription(ctx, "another-tenant", sub.ID), webhook.ErrNotFound)
+	require.NoError(t, s.DeleteSubscription(ctx, sub.TenantID, sub.ID))
+	subs, err = s.Subscriptions(ctx, sub.TenantID)
+	require.NoError(t, err)
+	assert.Empty(t, subs)
+}
@@ -6,6 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path"
+	"path/filepath"
+	"strings"
 
 	"github.com/hsequeda/drone/drone"
 	"github.com/sdomino/scribble"
@@ -13,9 +16,19 @@ import (
 
 const (
 	// droneCollection const is the key for the drone collection in scribble db.
+	// NOTE: each tenant owns a sub-collection (drone/<tenant_id>).
 	droneCollection = "drone"
+	// tenantCollection const is the key for the index of known tenants in scribble db.
+	tenantCollection = "tenant"
+	// outboxCollection const is the key for the pending domain events in scribble db.
+	outboxCollection = "outbox"
 )
 
+// tenantRecord is the entry stored in the tenant index.
+type tenantRecord struct {
+	ID string
+}
+
 type JSON struct {
 	db *scribble.Driver
 }
@@ -25,9 +38,14 @@ func NewJSON(db *scribble.Driver) *JSON {
 }
 
 // Drone implements drone.Storage
-func (j *JSON) Drone(ctx context.Context, serial string) (drone.Drone, error) {
+func (j *JSON) Drone(ctx context.Context, tenantID, serial string) (drone.Drone, error) {
+	// NOTE: no drone can be saved with an unsafe name.
+	if checkResource(tenantID) != nil || checkResource(serial) != nil {
+		return drone.Drone{}, drone.ErrNotFound
+	}
+
 	var d drone.Drone
-	if err := j.db.Read(droneCollection, serial, &d); err != nil {
+	if err := j.db.Read(tenantDroneCollection(tenantID), serial, &d); err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			return drone.Drone{}, drone.ErrNotFound
 		}
@@ -39,9 +57,13 @@ func (j *JSON) Drone(ctx context.Context, serial string) (drone.Drone, error) {
 }
 
 // Drones implements drone.Storage
-func (j *JSON) Drones(ctx context.Context) ([]drone.Drone, error) {
-	resp, err := j.db.ReadAll(droneCollection)
+func (j *JSON) Drones(ctx context.Context, tenantID string) ([]drone.Drone, error) {
+	resp, err := j.db.ReadAll(tenantDroneCollection(tenantID))
 	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []drone.Drone{}, nil
+		}
+
 		return nil, errors.New("fetch all drones")
 	}
 
@@ -58,13 +80,163 @@ func (j *JSON) Drones(ctx context.Context) ([]drone.Drone, error) {
 	return drones, nil
 }
 
+// AllDrones implements drone.Storage, leaving out the drones of ProbeTenant.
+func (j *JSON) AllDrones(ctx context.Context) ([]drone.Drone, error) {
+	resp, err := j.db.ReadAll(tenantCollection)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []drone.Drone{}, nil
+		}
+
+		return nil, errors.New("fetch all tenants")
+	}
+
+	drones := make([]drone.Drone, 0)
+	for _, v := range resp {
+		var t tenantRecord
+		if err = json.Unmarshal(v, &t); err != nil {
+			return nil, fmt.Errorf("decode tenant: %w", err)
+		}
+
+		if t.ID == ProbeTenant {
+			continue
+		}
+
+		tenantDrones, err := j.Drones(ctx, t.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		drones = append(drones, tenantDrones...)
+	}
+
+	return drones, nil
+}
+
 // SaveDrone implements drone.Storage
+// NOTE: the pending events are written to the outbox before the drone, so a
+// failed write never loses events (though they could describe a not saved change).
 func (j *JSON) SaveDrone(ctx context.Context, d drone.Drone) error {
-	if err := j.db.Write(droneCollection, d.Serial, d); err != nil {
+	if err := checkResource(d.TenantID); err != nil {
+		return fmt.Errorf("save drone: %w", err)
+	}
+
+	if err := checkResource(d.Serial); err != nil {
+		return fmt.Errorf("save drone: %w", err)
+	}
+
+	if err := j.db.Write(tenantCollection, d.TenantID, tenantRecord{ID: d.TenantID}); err != nil {
+		return fmt.Errorf("save tenant: %w", err)
+	}
+
+	for _, e := range d.Events() {
+		if err := j.db.Write(outboxCollection, e.ID, e); err != nil {
+			return fmt.Errorf("save event: %w", err)
+		}
+	}
+
+	d.ClearEvents()
+	if err := j.db.Write(tenantDroneCollection(d.TenantID), d.Serial, d); err != nil {
 		return fmt.Errorf("save drone: %w", err)
 	}
 
 	return nil
 }
 
-var _ drone.Storage = (*JSON)(nil)
+// PendingEvents implements drone.Outbox
+func (j *JSON) PendingEvents(ctx context.Context, limit int) ([]drone.Event, error) {
+	resp, err := j.db.ReadAll(outboxCollection)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []drone.Event{}, nil
+		}
+
+		return nil, errors.New("fetch pending events")
+	}
+
+	events := make([]drone.Event, len(resp))
+	for i, v := range resp {
+		if err = json.Unmarshal(v, &events[i]); err != nil {
+			return nil, fmt.Errorf("decode event: %w", err)
+		}
+	}
+
+	return sortEvents(events, limit), nil
+}
+
+// MarkDispatched implements drone.Outbox
+func (j *JSON) MarkDispatched(ctx context.Context, ids ...string) error {
+	for _, id := range ids {
+		if err := j.db.Delete(outboxCollection, id); err != nil {
+			return fmt.Errorf("delete event %q: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// tenantDroneCollection returns the drone collection partition of a tenant.
+func tenantDroneCollection(tenantID string) string {
+	return path.Join(droneCollection, tenantID)
+}
+
+// checkResource returns an error if the name can't be used as a scribble
+// resource or collection without leaving its directory (as `../tenant/serial`).
+func checkResource(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) || filepath.Clean(name) != name {
+		return fmt.Errorf("invalid resource name %q", name)
+	}
+
+	return nil
+}
+
+// MigrateLegacyDrones moves the drones saved before the tenants existed (the
+// `drone/<serial>.json` files of the dir of the database) into the tenant,
+// returning how many were moved. The migrated files are removed, so it's a
+// no-op once done.
+func MigrateLegacyDrones(ctx context.Context, j *JSON, dir, tenantID string) (int, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, droneCollection))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+
+		return 0, fmt.Errorf("list legacy drones: %w", err)
+	}
+
+	migrated := 0
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+
+		file := filepath.Join(dir, droneCollection, e.Name())
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return migrated, fmt.Errorf("read legacy drone: %w", err)
+		}
+
+		var d drone.Drone
+		if err := json.Unmarshal(b, &d); err != nil {
+			return migrated, fmt.Errorf("decode legacy drone %s: %w", e.Name(), err)
+		}
+
+		d.TenantID = tenantID
+		if err := j.SaveDrone(ctx, d); err != nil {
+			return migrated, fmt.Errorf("migrate drone %s: %w", d.Serial, err)
+		}
+
+		if err := os.Remove(file); err != nil {
+			return migrated, fmt.Errorf("remove legacy drone: %w", err)
+		}
+
+		migrated++
+	}
+
+	return migrated, nil
+}
+
+var (
+	_ drone.Storage = (*JSON)(nil)
+	_ drone.Outbox  = (*JSON)(nil)
+)
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+const (
+	// dockCollection const is the key for the charging docks in scribble db.
+	// NOTE: each tenant owns a sub-collection (dock/<tenant_id>).
+	dockCollection = "dock"
+	// chargingSessionCollection const is the key for the charging sessions in scribble db
+	// (charging_session/<tenant_id>/<dock_id>).
+	chargingSessionCollection = "charging_session"
+)
+
+var _ drone.DockStore = (*JSON)(nil)
+
+// SaveDock implements drone.DockStore
+func (j *JSON) SaveDock(ctx context.Context, d drone.Dock) error {
+	if err := j.db.Write(path.Join(dockCollection, d.TenantID), d.ID, d); err != nil {
+		return fmt.Errorf("save dock: %w", err)
+	}
+
+	return nil
+}
+
+// Dock implements drone.DockStore
+func (j *JSON) Dock(ctx context.Context, tenantID, id string) (drone.Dock, error) {
+	var d drone.Dock
+	if err := j.db.Read(path.Join(dockCollection, tenantID), id, &d); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return drone.Dock{}, drone.ErrDockNotFound
+		}
+
+		return drone.Dock{}, fmt.Errorf("read dock: %w", err)
+	}
+
+	return d, nil
+}
+
+// Docks implements drone.DockStore
+func (j *JSON) Docks(ctx context.Context, tenantID string) ([]drone.Dock, error) {
+	docks := make([]drone.Dock, 0)
+	if err := j.readAll(path.Join(dockCollection, tenantID), func(b []byte) error {
+		var d drone.Dock
+		if err := json.Unmarshal(b, &d); err != nil {
+			return fmt.Errorf("decode dock: %w", err)
+		}
+
+		docks = append(docks, d)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return docks, nil
+}
+
+// DeleteDock implements drone.DockStore
+func (j *JSON) DeleteDock(ctx context.Context, tenantID, id string) error {
+	if _, err := j.Dock(ctx, tenantID, id); err != nil {
+		return err
+	}
+
+	if err := j.db.Delete(path.Join(dockCollection, tenantID), id); err != nil {
+		return fmt.Errorf("delete dock: %w", err)
+	}
+
+	return nil
+}
+
+// SaveChargingSession implements drone.DockStore
+// NOTE: the resource name starts with the start time so the sessions sort by it.
+func (j *JSON) SaveChargingSession(ctx context.Context, s drone.ChargingSession) error {
+	resource := fmt.Sprintf("%020d-%s", s.StartedAt.UnixNano(), s.ID)
+	if err := j.db.Write(path.Join(chargingSessionCollection, s.TenantID, s.DockID), resource, s); err != nil {
+		return fmt.Errorf("save charging session: %w", err)
+	}
+
+	return nil
+}
+
+// ChargingSessions implements drone.DockStore
+func (j *JSON) ChargingSessions(ctx context.Context, tenantID, dockID string) ([]drone.ChargingSession, error) {
+	sessions := make([]drone.ChargingSession, 0)
+	if err := j.readAll(path.Join(chargingSessionCollection, tenantID, dockID), func(b []byte) error {
+		var s drone.ChargingSession
+		if err := json.Unmarshal(b, &s); err != nil {
+			return fmt.Errorf("decode charging session: %w", err)
+		}
+
+		sessions = append(sessions, s)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
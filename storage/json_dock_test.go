@@ -0,0 +1,10 @@
+package storage
+
+import (
+	"testing"
+)
+
+func (s *jsonSuite) TestDocks(t *testing.T) {
+	t.Parallel()
+	testDockStore(t, s.storage)
+}
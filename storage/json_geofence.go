@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// geofenceCollection const is the key for the no-fly zones in scribble db.
+// NOTE: each tenant owns a sub-collection (geofence/<tenant_id>).
+const geofenceCollection = "geofence"
+
+var _ drone.GeofenceStore = (*JSON)(nil)
+
+// SaveGeofence implements drone.GeofenceStore
+func (j *JSON) SaveGeofence(ctx context.Context, g drone.Geofence) error {
+	if err := j.db.Write(path.Join(geofenceCollection, g.TenantID), g.ID, g); err != nil {
+		return fmt.Errorf("save geofence: %w", err)
+	}
+
+	return nil
+}
+
+// Geofences implements drone.GeofenceStore
+func (j *JSON) Geofences(ctx context.Context, tenantID string) ([]drone.Geofence, error) {
+	zones := make([]drone.Geofence, 0)
+	if err := j.readAll(path.Join(geofenceCollection, tenantID), func(b []byte) error {
+		var g drone.Geofence
+		if err := json.Unmarshal(b, &g); err != nil {
+			return fmt.Errorf("decode geofence: %w", err)
+		}
+
+		zones = append(zones, g)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return zones, nil
+}
+
+// DeleteGeofence implements drone.GeofenceStore
+func (j *JSON) DeleteGeofence(ctx context.Context, tenantID, id string) error {
+	var g drone.Geofence
+	if err := j.db.Read(path.Join(geofenceCollection, tenantID), id, &g); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return drone.ErrGeofenceNotFound
+		}
+
+		return fmt.Errorf("read geofence: %w", err)
+	}
+
+	if err := j.db.Delete(path.Join(geofenceCollection, tenantID), id); err != nil {
+		return fmt.Errorf("delete geofence: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,10 @@
+package storage
+
+import (
+	"testing"
+)
+
+func (s *jsonSuite) TestGeofences(t *testing.T) {
+	t.Parallel()
+	testGeofenceStore(t, s.storage)
+}
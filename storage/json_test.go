@@ -26,6 +26,7 @@ func TestJSON(t *testing.T) {
 	s.db = db
 	s.storage = NewJSON(db)
 	s.presetDrones = append(s.presetDrones, drone.Drone{
+		TenantID:        "hospital-a",
 		Serial:          "101",
 		Model:           drone.Heavyweight,
 		WeightLimit:     439,
@@ -33,6 +34,7 @@ func TestJSON(t *testing.T) {
 		State:           drone.Idle,
 	},
 		drone.Drone{
+			TenantID:        "hospital-b",
 			Serial:          "102",
 			Model:           drone.Cruiserweight,
 			WeightLimit:     100,
@@ -41,21 +43,30 @@ func TestJSON(t *testing.T) {
 		},
 	)
 
-	err = s.db.Write(droneCollection, s.presetDrones[0].Serial, s.presetDrones[0])
-	require.NoError(t, err)
-	err = s.db.Write(droneCollection, s.presetDrones[1].Serial, s.presetDrones[1])
-	require.NoError(t, err)
+	for _, d := range s.presetDrones {
+		err = s.db.Write(tenantCollection, d.TenantID, tenantRecord{ID: d.TenantID})
+		require.NoError(t, err)
+		err = s.db.Write(tenantDroneCollection(d.TenantID), d.Serial, d)
+		require.NoError(t, err)
+	}
 
 	t.Cleanup(func() { os.RemoveAll("../test/test_json_data") })
 
 	t.Run("TestSaveDrone", s.TestSaveDrone)
+	t.Run("TestSaveDroneUnsafeSerial", s.TestSaveDroneUnsafeSerial)
 	t.Run("TestGetDrone", s.TestGetDrone)
 	t.Run("TestGetDrones", s.TestGetDrones)
+	t.Run("TestGetAllDrones", s.TestGetAllDrones)
+	t.Run("TestOutbox", s.TestOutbox)
+	t.Run("TestWebhooks", s.TestWebhooks)
+	t.Run("TestGeofences", s.TestGeofences)
+	t.Run("TestDocks", s.TestDocks)
 }
 
 func (s *jsonSuite) TestSaveDrone(t *testing.T) {
 	t.Parallel()
 	d := drone.Drone{
+		TenantID:        "hospital-a",
 		Serial:          "1",
 		Model:           drone.Lightweight,
 		WeightLimit:     300,
@@ -75,40 +86,81 @@ func (s *jsonSuite) TestSaveDrone(t *testing.T) {
 	require.NoError(t, err)
 
 	var expected drone.Drone
-	err = s.db.Read(droneCollection, d.Serial, &expected)
+	err = s.db.Read(tenantDroneCollection(d.TenantID), d.Serial, &expected)
 	require.NoError(t, err)
 	assert.Equal(t, expected, d)
 }
 
+func (s *jsonSuite) TestSaveDroneUnsafeSerial(t *testing.T) {
+	t.Parallel()
+	d := drone.Drone{TenantID: "hospital-a", Serial: "../hospital-b/X", State: drone.Idle}
+	require.Error(t, s.storage.SaveDrone(context.Background(), d))
+
+	_, err := s.storage.Drone(context.Background(), "hospital-a", "../hospital-b/102")
+	assert.ErrorIs(t, err, drone.ErrNotFound)
+}
+
+func TestMigrateLegacyDrones(t *testing.T) {
+	dir := t.TempDir()
+	db, err := scribble.New(dir, nil)
+	require.NoError(t, err)
+	legacy := drone.Drone{Serial: "7", Model: drone.Lightweight, WeightLimit: 200, BatteryCapacity: 60, State: drone.Idle}
+	require.NoError(t, db.Write(droneCollection, legacy.Serial, legacy))
+
+	j := NewJSON(db)
+	migrated, err := MigrateLegacyDrones(context.Background(), j, dir, "hospital-a")
+	require.NoError(t, err)
+	assert.Equal(t, 1, migrated)
+
+	d, err := j.Drone(context.Background(), "hospital-a", "7")
+	require.NoError(t, err)
+	legacy.TenantID = "hospital-a"
+	assert.Equal(t, legacy, d)
+
+	// the migrated drones aren't migrated again
+	migrated, err = MigrateLegacyDrones(context.Background(), j, dir, "hospital-a")
+	require.NoError(t, err)
+	assert.Zero(t, migrated)
+}
+
 func (s *jsonSuite) TestGetDrone(t *testing.T) {
 	t.Parallel()
 
-	err := s.db.Write(droneCollection, s.presetDrones[0].Serial, s.presetDrones[0])
+	err := s.db.Write(tenantDroneCollection(s.presetDrones[0].TenantID), s.presetDrones[0].Serial, s.presetDrones[0])
 	require.NoError(t, err)
 
 	testCases := []struct {
 		name     string
 		notFound bool
+		tenantID string
 		serial   string
 		expected drone.Drone
 	}{
 		{
 			name:     "OK: existent drone",
+			tenantID: s.presetDrones[0].TenantID,
 			serial:   s.presetDrones[0].Serial,
 			expected: s.presetDrones[0],
 		},
 		{
 			name:     "Err: Not Found",
+			tenantID: s.presetDrones[0].TenantID,
 			serial:   "qwerty",
 			notFound: true,
 		},
+		{
+			name:     "Err: Not Found in another tenant",
+			tenantID: s.presetDrones[1].TenantID,
+			serial:   s.presetDrones[0].Serial,
+			notFound: true,
+		},
 	}
 
 	for _, v := range testCases {
 		tc := v
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			d, err := s.storage.Drone(context.Background(), tc.serial)
+			d, err := s.storage.Drone(context.Background(), tc.tenantID, tc.serial)
 			if tc.notFound {
 				require.Error(t, err)
 				assert.ErrorIs(t, err, drone.ErrNotFound)
@@ -123,7 +175,55 @@ func (s *jsonSuite) TestGetDrone(t *testing.T) {
 
 func (s *jsonSuite) TestGetDrones(t *testing.T) {
 	t.Parallel()
-	drones, err := s.storage.Drones(context.Background())
+	drones, err := s.storage.Drones(context.Background(), s.presetDrones[1].TenantID)
+	require.NoError(t, err)
+	require.Len(t, drones, 1)
+	assert.Equal(t, s.presetDrones[1], drones[0])
+
+	drones, err = s.storage.Drones(context.Background(), "unknown-tenant")
+	require.NoError(t, err)
+	assert.Empty(t, drones)
+}
+
+func (s *jsonSuite) TestGetAllDrones(t *testing.T) {
+	t.Parallel()
+	err := s.storage.SaveDrone(context.Background(), drone.Drone{TenantID: ProbeTenant, Serial: "probe", State: drone.Idle})
+	require.NoError(t, err)
+
+	drones, err := s.storage.AllDrones(context.Background())
 	require.NoError(t, err)
 	assert.GreaterOrEqual(t, len(drones), 2) // compares with preset number of drones (could be more)
+	for _, d := range drones {
+		assert.NotEqual(t, ProbeTenant, d.TenantID) // the health probe isn't part of the fleet
+	}
+}
+
+func (s *jsonSuite) TestOutbox(t *testing.T) {
+	t.Parallel()
+	d, err := drone.NewDrone(drone.DefaultPolicy(), "hospital-c", "1", drone.Middleweight, 300, 80)
+	require.NoError(t, err)
+	d.ChangeState(drone.Loading)
+	events := d.Events()
+
+	err = s.storage.SaveDrone(context.Background(), d)
+	require.NoError(t, err)
+	saved, err := s.storage.Drone(context.Background(), "hospital-c", "1")
+	require.NoError(t, err)
+	assert.Empty(t, saved.Events()) // the events are committed to the outbox
+
+	pending, err := s.storage.PendingEvents(context.Background(), 10)
+	require.NoError(t, err)
+	require.Len(t, pending, len(events))
+	for i := range events {
+		assert.Equal(t, events[i].ID, pending[i].ID)
+		assert.Equal(t, events[i].Type, pending[i].Type)
+		assert.True(t, events[i].OccurredAt.Equal(pending[i].OccurredAt))
+	}
+
+	err = s.storage.MarkDispatched(context.Background(), events[0].ID)
+	require.NoError(t, err)
+	pending, err = s.storage.PendingEvents(context.Background(), 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, events[1].ID, pending[0].ID)
 }
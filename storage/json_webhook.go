@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/hsequeda/drone/webhook"
+)
+
+const (
+	// webhookCollection const is the key for the webhook subscriptions in scribble db.
+	// NOTE: each tenant owns a sub-collection (webhook/<tenant_id>).
+	webhookCollection = "webhook"
+	// webhookDeliveryCollection const is the key for the delivery history in scribble db
+	// (webhook_delivery/<tenant_id>/<subscription_id>).
+	webhookDeliveryCollection = "webhook_delivery"
+	// webhookDeadLetterCollection const is the key for the dead-letter list in scribble db
+	// (webhook_dead_letter/<tenant_id>).
+	webhookDeadLetterCollection = "webhook_dead_letter"
+	// webhookPendingCollection const is the key for the pending deliveries of every tenant in scribble db.
+	webhookPendingCollection = "webhook_pending"
+)
+
+var _ webhook.Store = (*JSON)(nil)
+
+// SaveSubscription implements webhook.Store
+func (j *JSON) SaveSubscription(ctx context.Context, s webhook.Subscription) error {
+	if err := j.db.Write(path.Join(webhookCollection, s.TenantID), s.ID, s); err != nil {
+		return fmt.Errorf("save subscription: %w", err)
+	}
+
+	return nil
+}
+
+// Subscriptions implements webhook.Store
+func (j *JSON) Subscriptions(ctx context.Context, tenantID string) ([]webhook.Subscription, error) {
+	subs := make([]webhook.Subscription, 0)
+	if err := j.readAll(path.Join(webhookCollection, tenantID), func(b []byte) error {
+		var s webhook.Subscription
+		if err := json.Unmarshal(b, &s); err != nil {
+			return fmt.Errorf("decode subscription: %w", err)
+		}
+
+		subs = append(subs, s)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return subs, nil
+}
+
+// DeleteSubscription implements webhook.Store
+func (j *JSON) DeleteSubscription(ctx context.Context, tenantID, id string) error {
+	var s webhook.Subscription
+	if err := j.db.Read(path.Join(webhookCollection, tenantID), id, &s); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return webhook.ErrNotFound
+		}
+
+		return fmt.Errorf("read subscription: %w", err)
+	}
+
+	if err := j.db.Delete(path.Join(webhookCollection, tenantID), id); err != nil {
+		return fmt.Errorf("delete subscription: %w", err)
+	}
+
+	return nil
+}
+
+// AppendDelivery implements webhook.Store
+func (j *JSON) AppendDelivery(ctx context.Context, d webhook.Delivery) error {
+	resource := fmt.Sprintf("%020d-%s-%d", d.At.UnixNano(), d.EventID, d.Attempt)
+	if err := j.db.Write(path.Join(webhookDeliveryCollection, d.TenantID, d.SubscriptionID), resource, d); err != nil {
+		return fmt.Errorf("save delivery: %w", err)
+	}
+
+	return nil
+}
+
+// Deliveries implements webhook.Store
+func (j *JSON) Deliveries(ctx context.Context, tenantID, subscriptionID string) ([]webhook.Delivery, error) {
+	deliveries := make([]webhook.Delivery, 0)
+	if err := j.readAll(path.Join(webhookDeliveryCollection, tenantID, subscriptionID), func(b []byte) error {
+		var d webhook.Delivery
+		if err := json.Unmarshal(b, &d); err != nil {
+			return fmt.Errorf("decode delivery: %w", err)
+		}
+
+		deliveries = append(deliveries, d)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// AppendDeadLetter implements webhook.Store
+func (j *JSON) AppendDeadLetter(ctx context.Context, dl webhook.DeadLetter) error {
+	resource := fmt.Sprintf("%020d-%s-%s", dl.At.UnixNano(), dl.SubscriptionID, dl.EventID)
+	if err := j.db.Write(path.Join(webhookDeadLetterCollection, dl.TenantID), resource, dl); err != nil {
+		return fmt.Errorf("save dead letter: %w", err)
+	}
+
+	return nil
+}
+
+// DeadLetters implements webhook.Store
+func (j *JSON) DeadLetters(ctx context.Context, tenantID string) ([]webhook.DeadLetter, error) {
+	dls := make([]webhook.DeadLetter, 0)
+	if err := j.readAll(path.Join(webhookDeadLetterCollection, tenantID), func(b []byte) error {
+		var dl webhook.DeadLetter
+		if err := json.Unmarshal(b, &dl); err != nil {
+			return fmt.Errorf("decode dead letter: %w", err)
+		}
+
+		dls = append(dls, dl)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return dls, nil
+}
+
+// SavePendingDelivery implements webhook.Store
+func (j *JSON) SavePendingDelivery(ctx context.Context, p webhook.PendingDelivery) error {
+	if err := checkResource(p.ID); err != nil {
+		return fmt.Errorf("save pending delivery: %w", err)
+	}
+
+	if err := j.db.Write(webhookPendingCollection, p.ID, p); err != nil {
+		return fmt.Errorf("save pending delivery: %w", err)
+	}
+
+	return nil
+}
+
+// PendingDeliveries implements webhook.Store
+func (j *JSON) PendingDeliveries(ctx context.Context) ([]webhook.PendingDelivery, error) {
+	pending := make([]webhook.PendingDelivery, 0)
+	if err := j.readAll(webhookPendingCollection, func(b []byte) error {
+		var p webhook.PendingDelivery
+		if err := json.Unmarshal(b, &p); err != nil {
+			return fmt.Errorf("decode pending delivery: %w", err)
+		}
+
+		pending = append(pending, p)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return pending, nil
+}
+
+// DeletePendingDelivery implements webhook.Store
+func (j *JSON) DeletePendingDelivery(ctx context.Context, id string) error {
+	if err := checkResource(id); err != nil {
+		// an unsafe id is never saved.
+		return nil
+	}
+
+	if err := j.db.Delete(webhookPendingCollection, id); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("delete pending delivery: %w", err)
+	}
+
+	return nil
+}
+
+// readAll calls fn with every record of the collection (sorted by resource name).
+// NOTE: a collection that doesn't exist yet is empty.
+func (j *JSON) readAll(collection string, fn func([]byte) error) error {
+	resp, err := j.db.ReadAll(collection)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("fetch %s: %w", collection, err)
+	}
+
+	for _, b := range resp {
+		if err = fn(b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
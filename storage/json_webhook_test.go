@@ -0,0 +1,10 @@
+package storage
+
+import (
+	"testing"
+)
+
+func (s *jsonSuite) TestWebhooks(t *testing.T) {
+	t.Parallel()
+	testWebhookStore(t, s.storage)
+}
@@ -0,0 +1,17 @@
+package storage
+
+import (
+	"sort"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// sortEvents sorts the events by ID (the order they occurred) and truncates the list to limit.
+func sortEvents(events []drone.Event, limit int) []drone.Event {
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	if limit > 0 && len(events) > limit {
+		events = events[:limit]
+	}
+
+	return events
+}
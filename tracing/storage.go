@@ -0,0 +1,195 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/storage"
+	"github.com/hsequeda/drone/webhook"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ storage.Backend = (*Storage)(nil)
+
+// Storage is a storage.Backend recording a span for each operation.
+type Storage struct {
+	next    storage.Backend
+	backend string
+	tracer  trace.Tracer
+}
+
+// InstrumentStorage wraps the storage.Backend, backend names the `db.system` of the spans.
+func InstrumentStorage(next storage.Backend, backend string) *Storage {
+	return &Storage{next: next, backend: backend, tracer: otel.Tracer(instrumentationName)}
+}
+
+// start starts the span of an operation.
+func (s *Storage) start(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attribute.String("db.system", s.backend), attribute.String("db.operation", operation))
+	return s.tracer.Start(ctx, "storage."+operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// end ends the span, recording the error (if any).
+// NOTE: a lookup that finds nothing isn't an error of the storage.
+func end(span trace.Span, err error) {
+	if err != nil && !storage.IsNotFound(err) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+func (s *Storage) Drone(ctx context.Context, tenantID, serial string) (d drone.Drone, err error) {
+	ctx, span := s.start(ctx, "drone", attribute.String("drone.tenant_id", tenantID), attribute.String("drone.serial", serial))
+	defer func() { end(span, err) }()
+	return s.next.Drone(ctx, tenantID, serial)
+}
+
+func (s *Storage) Drones(ctx context.Context, tenantID string) (drones []drone.Drone, err error) {
+	ctx, span := s.start(ctx, "drones", attribute.String("drone.tenant_id", tenantID))
+	defer func() { end(span, err) }()
+	return s.next.Drones(ctx, tenantID)
+}
+
+func (s *Storage) AllDrones(ctx context.Context) (drones []drone.Drone, err error) {
+	ctx, span := s.start(ctx, "all_drones")
+	defer func() { end(span, err) }()
+	return s.next.AllDrones(ctx)
+}
+
+func (s *Storage) SaveDrone(ctx context.Context, d drone.Drone) (err error) {
+	ctx, span := s.start(ctx, "save_drone", attribute.String("drone.tenant_id", d.TenantID), attribute.String("drone.serial", d.Serial))
+	defer func() { end(span, err) }()
+	return s.next.SaveDrone(ctx, d)
+}
+
+func (s *Storage) PendingEvents(ctx context.Context, limit int) (events []drone.Event, err error) {
+	ctx, span := s.start(ctx, "pending_events")
+	defer func() { end(span, err) }()
+	return s.next.PendingEvents(ctx, limit)
+}
+
+func (s *Storage) MarkDispatched(ctx context.Context, ids ...string) (err error) {
+	ctx, span := s.start(ctx, "mark_dispatched")
+	defer func() { end(span, err) }()
+	return s.next.MarkDispatched(ctx, ids...)
+}
+
+func (s *Storage) SaveSubscription(ctx context.Context, sub webhook.Subscription) (err error) {
+	ctx, span := s.start(ctx, "save_subscription")
+	defer func() { end(span, err) }()
+	return s.next.SaveSubscription(ctx, sub)
+}
+
+func (s *Storage) Subscriptions(ctx context.Context, tenantID string) (subs []webhook.Subscription, err error) {
+	ctx, span := s.start(ctx, "subscriptions", attribute.String("drone.tenant_id", tenantID))
+	defer func() { end(span, err) }()
+	return s.next.Subscriptions(ctx, tenantID)
+}
+
+func (s *Storage) DeleteSubscription(ctx context.Context, tenantID, id string) (err error) {
+	ctx, span := s.start(ctx, "delete_subscription", attribute.String("drone.tenant_id", tenantID))
+	defer func() { end(span, err) }()
+	return s.next.DeleteSubscription(ctx, tenantID, id)
+}
+
+func (s *Storage) AppendDelivery(ctx context.Context, d webhook.Delivery) (err error) {
+	ctx, span := s.start(ctx, "append_delivery")
+	defer func() { end(span, err) }()
+	return s.next.AppendDelivery(ctx, d)
+}
+
+func (s *Storage) Deliveries(ctx context.Context, tenantID, subscriptionID string) (deliveries []webhook.Delivery, err error) {
+	ctx, span := s.start(ctx, "deliveries", attribute.String("drone.tenant_id", tenantID))
+	defer func() { end(span, err) }()
+	return s.next.Deliveries(ctx, tenantID, subscriptionID)
+}
+
+func (s *Storage) AppendDeadLetter(ctx context.Context, dl webhook.DeadLetter) (err error) {
+	ctx, span := s.start(ctx, "append_dead_letter")
+	defer func() { end(span, err) }()
+	return s.next.AppendDeadLetter(ctx, dl)
+}
+
+func (s *Storage) DeadLetters(ctx context.Context, tenantID string) (dls []webhook.DeadLetter, err error) {
+	ctx, span := s.start(ctx, "dead_letters", attribute.String("drone.tenant_id", tenantID))
+	defer func() { end(span, err) }()
+	return s.next.DeadLetters(ctx, tenantID)
+}
+
+func (s *Storage) SavePendingDelivery(ctx context.Context, p webhook.PendingDelivery) (err error) {
+	ctx, span := s.start(ctx, "save_pending_delivery", attribute.String("drone.tenant_id", p.TenantID))
+	defer func() { end(span, err) }()
+	return s.next.SavePendingDelivery(ctx, p)
+}
+
+func (s *Storage) PendingDeliveries(ctx context.Context) (pending []webhook.PendingDelivery, err error) {
+	ctx, span := s.start(ctx, "pending_deliveries")
+	defer func() { end(span, err) }()
+	return s.next.PendingDeliveries(ctx)
+}
+
+func (s *Storage) DeletePendingDelivery(ctx context.Context, id string) (err error) {
+	ctx, span := s.start(ctx, "delete_pending_delivery")
+	defer func() { end(span, err) }()
+	return s.next.DeletePendingDelivery(ctx, id)
+}
+
+func (s *Storage) SaveGeofence(ctx context.Context, g drone.Geofence) (err error) {
+	ctx, span := s.start(ctx, "save_geofence", attribute.String("drone.tenant_id", g.TenantID))
+	defer func() { end(span, err) }()
+	return s.next.SaveGeofence(ctx, g)
+}
+
+func (s *Storage) Geofences(ctx context.Context, tenantID string) (zones []drone.Geofence, err error) {
+	ctx, span := s.start(ctx, "geofences", attribute.String("drone.tenant_id", tenantID))
+	defer func() { end(span, err) }()
+	return s.next.Geofences(ctx, tenantID)
+}
+
+func (s *Storage) DeleteGeofence(ctx context.Context, tenantID, id string) (err error) {
+	ctx, span := s.start(ctx, "delete_geofence", attribute.String("drone.tenant_id", tenantID))
+	defer func() { end(span, err) }()
+	return s.next.DeleteGeofence(ctx, tenantID, id)
+}
+
+func (s *Storage) SaveDock(ctx context.Context, d drone.Dock) (err error) {
+	ctx, span := s.start(ctx, "save_dock", attribute.String("drone.tenant_id", d.TenantID))
+	defer func() { end(span, err) }()
+	return s.next.SaveDock(ctx, d)
+}
+
+func (s *Storage) Dock(ctx context.Context, tenantID, id string) (d drone.Dock, err error) {
+	ctx, span := s.start(ctx, "dock", attribute.String("drone.tenant_id", tenantID))
+	defer func() { end(span, err) }()
+	return s.next.Dock(ctx, tenantID, id)
+}
+
+func (s *Storage) Docks(ctx context.Context, tenantID string) (docks []drone.Dock, err error) {
+	ctx, span := s.start(ctx, "docks", attribute.String("drone.tenant_id", tenantID))
+	defer func() { end(span, err) }()
+	return s.next.Docks(ctx, tenantID)
+}
+
+func (s *Storage) DeleteDock(ctx context.Context, tenantID, id string) (err error) {
+	ctx, span := s.start(ctx, "delete_dock", attribute.String("drone.tenant_id", tenantID))
+	defer func() { end(span, err) }()
+	return s.next.DeleteDock(ctx, tenantID, id)
+}
+
+func (s *Storage) SaveChargingSession(ctx context.Context, cs drone.ChargingSession) (err error) {
+	ctx, span := s.start(ctx, "save_charging_session",
+		attribute.String("drone.tenant_id", cs.TenantID), attribute.String("drone.serial", cs.Serial))
+	defer func() { end(span, err) }()
+	return s.next.SaveChargingSession(ctx, cs)
+}
+
+func (s *Storage) ChargingSessions(ctx context.Context, tenantID, dockID string) (sessions []drone.ChargingSession, err error) {
+	ctx, span := s.start(ctx, "charging_sessions", attribute.String("drone.tenant_id", tenantID))
+	defer func() { end(span, err) }()
+	return s.next.ChargingSessions(ctx, tenantID, dockID)
+}
@@ -0,0 +1,100 @@
+// Package tracing instruments the service with OpenTelemetry spans.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// instrumentationName is the name of the tracers of the package.
+const instrumentationName = "github.com/hsequeda/drone/tracing"
+
+// Exporters supported by NewTracerProvider.
+const (
+	ExporterNone   = ""
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// Config defines where the spans are exported.
+type Config struct {
+	// ServiceName is the `service.name` of the spans.
+	ServiceName string
+	// Exporter is one of ExporterNone (spans are dropped), ExporterStdout or ExporterOTLP.
+	Exporter string
+	// Endpoint is the `host:port` of the OTLP/HTTP collector (empty uses the OTEL_EXPORTER_OTLP_* variables).
+	Endpoint string
+	// Insecure disables TLS with the OTLP collector.
+	Insecure bool
+	// Writer receives the spans of the stdout exporter (nil uses os.Stdout).
+	Writer io.Writer
+	// SpanExporter (optional) replaces the Exporter, the spans are exported synchronously (e.g. tracetest.InMemoryExporter).
+	SpanExporter sdktrace.SpanExporter
+}
+
+// NewTracerProvider builds the TracerProvider of the Config.
+func NewTracerProvider(ctx context.Context, config Config) (*sdktrace.TracerProvider, error) {
+	var opts []sdktrace.TracerProviderOption
+	switch {
+	case config.SpanExporter != nil:
+		opts = append(opts, sdktrace.WithSyncer(config.SpanExporter))
+	case config.Exporter == ExporterNone:
+	case config.Exporter == ExporterStdout:
+		w := config.Writer
+		if w == nil {
+			w = os.Stdout
+		}
+
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(w))
+		if err != nil {
+			return nil, fmt.Errorf("create stdout exporter: %w", err)
+		}
+
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	case config.Exporter == ExporterOTLP:
+		var clientOpts []otlptracehttp.Option
+		if config.Endpoint != "" {
+			clientOpts = append(clientOpts, otlptracehttp.WithEndpoint(config.Endpoint))
+		}
+
+		if config.Insecure {
+			clientOpts = append(clientOpts, otlptracehttp.WithInsecure())
+		}
+
+		exporter, err := otlptracehttp.New(ctx, clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("create otlp exporter: %w", err)
+		}
+
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	default:
+		return nil, fmt.Errorf("unknown exporter %q", config.Exporter)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(config.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("create resource: %w", err)
+	}
+
+	return sdktrace.NewTracerProvider(append(opts, sdktrace.WithResource(res))...), nil
+}
+
+// SetGlobal makes tp the global TracerProvider and W3C trace context (and
+// baggage) the global propagator.
+func SetGlobal(tp *sdktrace.TracerProvider) {
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+}
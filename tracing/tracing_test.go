@@ -0,0 +1,122 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/storage"
+	"github.com/hsequeda/drone/tracing"
+	"github.com/sdomino/scribble"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// setupExporter sets a global TracerProvider exporting to the returned in-memory exporter.
+func setupExporter(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	tp, err := tracing.NewTracerProvider(context.Background(), tracing.Config{ServiceName: "test", SpanExporter: exporter})
+	require.NoError(t, err)
+	tracing.SetGlobal(tp)
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	return exporter
+}
+
+func attributes(s tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range s.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+
+	return attrs
+}
+
+func TestMiddleware(t *testing.T) {
+	exporter := setupExporter(t)
+	r := chi.NewRouter()
+	r.Use(tracing.Middleware)
+	var handlerSpan trace.SpanContext
+	r.Get("/drone/{serial}", func(w http.ResponseWriter, r *http.Request) {
+		handlerSpan = trace.SpanContextFromContext(r.Context())
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/drone/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "GET /drone/{serial}", span.Name)
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent.SpanID().String())
+	assert.True(t, span.Parent.IsRemote())
+	assert.Equal(t, span.SpanContext.SpanID(), handlerSpan.SpanID())
+	assert.Equal(t, codes.Error, span.Status.Code)
+	attrs := attributes(span)
+	assert.Equal(t, "/drone/{serial}", attrs["http.route"].AsString())
+	assert.Equal(t, int64(http.StatusInternalServerError), attrs["http.status_code"].AsInt64())
+}
+
+func TestInstrumentStorage(t *testing.T) {
+	exporter := setupExporter(t)
+	st := tracing.InstrumentStorage(storage.NewInMemory(), "in_memory")
+	ctx := context.Background()
+
+	require.NoError(t, st.SaveDrone(ctx, drone.Drone{TenantID: "hospital-a", Serial: "1", State: drone.Idle}))
+	_, err := st.Drone(ctx, "hospital-a", "2")
+	require.ErrorIs(t, err, drone.ErrNotFound)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "storage.save_drone", spans[0].Name)
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind)
+	attrs := attributes(spans[0])
+	assert.Equal(t, "in_memory", attrs["db.system"].AsString())
+	assert.Equal(t, "hospital-a", attrs["drone.tenant_id"].AsString())
+	assert.Equal(t, "1", attrs["drone.serial"].AsString())
+	assert.Equal(t, codes.Unset, spans[0].Status.Code)
+
+	// a drone not found isn't an error of the storage
+	assert.Equal(t, "storage.drone", spans[1].Name)
+	assert.Equal(t, codes.Unset, spans[1].Status.Code)
+	assert.Empty(t, spans[1].Events)
+}
+
+func TestInstrumentStorageError(t *testing.T) {
+	exporter := setupExporter(t)
+	db, err := scribble.New(t.TempDir(), nil)
+	require.NoError(t, err)
+	st := tracing.InstrumentStorage(storage.NewJSON(db), "json")
+
+	require.Error(t, st.SaveDrone(context.Background(), drone.Drone{TenantID: "hospital-a", Serial: "../1"}))
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	require.Len(t, spans[0].Events, 1)
+	assert.Equal(t, "exception", spans[0].Events[0].Name)
+}
+
+func TestNewTracerProvider(t *testing.T) {
+	var buf strings.Builder
+	tp, err := tracing.NewTracerProvider(context.Background(), tracing.Config{ServiceName: "test", Exporter: tracing.ExporterStdout, Writer: &buf})
+	require.NoError(t, err)
+	_, span := tp.Tracer("test").Start(context.Background(), "stdout span")
+	span.End()
+	require.NoError(t, tp.Shutdown(context.Background()))
+	assert.Contains(t, buf.String(), `"Name":"stdout span"`)
+
+	_, err = tracing.NewTracerProvider(context.Background(), tracing.Config{Exporter: "zipkin"})
+	assert.Error(t, err)
+}
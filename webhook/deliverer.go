@@ -0,0 +1,349 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// MedicationPayload is the loaded medication sent in a Payload.
+type MedicationPayload struct {
+	Name   string `json:"name"`
+	Weight uint32 `json:"weight"`
+	Code   string `json:"code"`
+}
+
+// Payload is the JSON body sent to the Subscription endpoints.
+type Payload struct {
+	ID            string             `json:"id"`
+	Type          drone.EventType    `json:"type"`
+	Serial        string             `json:"serial"`
+	OccurredAt    time.Time          `json:"occurred_at"`
+	PreviousState drone.State        `json:"previous_state,omitempty"`
+	State         drone.State        `json:"state,omitempty"`
+	BatteryLevel  uint8              `json:"battery_level"`
+	Medication    *MedicationPayload `json:"medication,omitempty"`
+}
+
+// Config defines the delivery policy of a Deliverer.
+type Config struct {
+	// MaxAttempts is the amount of attempts before moving an event to the dead-letter list.
+	MaxAttempts int
+	// InitialBackoff is the wait after the first failed attempt, doubled on each retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts.
+	MaxBackoff time.Duration
+	// Workers is the amount of concurrent deliveries.
+	Workers int
+	// PollInterval is the interval between the scans of the pending deliveries
+	// (new events are picked up right away).
+	PollInterval time.Duration
+}
+
+// DefaultConfig is the delivery policy used for the zero values of Config.
+var DefaultConfig = Config{
+	MaxAttempts:    5,
+	InitialBackoff: time.Second,
+	MaxBackoff:     time.Minute,
+	Workers:        4,
+	PollInterval:   10 * time.Second,
+}
+
+// Deliverer sends the domain events to the Subscriptions of their tenant.
+type Deliverer struct {
+	store  Store
+	client *http.Client
+	config Config
+	wake   chan struct{}
+
+	mu       sync.Mutex
+	inFlight map[string]bool
+}
+
+// NewDeliverer builds a Deliverer. Subscribe its HandleEvent to a drone.Dispatcher and Run it.
+func NewDeliverer(store Store, client *http.Client, config Config) *Deliverer {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultConfig.MaxAttempts
+	}
+
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = DefaultConfig.InitialBackoff
+	}
+
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = DefaultConfig.MaxBackoff
+	}
+
+	if config.Workers <= 0 {
+		config.Workers = DefaultConfig.Workers
+	}
+
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultConfig.PollInterval
+	}
+
+	return &Deliverer{
+		store:    store,
+		client:   client,
+		config:   config,
+		wake:     make(chan struct{}, 1),
+		inFlight: make(map[string]bool),
+	}
+}
+
+// HandleEvent implements drone.EventHandler, persisting a PendingDelivery of
+// the event for every Subscription of its tenant registered for its type.
+// NOTE: the event is only acknowledged once they are persisted, so none is lost on a restart.
+func (d *Deliverer) HandleEvent(ctx context.Context, e drone.Event) error {
+	subs, err := d.store.Subscriptions(ctx, e.TenantID)
+	if err != nil {
+		return fmt.Errorf("fetch subscriptions: %w", err)
+	}
+
+	payload, err := json.Marshal(newPayload(e))
+	if err != nil {
+		return fmt.Errorf("encode payload: %w", err)
+	}
+
+	for _, s := range subs {
+		if !s.Accepts(e.Type) {
+			continue
+		}
+
+		if err := d.store.SavePendingDelivery(ctx, PendingDelivery{
+			ID:             e.ID + "-" + s.ID,
+			SubscriptionID: s.ID,
+			TenantID:       s.TenantID,
+			EventID:        e.ID,
+			EventType:      e.Type,
+			Payload:        payload,
+			NextAttemptAt:  time.Now().UTC(),
+		}); err != nil {
+			return fmt.Errorf("save pending delivery: %w", err)
+		}
+	}
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// Run delivers the pending deliveries until ctx is done. The ones interrupted
+// stay pending and are resumed by the next Run.
+func (d *Deliverer) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	workers := make(chan struct{}, d.config.Workers)
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		// NOTE: a failed scan is retried on the next tick.
+		pending, _ := d.store.PendingDeliveries(ctx)
+		claimed := d.claim(pending)
+		for i, p := range claimed {
+			select {
+			case workers <- struct{}{}:
+			case <-ctx.Done():
+				d.release(claimed[i:]...)
+				return
+			}
+
+			wg.Add(1)
+			go func(p PendingDelivery) {
+				defer wg.Done()
+				defer func() { <-workers }()
+				defer d.release(p)
+				d.deliver(ctx, p)
+			}(p)
+		}
+
+		select {
+		case <-d.wake:
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// claim marks the PendingDeliveries as being delivered, returning the ones
+// that weren't already.
+// NOTE: they are claimed right after the scan, as a delivery finished later is no longer pending.
+func (d *Deliverer) claim(pending []PendingDelivery) []PendingDelivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var claimed []PendingDelivery
+	for _, p := range pending {
+		if !d.inFlight[p.ID] {
+			d.inFlight[p.ID] = true
+			claimed = append(claimed, p)
+		}
+	}
+
+	return claimed
+}
+
+func (d *Deliverer) release(pending ...PendingDelivery) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, p := range pending {
+		delete(d.inFlight, p.ID)
+	}
+}
+
+// deliver sends the PendingDelivery retrying with exponential backoff, moving
+// it to the dead-letter list when every attempt fails. Each failed attempt is
+// persisted, so the retries survive a restart.
+func (d *Deliverer) deliver(ctx context.Context, p PendingDelivery) {
+	for p.Attempts < d.config.MaxAttempts {
+		if wait := time.Until(p.NextAttemptAt); wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		sub, err := d.subscription(ctx, p)
+		if errors.Is(err, ErrNotFound) {
+			// the subscription was deleted, nobody waits for the event anymore.
+			_ = d.store.DeletePendingDelivery(ctx, p.ID)
+			return
+		}
+
+		if err != nil {
+			return
+		}
+
+		statusCode, err := d.send(ctx, sub, p)
+		if ctx.Err() != nil {
+			// NOTE: the attempt interrupted by the shutdown isn't counted.
+			return
+		}
+
+		p.Attempts++
+		delivery := Delivery{
+			SubscriptionID: p.SubscriptionID,
+			TenantID:       p.TenantID,
+			EventID:        p.EventID,
+			EventType:      p.EventType,
+			Attempt:        p.Attempts,
+			StatusCode:     statusCode,
+			Succeeded:      err == nil,
+			At:             time.Now().UTC(),
+		}
+		if err != nil {
+			delivery.Error = err.Error()
+		}
+
+		// NOTE: the history is best-effort, a failure to record it doesn't stop the delivery.
+		_ = d.store.AppendDelivery(ctx, delivery)
+		if err == nil {
+			_ = d.store.DeletePendingDelivery(ctx, p.ID)
+			return
+		}
+
+		p.LastError = err.Error()
+		p.NextAttemptAt = time.Now().Add(d.backoff(p.Attempts)).UTC()
+		if p.Attempts < d.config.MaxAttempts {
+			if err := d.store.SavePendingDelivery(ctx, p); err != nil {
+				return
+			}
+		}
+	}
+
+	// NOTE: the PendingDelivery is kept until the dead letter is recorded.
+	if err := d.store.AppendDeadLetter(ctx, DeadLetter{
+		SubscriptionID: p.SubscriptionID,
+		TenantID:       p.TenantID,
+		EventID:        p.EventID,
+		EventType:      p.EventType,
+		Payload:        p.Payload,
+		Attempts:       p.Attempts,
+		LastError:      p.LastError,
+		At:             time.Now().UTC(),
+	}); err != nil {
+		return
+	}
+
+	_ = d.store.DeletePendingDelivery(ctx, p.ID)
+}
+
+// backoff returns the wait after the failed attempt.
+func (d *Deliverer) backoff(attempt int) time.Duration {
+	backoff := d.config.InitialBackoff
+	for i := 1; i < attempt && backoff < d.config.MaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, d.config.MaxBackoff)
+}
+
+// subscription returns the Subscription of the PendingDelivery.
+func (d *Deliverer) subscription(ctx context.Context, p PendingDelivery) (Subscription, error) {
+	subs, err := d.store.Subscriptions(ctx, p.TenantID)
+	if err != nil {
+		return Subscription{}, fmt.Errorf("fetch subscriptions: %w", err)
+	}
+
+	for _, s := range subs {
+		if s.ID == p.SubscriptionID {
+			return s, nil
+		}
+	}
+
+	return Subscription{}, ErrNotFound
+}
+
+// send POSTs the signed payload, any non 2xx status is a failed attempt.
+func (d *Deliverer) send(ctx context.Context, sub Subscription, p PendingDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(p.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("build request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, p.EventID)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(sub.Secret, timestamp, p.Payload))
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("send request: %w", err)
+	}
+
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+func newPayload(e drone.Event) Payload {
+	p := Payload{
+		ID:            e.ID,
+		Type:          e.Type,
+		Serial:        e.Serial,
+		OccurredAt:    e.OccurredAt,
+		PreviousState: e.PreviousState,
+		State:         e.State,
+		BatteryLevel:  e.BatteryLevel,
+	}
+	if e.Medication != nil {
+		p.Medication = &MedicationPayload{Name: e.Medication.Name, Weight: e.Medication.Weight, Code: e.Medication.Code}
+	}
+
+	return p
+}
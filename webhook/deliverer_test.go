@@ -0,0 +1,161 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/storage"
+	"github.com/hsequeda/drone/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliverer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	var (
+		received  = make(chan webhook.Payload, 10)
+		failFirst atomic.Int32
+	)
+	failFirst.Store(2)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !webhook.Verify("s3cr3t", body, r.Header.Get(webhook.TimestampHeader), r.Header.Get(webhook.SignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if failFirst.Add(-1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var p webhook.Payload
+		_ = json.Unmarshal(body, &p)
+		assert.Equal(t, p.ID, r.Header.Get(webhook.EventIDHeader))
+		received <- p
+	}))
+	t.Cleanup(receiver.Close)
+	deadReceiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(deadReceiver.Close)
+
+	st := storage.NewInMemory()
+	sub, err := webhook.NewSubscription(webhook.EgressPolicy{AllowPrivate: true, InsecureHosts: []string{"127.0.0.1"}}, "hospital-a", receiver.URL, "s3cr3t", []drone.EventType{drone.MedicationLoaded})
+	require.NoError(t, err)
+	require.NoError(t, st.SaveSubscription(ctx, sub))
+	deadSub, err := webhook.NewSubscription(webhook.EgressPolicy{AllowPrivate: true, InsecureHosts: []string{"127.0.0.1"}}, "hospital-a", deadReceiver.URL, "", []drone.EventType{drone.MedicationLoaded})
+	require.NoError(t, err)
+	require.NoError(t, st.SaveSubscription(ctx, deadSub))
+	otherTenantSub, err := webhook.NewSubscription(webhook.EgressPolicy{AllowPrivate: true, InsecureHosts: []string{"127.0.0.1"}}, "hospital-b", receiver.URL, "s3cr3t", []drone.EventType{drone.MedicationLoaded})
+	require.NoError(t, err)
+	require.NoError(t, st.SaveSubscription(ctx, otherTenantSub))
+
+	deliverer := webhook.NewDeliverer(st, receiver.Client(), webhook.Config{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	})
+
+	d, err := drone.NewDrone(drone.DefaultPolicy(), "hospital-a", "1", drone.Lightweight, 300, 80)
+	require.NoError(t, err)
+	require.NoError(t, d.AddMedications(drone.DefaultPolicy(), drone.Medication{Name: "Aspirin", Weight: 50, Code: "A01"}))
+	for _, e := range d.Events() {
+		require.NoError(t, deliverer.HandleEvent(ctx, e))
+	}
+
+	// the deliveries are persisted before the events are acknowledged
+	pending, err := st.PendingDeliveries(ctx)
+	require.NoError(t, err)
+	assert.Len(t, pending, 2)
+	go deliverer.Run(ctx)
+
+	// delivered on the third attempt
+	select {
+	case p := <-received:
+		assert.Equal(t, drone.MedicationLoaded, p.Type)
+		assert.Equal(t, "1", p.Serial)
+		require.NotNil(t, p.Medication)
+		assert.Equal(t, "A01", p.Medication.Code)
+	case <-time.After(5 * time.Second):
+		t.Fatal("event not delivered")
+	}
+
+	require.Eventually(t, func() bool {
+		deliveries, err := st.Deliveries(ctx, "hospital-a", sub.ID)
+		return err == nil && len(deliveries) == 3 && deliveries[2].Succeeded
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the dead receiver exhausts the attempts
+	require.Eventually(t, func() bool {
+		dls, err := st.DeadLetters(ctx, "hospital-a")
+		return err == nil && len(dls) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	dls, err := st.DeadLetters(ctx, "hospital-a")
+	require.NoError(t, err)
+	assert.Equal(t, deadSub.ID, dls[0].SubscriptionID)
+	assert.Equal(t, 3, dls[0].Attempts)
+	deliveries, err := st.Deliveries(ctx, "hospital-a", deadSub.ID)
+	require.NoError(t, err)
+	assert.Len(t, deliveries, 3)
+
+	// the subscription of the other tenant is never notified
+	deliveries, err = st.Deliveries(ctx, "hospital-b", otherTenantSub.ID)
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+
+	// nothing is left pending
+	require.Eventually(t, func() bool {
+		pending, err := st.PendingDeliveries(ctx)
+		return err == nil && len(pending) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestDelivererResumesPendingDeliveries(t *testing.T) {
+	received := make(chan string, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get(webhook.EventIDHeader)
+	}))
+	t.Cleanup(receiver.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	st := storage.NewInMemory()
+	sub, err := webhook.NewSubscription(webhook.EgressPolicy{AllowPrivate: true, InsecureHosts: []string{"127.0.0.1"}}, "hospital-a", receiver.URL, "", []drone.EventType{drone.DroneRegistered})
+	require.NoError(t, err)
+	require.NoError(t, st.SaveSubscription(ctx, sub))
+
+	// a delivery left pending by a previous run, with an attempt already failed
+	require.NoError(t, st.SavePendingDelivery(ctx, webhook.PendingDelivery{
+		ID:             "e1-" + sub.ID,
+		SubscriptionID: sub.ID,
+		TenantID:       "hospital-a",
+		EventID:        "e1",
+		EventType:      drone.DroneRegistered,
+		Payload:        []byte(`{"id":"e1"}`),
+		Attempts:       1,
+		NextAttemptAt:  time.Now(),
+	}))
+
+	go webhook.NewDeliverer(st, receiver.Client(), webhook.Config{}).Run(ctx)
+	select {
+	case id := <-received:
+		assert.Equal(t, "e1", id)
+	case <-time.After(5 * time.Second):
+		t.Fatal("pending delivery not resumed")
+	}
+
+	require.Eventually(t, func() bool {
+		deliveries, err := st.Deliveries(ctx, "hospital-a", sub.ID)
+		return err == nil && len(deliveries) == 1 && deliveries[0].Attempt == 2 && deliveries[0].Succeeded
+	}, 5*time.Second, 10*time.Millisecond)
+}
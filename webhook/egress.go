@@ -0,0 +1,90 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress error occurs when a Subscription endpoint resolves to a
+// loopback, link-local or private address.
+var ErrForbiddenAddress = errors.New("forbidden webhook address")
+
+// EgressPolicy restricts the endpoints the Subscriptions can point to.
+type EgressPolicy struct {
+	// InsecureHosts are the hosts allowed over plain http, every other one needs https.
+	InsecureHosts []string
+	// AllowPrivate allows the loopback, link-local and private addresses.
+	// NOTE: only meant for local setups, a tenant could reach the internal network otherwise.
+	AllowPrivate bool
+}
+
+// CheckURL returns an error if the raw URL can't be used as a Subscription endpoint.
+func (p EgressPolicy) CheckURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+
+	if u.Hostname() == "" {
+		return errors.New("url has no host")
+	}
+
+	switch u.Scheme {
+	case "https":
+	case "http":
+		if !slices.Contains(p.InsecureHosts, u.Hostname()) {
+			return errors.New("url needs to use https")
+		}
+	default:
+		return fmt.Errorf("unsupported url scheme %q", u.Scheme)
+	}
+
+	// NOTE: the names are checked when dialed, as they can resolve to another address later.
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !p.allowed(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, ip)
+	}
+
+	return nil
+}
+
+// Client returns an http.Client that refuses to connect to the addresses
+// forbidden by the policy, whatever name or redirect led to them.
+func (p EgressPolicy) Client(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			if ip := net.ParseIP(host); ip == nil || !p.allowed(ip) {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+			}
+
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// NOTE: a proxy would be the dialed address, hiding the real endpoint from the check.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+func (p EgressPolicy) allowed(ip net.IP) bool {
+	if p.AllowPrivate {
+		return true
+	}
+
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast()
+}
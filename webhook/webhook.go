@@ -0,0 +1,211 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+)
+
+// SignatureHeader is the header carrying the HMAC signature of the timestamp and the payload.
+const SignatureHeader = "X-Drone-Signature"
+
+// TimestampHeader is the header carrying the unix time the delivery was signed at.
+const TimestampHeader = "X-Drone-Timestamp"
+
+// SignatureTolerance is the max age of a signature accepted by Verify, older
+// deliveries are rejected so a captured one can't be replayed.
+const SignatureTolerance = 5 * time.Minute
+
+// EventIDHeader is the header carrying the ID of the delivered event.
+// NOTE: events are delivered at least once, receivers can use it to discard duplicates.
+const EventIDHeader = "X-Drone-Event-ID"
+
+var (
+	ErrNotFound = errors.New("webhook not found")
+	// ErrUnsupportedEvent error occurs when a Subscription is created for an unknown event type.
+	ErrUnsupportedEvent = errors.New("unsupported event type")
+)
+
+// SupportedEvents are the event types a Subscription can be registered for.
+var SupportedEvents = []drone.EventType{
+	drone.DroneRegistered,
+	drone.MedicationLoaded,
+	drone.MedicationUnloaded,
+	drone.StateChanged,
+	drone.BatteryLow,
+	drone.DroneGrounded,
+	drone.DroneReturnedToService,
+}
+
+// Subscription is an endpoint of a tenant notified about fleet events.
+type Subscription struct {
+	ID         string
+	TenantID   string
+	URL        string
+	Secret     string
+	EventTypes []drone.EventType
+	CreatedAt  time.Time
+}
+
+// NewSubscription builds a new Subscription, its url needs to be allowed by the
+// EgressPolicy. If secret is empty a random one is generated.
+func NewSubscription(policy EgressPolicy, tenantID, url, secret string, eventTypes []drone.EventType) (Subscription, error) {
+	if tenantID == "" {
+		return Subscription{}, errors.New("tenant id is empty")
+	}
+
+	if url == "" {
+		return Subscription{}, errors.New("url is empty")
+	}
+
+	if err := policy.CheckURL(url); err != nil {
+		return Subscription{}, err
+	}
+
+	if len(eventTypes) == 0 {
+		return Subscription{}, errors.New("event types are empty")
+	}
+
+	for _, t := range eventTypes {
+		if !isSupported(t) {
+			return Subscription{}, ErrUnsupportedEvent
+		}
+	}
+
+	if secret == "" {
+		secret = randomHex(32)
+	}
+
+	return Subscription{
+		ID:         randomHex(16),
+		TenantID:   tenantID,
+		URL:        url,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		CreatedAt:  time.Now().UTC(),
+	}, nil
+}
+
+// Accepts returns if the Subscription is registered for the event type.
+func (s Subscription) Accepts(t drone.EventType) bool {
+	for _, et := range s.EventTypes {
+		if et == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Delivery is an attempt to deliver an event to a Subscription.
+type Delivery struct {
+	SubscriptionID string
+	TenantID       string
+	EventID        string
+	EventType      drone.EventType
+	Attempt        int
+	StatusCode     int
+	Error          string
+	Succeeded      bool
+	At             time.Time
+}
+
+// DeadLetter is an event that couldn't be delivered after all the attempts.
+type DeadLetter struct {
+	SubscriptionID string
+	TenantID       string
+	EventID        string
+	EventType      drone.EventType
+	Payload        []byte
+	Attempts       int
+	LastError      string
+	At             time.Time
+}
+
+// PendingDelivery is an event waiting to be delivered to a Subscription.
+// NOTE: it's persisted before the event is acknowledged, so a restart resumes the deliveries.
+type PendingDelivery struct {
+	// ID is unique for each event and Subscription.
+	ID             string
+	SubscriptionID string
+	TenantID       string
+	EventID        string
+	EventType      drone.EventType
+	Payload        []byte
+	// Attempts are the failed attempts so far.
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+}
+
+// Store persists the Subscriptions and the history of their deliveries.
+type Store interface {
+	// SaveSubscription persists a Subscription.
+	SaveSubscription(ctx context.Context, s Subscription) error
+	// Subscriptions returns the Subscriptions of the tenant.
+	Subscriptions(ctx context.Context, tenantID string) ([]Subscription, error)
+	// DeleteSubscription removes a Subscription of the tenant.
+	// NOTE: Returns NotFound error if id doesn't match inside the tenant.
+	DeleteSubscription(ctx context.Context, tenantID, id string) error
+	// AppendDelivery appends a Delivery to the history of its Subscription.
+	AppendDelivery(ctx context.Context, d Delivery) error
+	// Deliveries returns the history of deliveries of a Subscription, oldest first.
+	Deliveries(ctx context.Context, tenantID, subscriptionID string) ([]Delivery, error)
+	// AppendDeadLetter appends an undeliverable event to the dead-letter list of the tenant.
+	AppendDeadLetter(ctx context.Context, dl DeadLetter) error
+	// DeadLetters returns the dead-letter list of the tenant, oldest first.
+	DeadLetters(ctx context.Context, tenantID string) ([]DeadLetter, error)
+	// SavePendingDelivery persists a PendingDelivery, replacing the one with its ID.
+	SavePendingDelivery(ctx context.Context, p PendingDelivery) error
+	// PendingDeliveries returns the PendingDeliveries of every tenant.
+	PendingDeliveries(ctx context.Context) ([]PendingDelivery, error)
+	// DeletePendingDelivery removes a PendingDelivery, it doesn't fail if it doesn't exist.
+	DeletePendingDelivery(ctx context.Context, id string) error
+}
+
+// Sign returns the value of the SignatureHeader for the payload signed at the
+// timestamp (the value of the TimestampHeader).
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is a valid SignatureHeader value for the
+// payload and timestamp, and the timestamp is within the SignatureTolerance.
+func Verify(secret string, payload []byte, timestamp, signature string) bool {
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	if age := time.Since(time.Unix(unix, 0)); age > SignatureTolerance || age < -SignatureTolerance {
+		return false
+	}
+
+	return hmac.Equal([]byte(Sign(secret, timestamp, payload)), []byte(signature))
+}
+
+func isSupported(t drone.EventType) bool {
+	for _, st := range SupportedEvents {
+		if st == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
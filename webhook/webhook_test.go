@@ -0,0 +1,165 @@
+package webhook_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hsequeda/drone/drone"
+	"github.com/hsequeda/drone/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSubscription(t *testing.T) {
+	testCases := []struct {
+		name        string
+		expectedErr bool
+
+		policy     webhook.EgressPolicy
+		tenantID   string
+		url        string
+		secret     string
+		eventTypes []drone.EventType
+	}{
+		{
+			name:       "OK: with secret",
+			tenantID:   "hospital-a",
+			url:        "https://hospital-a.example/hook",
+			secret:     "s3cr3t",
+			eventTypes: []drone.EventType{drone.MedicationLoaded, drone.BatteryLow},
+		},
+		{
+			name:       "OK: generated secret",
+			tenantID:   "hospital-a",
+			url:        "https://hospital-a.example/hook",
+			eventTypes: []drone.EventType{drone.StateChanged},
+		},
+		{
+			name:       "OK: http allowed for the host",
+			policy:     webhook.EgressPolicy{InsecureHosts: []string{"hospital-a.local"}},
+			tenantID:   "hospital-a",
+			url:        "http://hospital-a.local/hook",
+			eventTypes: []drone.EventType{drone.StateChanged},
+		},
+		{
+			name:        "Err: http not allowed for the host",
+			expectedErr: true,
+			tenantID:    "hospital-a",
+			url:         "http://hospital-a.local/hook",
+			eventTypes:  []drone.EventType{drone.StateChanged},
+		},
+		{
+			name:        "Err: unsupported scheme",
+			expectedErr: true,
+			tenantID:    "hospital-a",
+			url:         "file:///etc/passwd",
+			eventTypes:  []drone.EventType{drone.StateChanged},
+		},
+		{
+			name:        "Err: 'url has no host'",
+			expectedErr: true,
+			tenantID:    "hospital-a",
+			url:         "https:///hook",
+			eventTypes:  []drone.EventType{drone.StateChanged},
+		},
+		{
+			name:        "Err: loopback address",
+			expectedErr: true,
+			tenantID:    "hospital-a",
+			url:         "https://127.0.0.1:8080/hook",
+			eventTypes:  []drone.EventType{drone.StateChanged},
+		},
+		{
+			name:        "Err: link-local address",
+			expectedErr: true,
+			tenantID:    "hospital-a",
+			url:         "https://169.254.169.254/latest/meta-data",
+			eventTypes:  []drone.EventType{drone.StateChanged},
+		},
+		{
+			name:        "Err: private address",
+			expectedErr: true,
+			tenantID:    "hospital-a",
+			url:         "https://10.0.0.7/hook",
+			eventTypes:  []drone.EventType{drone.StateChanged},
+		},
+		{
+			name:       "OK: private address allowed",
+			policy:     webhook.EgressPolicy{AllowPrivate: true},
+			tenantID:   "hospital-a",
+			url:        "https://10.0.0.7/hook",
+			eventTypes: []drone.EventType{drone.StateChanged},
+		},
+		{
+			name:        "Err: 'url is empty'",
+			expectedErr: true,
+			tenantID:    "hospital-a",
+			eventTypes:  []drone.EventType{drone.StateChanged},
+		},
+		{
+			name:        "Err: 'event types are empty'",
+			expectedErr: true,
+			tenantID:    "hospital-a",
+			url:         "https://hospital-a.example/hook",
+		},
+		{
+			name:        "Err: unsupported event type",
+			expectedErr: true,
+			tenantID:    "hospital-a",
+			url:         "https://hospital-a.example/hook",
+			eventTypes:  []drone.EventType{"drone.exploded"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := webhook.NewSubscription(tc.policy, tc.tenantID, tc.url, tc.secret, tc.eventTypes)
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEmpty(t, s.ID)
+			assert.NotEmpty(t, s.Secret)
+			if tc.secret != "" {
+				assert.Equal(t, tc.secret, s.Secret)
+			}
+			for _, et := range tc.eventTypes {
+				assert.True(t, s.Accepts(et))
+			}
+			assert.False(t, s.Accepts(drone.DroneRegistered))
+		})
+	}
+}
+
+func TestSignature(t *testing.T) {
+	payload := []byte(`{"id":"1"}`)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := webhook.Sign("s3cr3t", timestamp, payload)
+	assert.True(t, webhook.Verify("s3cr3t", payload, timestamp, signature))
+	assert.False(t, webhook.Verify("another", payload, timestamp, signature))
+	assert.False(t, webhook.Verify("s3cr3t", []byte(`{"id":"2"}`), timestamp, signature))
+	assert.False(t, webhook.Verify("s3cr3t", payload, "1", signature))
+
+	// a captured delivery can't be replayed once the tolerance is over
+	stale := strconv.FormatInt(time.Now().Add(-webhook.SignatureTolerance-time.Minute).Unix(), 10)
+	assert.False(t, webhook.Verify("s3cr3t", payload, stale, webhook.Sign("s3cr3t", stale, payload)))
+}
+
+func TestEgressPolicyClient(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(receiver.Close)
+
+	// NOTE: the address is checked when dialed, whatever the url says.
+	_, err := webhook.EgressPolicy{}.Client(time.Second).Get(receiver.URL)
+	require.ErrorIs(t, err, webhook.ErrForbiddenAddress)
+
+	resp, err := webhook.EgressPolicy{AllowPrivate: true}.Client(time.Second).Get(receiver.URL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}